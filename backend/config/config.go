@@ -35,6 +35,25 @@ type Config struct {
 	// CORS / Frontend
 	FrontendURL    string
 	AllowedOrigins []string
+
+	// Waitlist
+	WaitlistOfferTTL time.Duration
+
+	// No-show detection
+	NoShowGracePeriod   time.Duration
+	NoShowCheckInterval time.Duration // 0 disables the job
+
+	// Public booking
+	PublicRateLimit int // requests per minute per client IP and endpoint
+
+	// Reminders and notification channels
+	ReminderCheckInterval time.Duration // 0 disables the job
+	ReminderMaxAttempts   int
+	SMSGatewayURL         string
+	SMSGatewayToken       string
+	SMSSender             string
+	TelegramBotToken      string
+	ResendAPIKey          string
 }
 
 func Load() *Config {
@@ -59,6 +78,21 @@ func Load() *Config {
 		SuperadminPassword: getEnv("SUPERADMIN_PASSWORD", ""),
 
 		FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+
+		WaitlistOfferTTL: getDurationEnv("WAITLIST_OFFER_TTL", 2*time.Hour),
+
+		NoShowGracePeriod:   getDurationEnv("NO_SHOW_GRACE_PERIOD", 30*time.Minute),
+		NoShowCheckInterval: getDurationEnv("NO_SHOW_CHECK_INTERVAL", 5*time.Minute),
+
+		PublicRateLimit: getIntEnv("PUBLIC_RATE_LIMIT", 30),
+
+		ReminderCheckInterval: getDurationEnv("REMINDER_CHECK_INTERVAL", 5*time.Minute),
+		ReminderMaxAttempts:   getIntEnv("REMINDER_MAX_ATTEMPTS", 3),
+		SMSGatewayURL:         getEnv("SMS_GATEWAY_URL", ""),
+		SMSGatewayToken:       getEnv("SMS_GATEWAY_TOKEN", ""),
+		SMSSender:             getEnv("SMS_SENDER", ""),
+		TelegramBotToken:      getEnv("TELEGRAM_BOT_TOKEN", ""),
+		ResendAPIKey:          getEnv("RESEND_API_KEY", ""),
 	}
 
 	// Build allowed origins list
@@ -161,3 +195,12 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
@@ -10,6 +10,7 @@ require (
 	github.com/rs/zerolog v1.31.0
 	go.mongodb.org/mongo-driver v1.13.1
 	golang.org/x/crypto v0.17.0
+	golang.org/x/image v0.14.0
 )
 
 require (
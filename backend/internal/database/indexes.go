@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"medical-crm/internal/models"
 	"medical-crm/pkg/logger"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -18,6 +19,8 @@ type IndexDefinition struct {
 	Unique     bool
 	Name       string
 	Sparse     bool
+	Partial    bson.M // Optional partialFilterExpression
+	TTL        bool   // Expire documents at the time stored in the (single) key field
 }
 
 // GetIndexes returns all required indexes for the application
@@ -30,6 +33,13 @@ func GetIndexes() []IndexDefinition {
 			Unique:     true,
 			Name:       "idx_clinics_name_unique",
 		},
+		{
+			Collection: "clinics",
+			Keys:       bson.D{{Key: "slug", Value: 1}},
+			Unique:     true,
+			Sparse:     true,
+			Name:       "idx_clinics_slug_unique",
+		},
 
 		// Users - phone-based authentication
 		{
@@ -90,7 +100,12 @@ func GetIndexes() []IndexDefinition {
 			Collection: "appointments",
 			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "doctor_id", Value: 1}, {Key: "start_time", Value: 1}},
 			Unique:     true,
-			Name:       "idx_appointments_clinic_doctor_time_unique",
+			Name:       "idx_appointments_clinic_doctor_start_unique",
+			// Cancelled and no-show appointments free their start time for rebooking
+			Partial: bson.M{"status": bson.M{"$in": []string{
+				models.AppointmentStatusPending, models.AppointmentStatusScheduled, models.AppointmentStatusConfirmed,
+				models.AppointmentStatusInProgress, models.AppointmentStatusCompleted,
+			}}},
 		},
 		{
 			Collection: "appointments",
@@ -104,6 +119,18 @@ func GetIndexes() []IndexDefinition {
 			Unique:     false,
 			Name:       "idx_appointments_clinic_patient",
 		},
+		{
+			Collection: "appointments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "status", Value: 1}, {Key: "end_time", Value: 1}},
+			Unique:     false,
+			Name:       "idx_appointments_clinic_status_end",
+		},
+		{
+			Collection: "appointments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "status", Value: 1}, {Key: "start_time", Value: 1}},
+			Unique:     false,
+			Name:       "idx_appointments_clinic_status_start",
+		},
 		{
 			Collection: "appointments",
 			Keys:       bson.D{{Key: "doctor_id", Value: 1}, {Key: "date", Value: 1}},
@@ -117,6 +144,13 @@ func GetIndexes() []IndexDefinition {
 			Unique:     false,
 			Name:       "idx_appointments_clinic_start_time_doctor",
 		},
+		{
+			Collection: "appointments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "series_id", Value: 1}, {Key: "start_time", Value: 1}},
+			Unique:     false,
+			Sparse:     true,
+			Name:       "idx_appointments_clinic_series_start",
+		},
 
 		// Services
 		{
@@ -131,6 +165,18 @@ func GetIndexes() []IndexDefinition {
 			Unique:     true,
 			Name:       "idx_services_clinic_name_unique",
 		},
+		{
+			Collection: "service_prices",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "service_id", Value: 1}, {Key: "effective_from", Value: 1}},
+			Unique:     true,
+			Name:       "idx_service_prices_clinic_service_effective_unique",
+		},
+		{
+			Collection: "service_prices",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "effective_from", Value: 1}},
+			Unique:     false,
+			Name:       "idx_service_prices_clinic_effective",
+		},
 
 		// Visits
 		{
@@ -158,6 +204,231 @@ func GetIndexes() []IndexDefinition {
 			Sparse:     true,
 			Name:       "idx_visits_appointment_unique",
 		},
+
+		// Clinic closures and doctor leaves
+		{
+			Collection: "time_off",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "start_time", Value: 1}, {Key: "end_time", Value: 1}},
+			Unique:     false,
+			Name:       "idx_time_off_clinic_interval",
+		},
+
+		// Online booking phone verification codes
+		{
+			Collection: "phone_verifications",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "phone", Value: 1}, {Key: "created_at", Value: -1}},
+			Unique:     false,
+			Name:       "idx_phone_verifications_clinic_phone",
+		},
+		{
+			Collection: "phone_verifications",
+			Keys:       bson.D{{Key: "expires_at", Value: 1}},
+			TTL:        true,
+			Name:       "idx_phone_verifications_ttl",
+		},
+
+		// Appointment reminders - unique key makes each reminder single-send
+		{
+			Collection: "reminder_deliveries",
+			Keys:       bson.D{{Key: "appointment_id", Value: 1}, {Key: "appointment_start", Value: 1}, {Key: "hours_before", Value: 1}},
+			Unique:     true,
+			Name:       "idx_reminder_deliveries_appointment_unique",
+		},
+		{
+			Collection: "reminder_deliveries",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "appointment_date", Value: 1}, {Key: "status", Value: 1}},
+			Unique:     false,
+			Name:       "idx_reminder_deliveries_clinic_date",
+		},
+		{
+			Collection: "reminder_deliveries",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			Unique:     false,
+			Name:       "idx_reminder_deliveries_retry",
+		},
+
+		// Payments ledger
+		{
+			Collection: "payments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "date", Value: 1}},
+			Unique:     false,
+			Name:       "idx_payments_clinic_date",
+		},
+		{
+			Collection: "payments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "visit_id", Value: 1}},
+			Unique:     false,
+			Name:       "idx_payments_clinic_visit",
+		},
+		{
+			Collection: "payments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "patient_id", Value: 1}, {Key: "paid_at", Value: -1}},
+			Unique:     false,
+			Name:       "idx_payments_clinic_patient",
+		},
+		{
+			Collection: "payments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "receipt_number", Value: 1}},
+			Unique:     true,
+			Name:       "idx_payments_clinic_receipt_unique",
+			Partial:    bson.M{"receipt_number": bson.M{"$exists": true}},
+		},
+		{
+			Collection: "visits",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "invoice_number", Value: 1}},
+			Unique:     true,
+			Name:       "idx_visits_clinic_invoice_unique",
+			Partial:    bson.M{"invoice_number": bson.M{"$exists": true}},
+		},
+
+		// Visit voids and refunds
+		{
+			Collection: "visit_adjustments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "visit_id", Value: 1}},
+			Unique:     false,
+			Name:       "idx_visit_adjustments_clinic_visit",
+		},
+		{
+			Collection: "visit_adjustments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "date", Value: 1}},
+			Unique:     false,
+			Name:       "idx_visit_adjustments_clinic_date",
+		},
+
+		// Cash register shifts
+		{
+			Collection: "cash_shifts",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "cashier_id", Value: 1}},
+			Unique:     true,
+			Name:       "idx_cash_shifts_clinic_cashier_open_unique",
+			// One open shift per cashier; closed shifts are history
+			Partial: bson.M{"status": models.CashShiftStatusOpen},
+		},
+		{
+			Collection: "cash_shifts",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "date", Value: 1}},
+			Unique:     false,
+			Name:       "idx_cash_shifts_clinic_date",
+		},
+		{
+			Collection: "payments",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "shift_id", Value: 1}},
+			Unique:     false,
+			Name:       "idx_payments_clinic_shift",
+			Partial:    bson.M{"shift_id": bson.M{"$exists": true}},
+		},
+
+		// Doctor payout statements
+		{
+			Collection: "payout_statements",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "doctor_id", Value: 1}, {Key: "period_from", Value: 1}},
+			Unique:     true,
+			Name:       "idx_payout_statements_clinic_doctor_period_unique",
+		},
+
+		// Insurance payers and claims
+		{
+			Collection: "insurance_payers",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "name", Value: 1}},
+			Unique:     true,
+			Name:       "idx_insurance_payers_clinic_name_unique",
+		},
+		{
+			Collection: "claim_batches",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "number", Value: 1}},
+			Unique:     true,
+			Name:       "idx_claim_batches_clinic_number_unique",
+		},
+		{
+			Collection: "claim_batches",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "payer_id", Value: 1}, {Key: "status", Value: 1}},
+			Unique:     false,
+			Name:       "idx_claim_batches_clinic_payer_status",
+		},
+		{
+			Collection: "visits",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "claim.payer_id", Value: 1}, {Key: "claim.status", Value: 1}},
+			Unique:     false,
+			Name:       "idx_visits_clinic_claim_payer_status",
+		},
+		{
+			Collection: "visits",
+			Keys:       bson.D{{Key: "claim.batch_id", Value: 1}},
+			Unique:     false,
+			Name:       "idx_visits_claim_batch",
+		},
+
+		// Inventory
+		{
+			Collection: "inventory_items",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "name", Value: 1}},
+			Unique:     true,
+			Name:       "idx_inventory_items_clinic_name_unique",
+		},
+		{
+			Collection: "stock_movements",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "item_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Unique:     false,
+			Name:       "idx_stock_movements_clinic_item_created",
+		},
+		{
+			Collection: "stock_movements",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "visit_id", Value: 1}},
+			Unique:     false,
+			Name:       "idx_stock_movements_clinic_visit",
+		},
+
+		// Tooth chart timeline
+		{
+			Collection: "tooth_chart_entries",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "patient_id", Value: 1}, {Key: "date", Value: 1}, {Key: "created_at", Value: 1}},
+			Unique:     false,
+			Name:       "idx_tooth_chart_entries_clinic_patient_date",
+		},
+		{
+			Collection: "tooth_chart_entries",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "visit_id", Value: 1}},
+			Unique:     false,
+			Name:       "idx_tooth_chart_entries_clinic_visit",
+		},
+		{
+			Collection: "visits",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "patient_id", Value: 1}, {Key: "services.teeth", Value: 1}},
+			Unique:     false,
+			Name:       "idx_visits_clinic_patient_teeth",
+		},
+
+		// Medical history versions
+		{
+			Collection: "medical_history_versions",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "patient_id", Value: 1}, {Key: "history.version", Value: -1}},
+			Unique:     true,
+			Name:       "idx_medical_history_versions_clinic_patient_version",
+		},
+
+		// Per-clinic document number sequences
+		{
+			Collection: "counters",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "name", Value: 1}},
+			Unique:     true,
+			Name:       "idx_counters_clinic_name_unique",
+		},
+
+		// Named locks - expired leases left by crashed holders are cleaned up
+		{
+			Collection: "locks",
+			Keys:       bson.D{{Key: "expires_at", Value: 1}},
+			TTL:        true,
+			Name:       "idx_locks_ttl",
+		},
+
+		// Waitlist
+		{
+			Collection: "waitlist",
+			Keys:       bson.D{{Key: "clinic_id", Value: 1}, {Key: "status", Value: 1}, {Key: "from_date", Value: 1}, {Key: "to_date", Value: 1}},
+			Unique:     false,
+			Name:       "idx_waitlist_clinic_status_dates",
+		},
 	}
 }
 
@@ -171,7 +442,8 @@ func DropDeprecatedIndexes(db *mongo.Database, log *logger.Logger) {
 		Collection string
 		IndexName  string
 	}{
-		{"users", "idx_users_email_unique"}, // Replaced with phone-based auth
+		{"users", "idx_users_email_unique"},                            // Replaced with phone-based auth
+		{"appointments", "idx_appointments_clinic_doctor_time_unique"}, // Replaced with the active-only partial index
 	}
 
 	for _, idx := range deprecatedIndexes {
@@ -209,6 +481,12 @@ func CreateIndexes(db *mongo.Database, log *logger.Logger) error {
 		if idx.Sparse {
 			indexModel.Options.SetSparse(true)
 		}
+		if idx.Partial != nil {
+			indexModel.Options.SetPartialFilterExpression(idx.Partial)
+		}
+		if idx.TTL {
+			indexModel.Options.SetExpireAfterSeconds(0)
+		}
 
 		_, err := collection.Indexes().CreateOne(ctx, indexModel)
 		if err != nil {
@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/pkg/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RecomputeLocalDates rewrites the stored `date` of appointments and visits
+// using each clinic's time zone. Appointments are bucketed by start_time and
+// visits by created_at. The migration is idempotent.
+func RecomputeLocalDates(ctx context.Context, db *mongo.Database, log *logger.Logger) error {
+	cursor, err := db.Collection("clinics").Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+
+	var clinics []models.Clinic
+	if err := cursor.All(ctx, &clinics); err != nil {
+		return err
+	}
+
+	for _, clinic := range clinics {
+		loc := clinic.Location()
+
+		appointments, err := recomputeDates(ctx, db.Collection("appointments"), clinic.ID, "start_time", loc)
+		if err != nil {
+			return err
+		}
+		visits, err := recomputeDates(ctx, db.Collection("visits"), clinic.ID, "created_at", loc)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Clinic %s (%s): updated %d appointment and %d visit dates", clinic.Name, loc, appointments, visits)
+	}
+
+	return nil
+}
+
+// recomputeDates sets `date` from timeField in loc for every document of a clinic
+// whose stored date differs, returning the number of documents changed
+func recomputeDates(ctx context.Context, collection *mongo.Collection, clinicID primitive.ObjectID, timeField string, loc *time.Location) (int, error) {
+	cursor, err := collection.Find(ctx, bson.M{"clinic_id": clinicID})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var writes []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return 0, err
+		}
+
+		ts, ok := doc[timeField].(primitive.DateTime)
+		if !ok {
+			continue
+		}
+
+		date := models.LocalDate(ts.Time(), loc)
+		if current, _ := doc["date"].(string); current == date {
+			continue
+		}
+
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": doc["_id"]}).
+			SetUpdate(bson.M{"$set": bson.M{"date": date}}))
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(writes) == 0 {
+		return 0, nil
+	}
+
+	if _, err := collection.BulkWrite(ctx, writes); err != nil {
+		return 0, err
+	}
+	return len(writes), nil
+}
+
+// backfillPaymentNote marks the payments BackfillVisitPayments records
+const backfillPaymentNote = "Recorded at visit completion"
+
+// BackfillVisitPayments records a ledger payment for every completed visit
+// from before the payments ledger, when completion implied full payment in
+// the visit's payment_type. Such visits have no paid_amount yet, which also
+// makes the migration idempotent. The payment is upserted by visit so a rerun
+// after a failure between the two writes does not record it twice.
+func BackfillVisitPayments(ctx context.Context, db *mongo.Database, log *logger.Logger) error {
+	visits := db.Collection("visits")
+	payments := db.Collection("payments")
+
+	cursor, err := visits.Find(ctx, bson.M{
+		"status":      models.VisitStatusCompleted,
+		"paid_amount": bson.M{"$exists": false},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	for cursor.Next(ctx) {
+		var visit models.Visit
+		if err := cursor.Decode(&visit); err != nil {
+			return err
+		}
+
+		if visit.Total > 0 {
+			paidAt := visit.UpdatedAt
+			if visit.CompletedAt != nil {
+				paidAt = *visit.CompletedAt
+			}
+			method := visit.PaymentType
+			if method == "" {
+				method = models.PaymentTypeCash
+			}
+
+			payment := models.Payment{
+				ClinicID:  visit.ClinicID,
+				VisitID:   visit.ID,
+				PatientID: visit.PatientID,
+				Amount:    visit.Total,
+				Method:    method,
+				CashierID: models.SystemActorID,
+				PaidAt:    paidAt,
+				Date:      visit.Date,
+				Notes:     backfillPaymentNote,
+				CreatedAt: time.Now().UTC(),
+			}
+			_, err := payments.UpdateOne(ctx,
+				bson.M{"visit_id": visit.ID, "cashier_id": models.SystemActorID, "notes": backfillPaymentNote},
+				bson.M{"$setOnInsert": payment},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := visits.UpdateOne(ctx, bson.M{"_id": visit.ID}, bson.M{"$set": bson.M{"paid_amount": visit.Total}}); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	log.Infof("Backfilled payments for %d completed visits", count)
+	return nil
+}
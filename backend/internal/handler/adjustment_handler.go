@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+
+	"medical-crm/internal/middleware"
+	"medical-crm/internal/models"
+	"medical-crm/internal/service"
+	apperrors "medical-crm/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AdjustmentHandler serves the boss-only void and refund flow for completed visits
+type AdjustmentHandler struct {
+	adjustmentService *service.AdjustmentService
+	auditService      *service.AuditService
+}
+
+func NewAdjustmentHandler(adjustmentService *service.AdjustmentService, auditService *service.AuditService) *AdjustmentHandler {
+	return &AdjustmentHandler{
+		adjustmentService: adjustmentService,
+		auditService:      auditService,
+	}
+}
+
+// CreateAdjustment voids a completed visit or refunds some of its services
+// POST /api/v1/boss/visits/:id/adjustments
+func (h *AdjustmentHandler) CreateAdjustment(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	visitID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid visit ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.CreateAdjustmentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	adjustment, err := h.adjustmentService.Create(c.Request.Context(), visitID, clinicID, userID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to adjust visit")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	meta := map[string]interface{}{
+		"adjustment_id":    adjustment.ID.Hex(),
+		"type":             adjustment.Type,
+		"amount":           adjustment.Amount,
+		"earning_reversal": adjustment.EarningReversal,
+		"refund_amount":    adjustment.RefundAmount,
+		"reason":           adjustment.Reason,
+		"doctor_id":        adjustment.DoctorID.Hex(),
+	}
+	if adjustment.RefundPaymentID != nil {
+		meta["refund_payment_id"] = adjustment.RefundPaymentID.Hex()
+	}
+	h.auditService.LogAsync(clinicID, userID, adjustment.VisitID, models.AuditActionVisitAdjusted, "visit", requestID, meta)
+
+	c.JSON(http.StatusCreated, adjustment.ToResponse())
+}
+
+// ListVisitAdjustments returns the voids and refunds of one visit
+// GET /api/v1/boss/visits/:id/adjustments
+func (h *AdjustmentHandler) ListVisitAdjustments(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	visitID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid visit ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	adjustments, err := h.adjustmentService.ListByVisit(c.Request.Context(), visitID, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list adjustments")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"adjustments": adjustments})
+}
+
+// ListAdjustments returns voids and refunds posted in a date range (today by default)
+// GET /api/v1/boss/adjustments?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *AdjustmentHandler) ListAdjustments(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	adjustments, err := h.adjustmentService.List(c.Request.Context(), clinicID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list adjustments")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"adjustments": adjustments})
+}
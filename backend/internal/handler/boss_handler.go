@@ -4,7 +4,6 @@ import (
 	"context"
 	"net/http"
 	"strconv"
-	"time"
 
 	"medical-crm/internal/middleware"
 	"medical-crm/internal/models"
@@ -24,6 +23,8 @@ type BossHandler struct {
 	expenseService  *service.ExpenseService
 	salaryService   *service.StaffSalaryService
 	auditService    *service.AuditService
+	timeOffService  *service.TimeOffService
+	reminderService *service.ReminderService
 	userRepo        *repository.UserRepository
 }
 
@@ -35,6 +36,8 @@ func NewBossHandler(
 	expenseService *service.ExpenseService,
 	salaryService *service.StaffSalaryService,
 	auditService *service.AuditService,
+	timeOffService *service.TimeOffService,
+	reminderService *service.ReminderService,
 	userRepo *repository.UserRepository,
 ) *BossHandler {
 	return &BossHandler{
@@ -45,6 +48,8 @@ func NewBossHandler(
 		expenseService:  expenseService,
 		salaryService:   salaryService,
 		auditService:    auditService,
+		timeOffService:  timeOffService,
+		reminderService: reminderService,
 		userRepo:        userRepo,
 	}
 }
@@ -242,6 +247,13 @@ func (h *BossHandler) UpdateService(c *gin.Context) {
 		return
 	}
 
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
 	serviceID, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		appErr := apperrors.BadRequest("Invalid service ID")
@@ -256,7 +268,7 @@ func (h *BossHandler) UpdateService(c *gin.Context) {
 		return
 	}
 
-	svc, err := h.serviceService.Update(c.Request.Context(), serviceID, clinicID, dto)
+	svc, err := h.serviceService.Update(c.Request.Context(), serviceID, clinicID, userID, dto)
 	if err != nil {
 		if appErr, ok := err.(*apperrors.AppError); ok {
 			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
@@ -302,6 +314,125 @@ func (h *BossHandler) DeleteService(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Service deleted successfully"})
 }
 
+// ListServicePrices returns a service's price history
+// GET /api/v1/boss/services/:id/prices
+func (h *BossHandler) ListServicePrices(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	serviceID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid service ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	prices, err := h.serviceService.ListPrices(c.Request.Context(), serviceID, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list service prices")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"prices": prices})
+}
+
+// ScheduleServicePrice changes a service's price from today or a later date
+// POST /api/v1/boss/services/:id/prices
+func (h *BossHandler) ScheduleServicePrice(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	serviceID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid service ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.ScheduleServicePriceDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	price, err := h.serviceService.SchedulePrice(c.Request.Context(), serviceID, clinicID, userID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to change service price")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, price.ToResponse())
+}
+
+// DeleteServicePrice cancels a scheduled price change
+// DELETE /api/v1/boss/services/:id/prices/:priceId
+func (h *BossHandler) DeleteServicePrice(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	serviceID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid service ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	priceID, err := primitive.ObjectIDFromHex(c.Param("priceId"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid price ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	if err := h.serviceService.DeleteScheduledPrice(c.Request.Context(), serviceID, priceID, clinicID); err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to delete service price")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Price change cancelled"})
+}
+
 // ImportServices bulk imports services from Excel data
 // POST /api/v1/boss/services/import
 func (h *BossHandler) ImportServices(c *gin.Context) {
@@ -370,7 +501,8 @@ func (h *BossHandler) GetDailyReport(c *gin.Context) {
 		return
 	}
 
-	date := c.DefaultQuery("date", time.Now().UTC().Format("2006-01-02"))
+	// Empty date means today in the clinic's time zone
+	date := c.Query("date")
 
 	report, err := h.reportService.GetDailyReport(c.Request.Context(), clinicID, date)
 	if err != nil {
@@ -398,9 +530,9 @@ func (h *BossHandler) GetMonthlyReport(c *gin.Context) {
 		return
 	}
 
-	now := time.Now().UTC()
-	year, _ := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(now.Year())))
-	month, _ := strconv.Atoi(c.DefaultQuery("month", strconv.Itoa(int(now.Month()))))
+	// Missing year/month default to the clinic's current month
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
 
 	report, err := h.reportService.GetMonthlyReport(c.Request.Context(), clinicID, year, month)
 	if err != nil {
@@ -416,6 +548,59 @@ func (h *BossHandler) GetMonthlyReport(c *gin.Context) {
 	c.JSON(http.StatusOK, report)
 }
 
+// GetPriceChangesReport returns price changes in a period with their revenue impact
+// GET /api/v1/boss/reports/price-changes
+func (h *BossHandler) GetPriceChangesReport(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	// Empty dates mean the clinic's current month up to today
+	report, err := h.reportService.GetPriceChangesReport(c.Request.Context(), clinicID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to generate report")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetDoctorPayoutsReport returns doctor earnings paid vs owed
+// GET /api/v1/boss/reports/doctor-payouts
+func (h *BossHandler) GetDoctorPayoutsReport(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	report, err := h.reportService.GetDoctorPayoutsReport(c.Request.Context(), clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to generate report")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // ListDoctors returns all doctors
 // GET /api/v1/boss/doctors
 func (h *BossHandler) ListDoctors(c *gin.Context) {
@@ -443,6 +628,79 @@ func (h *BossHandler) ListDoctors(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"doctors": responses})
 }
 
+// GetDoctorSchedule returns a doctor's weekly working schedule
+// GET /api/v1/boss/doctors/:id/schedule
+func (h *BossHandler) GetDoctorSchedule(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	doctorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid doctor ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	schedule, err := h.userService.GetDoctorSchedule(c.Request.Context(), doctorID, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get doctor schedule")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateDoctorSchedule replaces a doctor's weekly working schedule
+// PUT /api/v1/boss/doctors/:id/schedule
+func (h *BossHandler) UpdateDoctorSchedule(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	doctorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid doctor ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.UpdateWorkScheduleDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	schedule, err := h.userService.UpdateDoctorSchedule(c.Request.Context(), doctorID, clinicID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to update doctor schedule")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
 // ==================== Doctor Contracts ====================
 
 // CreateContract creates a new doctor contract
@@ -920,3 +1178,240 @@ func (h *BossHandler) GetAuditLogs(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"audit_logs": responses})
 }
+
+// ==================== Reminders ====================
+
+// GetReminderSettings returns the clinic's appointment reminder settings
+// GET /api/v1/boss/reminders/settings
+func (h *BossHandler) GetReminderSettings(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	settings, err := h.reminderService.GetSettings(c.Request.Context(), clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get reminder settings")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateReminderSettings configures reminder offsets, channels and template
+// PUT /api/v1/boss/reminders/settings
+func (h *BossHandler) UpdateReminderSettings(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.UpdateReminderSettingsDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	settings, err := h.reminderService.UpdateSettings(c.Request.Context(), clinicID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to update reminder settings")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// ==================== Closures & Leaves ====================
+
+// CreateClosure closes the clinic on one or more days
+// POST /api/v1/boss/closures
+func (h *BossHandler) CreateClosure(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.CreateClosureDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	closure, affected, err := h.timeOffService.CreateClosure(c.Request.Context(), clinicID, userID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to create closure")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, timeOffCreatedResponse(closure, affected))
+}
+
+// ListClosures returns current and upcoming clinic closures
+// GET /api/v1/boss/closures
+func (h *BossHandler) ListClosures(c *gin.Context) {
+	h.listTimeOff(c, models.TimeOffTypeClosure, "closures")
+}
+
+// DeleteClosure removes a clinic closure
+// DELETE /api/v1/boss/closures/:id
+func (h *BossHandler) DeleteClosure(c *gin.Context) {
+	h.deleteTimeOff(c, models.TimeOffTypeClosure, "Closure deleted")
+}
+
+// CreateLeave records a doctor's leave
+// POST /api/v1/boss/leaves
+func (h *BossHandler) CreateLeave(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.CreateLeaveDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	leave, affected, err := h.timeOffService.CreateLeave(c.Request.Context(), clinicID, userID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to create leave")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, timeOffCreatedResponse(leave, affected))
+}
+
+// ListLeaves returns current and upcoming doctor leaves
+// GET /api/v1/boss/leaves
+func (h *BossHandler) ListLeaves(c *gin.Context) {
+	h.listTimeOff(c, models.TimeOffTypeLeave, "leaves")
+}
+
+// DeleteLeave removes a doctor's leave
+// DELETE /api/v1/boss/leaves/:id
+func (h *BossHandler) DeleteLeave(c *gin.Context) {
+	h.deleteTimeOff(c, models.TimeOffTypeLeave, "Leave deleted")
+}
+
+func (h *BossHandler) listTimeOff(c *gin.Context, entryType, key string) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	entries, err := h.timeOffService.List(c.Request.Context(), clinicID, entryType)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list " + key)
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	responses := make([]models.TimeOffResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = entry.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{key: responses})
+}
+
+func (h *BossHandler) deleteTimeOff(c *gin.Context, entryType, message string) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	if err := h.timeOffService.Delete(c.Request.Context(), id, clinicID, entryType); err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to delete entry")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func timeOffCreatedResponse(entry *models.TimeOff, affected []models.Appointment) models.CreateTimeOffResponse {
+	resp := models.CreateTimeOffResponse{
+		TimeOff:              entry.ToResponse(),
+		AffectedAppointments: make([]models.AppointmentResponse, len(affected)),
+	}
+	for i, a := range affected {
+		resp.AffectedAppointments[i] = a.ToResponse()
+	}
+	return resp
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"net/http"
+
+	"medical-crm/internal/middleware"
+	"medical-crm/internal/models"
+	"medical-crm/internal/service"
+	apperrors "medical-crm/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CashShiftHandler serves cash register shifts for reception and boss
+type CashShiftHandler struct {
+	shiftService *service.CashShiftService
+	auditService *service.AuditService
+}
+
+func NewCashShiftHandler(shiftService *service.CashShiftService, auditService *service.AuditService) *CashShiftHandler {
+	return &CashShiftHandler{
+		shiftService: shiftService,
+		auditService: auditService,
+	}
+}
+
+// OpenShift opens a shift for the current user with the opening float
+// POST /api/v1/shifts
+func (h *CashShiftHandler) OpenShift(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.OpenCashShiftDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	shift, err := h.shiftService.Open(c.Request.Context(), clinicID, userID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to open shift")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	h.auditService.LogAsync(clinicID, userID, shift.ID, models.AuditActionCashShiftOpened, "cash_shift", requestID, map[string]interface{}{
+		"opening_float": shift.OpeningFloat,
+	})
+
+	c.JSON(http.StatusCreated, shift.ToResponse())
+}
+
+// GetCurrentShift returns the current user's open shift with live totals
+// GET /api/v1/shifts/current
+func (h *CashShiftHandler) GetCurrentShift(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	shift, err := h.shiftService.Current(c.Request.Context(), clinicID, userID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get shift")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+// GetShift returns a shift with its cash payments
+// GET /api/v1/shifts/:id
+func (h *CashShiftHandler) GetShift(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	shiftID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid shift ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	shift, err := h.shiftService.Get(c.Request.Context(), shiftID, clinicID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get shift")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+// CloseShift closes a shift with the counted cash and records the discrepancy
+// PUT /api/v1/shifts/:id/close
+func (h *CashShiftHandler) CloseShift(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	shiftID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid shift ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.CloseCashShiftDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	shift, err := h.shiftService.Close(c.Request.Context(), shiftID, clinicID, userID, middleware.GetUserRole(c), dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to close shift")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	h.auditService.LogAsync(clinicID, userID, shift.ID, models.AuditActionCashShiftClosed, "cash_shift", requestID, map[string]interface{}{
+		"cashier_id":    shift.CashierID.Hex(),
+		"expected_cash": shift.ExpectedCash,
+		"counted_cash":  shift.CountedCash,
+		"discrepancy":   shift.Discrepancy,
+	})
+
+	c.JSON(http.StatusOK, shift.ToResponse())
+}
+
+// ListShifts returns the shift history (today by default), optionally of one cashier
+// GET /api/v1/boss/shifts?from=YYYY-MM-DD&to=YYYY-MM-DD&cashier_id=
+func (h *CashShiftHandler) ListShifts(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var cashierID *primitive.ObjectID
+	if raw := c.Query("cashier_id"); raw != "" {
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			appErr := apperrors.BadRequest("Invalid cashier_id")
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		cashierID = &id
+	}
+
+	shifts, err := h.shiftService.List(c.Request.Context(), clinicID, cashierID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list shifts")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shifts": shifts})
+}
@@ -3,7 +3,6 @@ package handler
 import (
 	"net/http"
 	"strconv"
-	"time"
 
 	"medical-crm/internal/middleware"
 	"medical-crm/internal/models"
@@ -58,7 +57,7 @@ func (h *DoctorHandler) GetSchedule(c *gin.Context) {
 	}
 
 	// Support both single date and date range
-	today := time.Now().UTC().Format("2006-01-02")
+	today := h.appointmentService.Today(c.Request.Context(), clinicID)
 	from := c.DefaultQuery("from", c.DefaultQuery("date", today))
 	to := c.DefaultQuery("to", from)
 
@@ -203,11 +202,19 @@ func (h *DoctorHandler) CompleteVisit(c *gin.Context) {
 	}
 
 	// Log audit event async
-	h.auditService.LogAsync(clinicID, doctorID, visitID, models.AuditActionVisitFinished, "visit", requestID, map[string]interface{}{
-		"total":          visit.Total,
-		"doctor_earning": visit.DoctorEarning,
-		"diagnosis":      visit.Diagnosis,
-	})
+	if visit.Status == models.VisitStatusPendingApproval {
+		h.auditService.LogAsync(clinicID, doctorID, visitID, models.AuditActionVisitApprovalRequested, "visit", requestID, map[string]interface{}{
+			"total":        visit.Total,
+			"discount":     visit.Approval.Discount,
+			"max_discount": visit.Approval.MaxDiscount,
+		})
+	} else {
+		h.auditService.LogAsync(clinicID, doctorID, visitID, models.AuditActionVisitFinished, "visit", requestID, map[string]interface{}{
+			"total":          visit.Total,
+			"doctor_earning": visit.DoctorEarning,
+			"diagnosis":      visit.Diagnosis,
+		})
+	}
 
 	c.JSON(http.StatusOK, visit.ToResponse())
 }
@@ -271,7 +278,10 @@ func (h *DoctorHandler) ListTodayVisits(c *gin.Context) {
 		return
 	}
 
-	date := c.DefaultQuery("date", time.Now().UTC().Format("2006-01-02"))
+	date := c.Query("date")
+	if date == "" {
+		date = h.visitService.Today(c.Request.Context(), clinicID)
+	}
 
 	visits, err := h.visitService.ListByDoctor(c.Request.Context(), clinicID, doctorID, date)
 	if err != nil {
@@ -349,7 +359,7 @@ func (h *DoctorHandler) UpdateAppointmentStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.appointmentService.UpdateStatus(c.Request.Context(), appointmentID, clinicID, dto.Status); err != nil {
+	if err := h.appointmentService.UpdateStatus(c.Request.Context(), appointmentID, clinicID, dto.Status, doctorID); err != nil {
 		if appErr, ok := err.(*apperrors.AppError); ok {
 			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
 			return
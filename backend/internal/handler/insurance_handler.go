@@ -0,0 +1,539 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"medical-crm/internal/middleware"
+	"medical-crm/internal/models"
+	"medical-crm/internal/service"
+	apperrors "medical-crm/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InsuranceHandler struct {
+	insuranceService *service.InsuranceService
+	auditService     *service.AuditService
+}
+
+func NewInsuranceHandler(insuranceService *service.InsuranceService, auditService *service.AuditService) *InsuranceHandler {
+	return &InsuranceHandler{
+		insuranceService: insuranceService,
+		auditService:     auditService,
+	}
+}
+
+// CreatePayer adds an insurance company
+// POST /api/v1/boss/insurance/payers
+func (h *InsuranceHandler) CreatePayer(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.CreateInsurancePayerDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	payer, err := h.insuranceService.CreatePayer(c.Request.Context(), dto, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to create payer")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, payer)
+}
+
+// ListPayers returns all of the clinic's payers
+// GET /api/v1/boss/insurance/payers
+func (h *InsuranceHandler) ListPayers(c *gin.Context) {
+	h.listPayers(c, false)
+}
+
+// ListActivePayers returns the payers new policies can be issued by
+// GET /api/v1/insurance/payers
+func (h *InsuranceHandler) ListActivePayers(c *gin.Context) {
+	h.listPayers(c, true)
+}
+
+func (h *InsuranceHandler) listPayers(c *gin.Context, activeOnly bool) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	payers, err := h.insuranceService.ListPayers(c.Request.Context(), clinicID, activeOnly)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list payers")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payers": payers})
+}
+
+// UpdatePayer updates or deactivates a payer
+// PUT /api/v1/boss/insurance/payers/:id
+func (h *InsuranceHandler) UpdatePayer(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	payerID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid payer ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.UpdateInsurancePayerDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	payer, err := h.insuranceService.UpdatePayer(c.Request.Context(), payerID, clinicID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to update payer")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, payer)
+}
+
+// AddPolicy adds an insurance policy to a patient
+// POST /api/v1/patients/:id/policies
+func (h *InsuranceHandler) AddPolicy(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid patient ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.InsurancePolicyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	policy, err := h.insuranceService.AddPolicy(c.Request.Context(), patientID, clinicID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to add policy")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+// UpdatePolicy replaces a patient's insurance policy
+// PUT /api/v1/patients/:id/policies/:policyId
+func (h *InsuranceHandler) UpdatePolicy(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid patient ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	policyID, err := primitive.ObjectIDFromHex(c.Param("policyId"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid policy ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.InsurancePolicyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	policy, err := h.insuranceService.UpdatePolicy(c.Request.Context(), patientID, policyID, clinicID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to update policy")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeletePolicy removes a patient's insurance policy
+// DELETE /api/v1/patients/:id/policies/:policyId
+func (h *InsuranceHandler) DeletePolicy(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid patient ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	policyID, err := primitive.ObjectIDFromHex(c.Param("policyId"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid policy ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	if err := h.insuranceService.DeletePolicy(c.Request.Context(), patientID, policyID, clinicID); err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to delete policy")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Policy deleted successfully"})
+}
+
+// ListClaims returns visit claims
+// GET /api/v1/boss/insurance/claims?payer_id=&status=
+func (h *InsuranceHandler) ListClaims(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	payerID, ok := h.payerFilter(c)
+	if !ok {
+		return
+	}
+
+	claims, err := h.insuranceService.ListClaims(c.Request.Context(), clinicID, payerID, c.Query("status"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list claims")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"claims": claims})
+}
+
+// CreateBatch batches a payer's pending claims
+// POST /api/v1/boss/insurance/batches
+func (h *InsuranceHandler) CreateBatch(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.CreateClaimBatchDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	batch, err := h.insuranceService.CreateBatch(c.Request.Context(), dto, clinicID, userID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to create claim batch")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	h.auditService.LogAsync(clinicID, userID, batch.ID, models.AuditActionClaimBatchCreated, "claim_batch", requestID, map[string]interface{}{
+		"payer_id": batch.PayerID.Hex(),
+		"claims":   len(batch.Claims),
+		"amount":   batch.Amount,
+	})
+
+	resp := batch.ToResponse()
+	resp.Claims = batch.Claims
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListBatches returns claim batches
+// GET /api/v1/boss/insurance/batches?payer_id=&status=
+func (h *InsuranceHandler) ListBatches(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	payerID, ok := h.payerFilter(c)
+	if !ok {
+		return
+	}
+
+	batches, err := h.insuranceService.ListBatches(c.Request.Context(), clinicID, payerID, c.Query("status"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list claim batches")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batches": batches})
+}
+
+// GetBatch returns a claim batch with its lines
+// GET /api/v1/boss/insurance/batches/:id
+func (h *InsuranceHandler) GetBatch(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	batchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid batch ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	batch, err := h.insuranceService.GetBatch(c.Request.Context(), batchID, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get claim batch")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
+// ExportBatch downloads a claim batch as CSV for the payer
+// GET /api/v1/boss/insurance/batches/:id/export.csv
+func (h *InsuranceHandler) ExportBatch(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	batchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid batch ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	data, filename, err := h.insuranceService.ExportBatch(c.Request.Context(), batchID, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to export claim batch")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv", data)
+}
+
+// UpdateBatchStatus submits or settles a claim batch
+// PUT /api/v1/boss/insurance/batches/:id/status
+func (h *InsuranceHandler) UpdateBatchStatus(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	batchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid batch ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.UpdateClaimBatchStatusDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	batch, err := h.insuranceService.UpdateBatchStatus(c.Request.Context(), batchID, clinicID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to update claim batch")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	h.auditService.LogAsync(clinicID, userID, batch.ID, models.AuditActionClaimBatchStatusChanged, "claim_batch", requestID, map[string]interface{}{
+		"payer_id": batch.PayerID.Hex(),
+		"status":   batch.Status,
+		"amount":   batch.Amount,
+	})
+
+	c.JSON(http.StatusOK, batch.ToResponse())
+}
+
+// DeleteBatch discards a batch that was not submitted
+// DELETE /api/v1/boss/insurance/batches/:id
+func (h *InsuranceHandler) DeleteBatch(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	batchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid batch ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	if err := h.insuranceService.DeleteBatch(c.Request.Context(), batchID, clinicID); err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to delete claim batch")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Claim batch deleted successfully"})
+}
+
+// payerFilter parses the optional payer_id query parameter, writing the
+// error response when it is malformed
+func (h *InsuranceHandler) payerFilter(c *gin.Context) (*primitive.ObjectID, bool) {
+	raw := c.Query("payer_id")
+	if raw == "" {
+		return nil, true
+	}
+	id, err := primitive.ObjectIDFromHex(raw)
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid payer_id")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, middleware.GetRequestID(c)))
+		return nil, false
+	}
+	return &id, true
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+
+	"medical-crm/internal/middleware"
+	"medical-crm/internal/models"
+	"medical-crm/internal/service"
+	apperrors "medical-crm/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MedicalHistoryHandler struct {
+	medicalHistoryService *service.MedicalHistoryService
+	auditService          *service.AuditService
+}
+
+func NewMedicalHistoryHandler(medicalHistoryService *service.MedicalHistoryService, auditService *service.AuditService) *MedicalHistoryHandler {
+	return &MedicalHistoryHandler{
+		medicalHistoryService: medicalHistoryService,
+		auditService:          auditService,
+	}
+}
+
+// GetMedicalHistory returns a patient's medical history and alerts
+// GET /api/v1/patients/:id/medical-history
+func (h *MedicalHistoryHandler) GetMedicalHistory(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid patient ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	history, err := h.medicalHistoryService.Get(c.Request.Context(), patientID, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get medical history")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// UpdateMedicalHistory replaces a patient's medical history
+// PUT /api/v1/patients/:id/medical-history
+func (h *MedicalHistoryHandler) UpdateMedicalHistory(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid patient ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.UpdateMedicalHistoryDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	history, err := h.medicalHistoryService.Update(c.Request.Context(), patientID, clinicID, userID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to update medical history")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	h.auditService.LogAsync(clinicID, userID, patientID, models.AuditActionMedicalHistoryUpdated, "patient", requestID, map[string]interface{}{
+		"version": history.History.Version,
+		"alerts":  len(history.Alerts),
+	})
+
+	c.JSON(http.StatusOK, history)
+}
+
+// ListMedicalHistoryVersions returns every saved version of a patient's
+// medical history, newest first
+// GET /api/v1/patients/:id/medical-history/versions
+func (h *MedicalHistoryHandler) ListMedicalHistoryVersions(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid patient ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	versions, err := h.medicalHistoryService.ListVersions(c.Request.Context(), patientID, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list medical history versions")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"net/http"
+
+	"medical-crm/internal/middleware"
+	"medical-crm/internal/models"
+	"medical-crm/internal/service"
+	apperrors "medical-crm/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PayoutHandler serves doctor payout statements: period closing and payments
+// for the boss, read-only statements for doctors
+type PayoutHandler struct {
+	payoutService *service.PayoutService
+	auditService  *service.AuditService
+}
+
+func NewPayoutHandler(payoutService *service.PayoutService, auditService *service.AuditService) *PayoutHandler {
+	return &PayoutHandler{
+		payoutService: payoutService,
+		auditService:  auditService,
+	}
+}
+
+// ClosePeriod closes a doctor's period into a payout statement
+// POST /api/v1/boss/payouts
+func (h *PayoutHandler) ClosePeriod(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.ClosePayoutPeriodDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	statement, err := h.payoutService.Close(c.Request.Context(), dto, clinicID, userID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to close payout period")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	h.auditService.LogAsync(clinicID, userID, statement.ID, models.AuditActionPayoutPeriodClosed, "payout_statement", requestID, map[string]interface{}{
+		"doctor_id":   statement.DoctorID.Hex(),
+		"period_from": statement.PeriodFrom,
+		"period_to":   statement.PeriodTo,
+		"earning":     statement.Earning,
+	})
+
+	resp := statement.ToResponse()
+	resp.Visits = statement.Visits
+	resp.Reversals = statement.Reversals
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListPayouts returns payout statements, optionally of one doctor
+// GET /api/v1/boss/payouts?doctor_id=
+func (h *PayoutHandler) ListPayouts(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var doctorID *primitive.ObjectID
+	if raw := c.Query("doctor_id"); raw != "" {
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			appErr := apperrors.BadRequest("Invalid doctor_id")
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		doctorID = &id
+	}
+
+	h.list(c, clinicID, doctorID)
+}
+
+// ListMyPayouts returns the signed-in doctor's payout statements
+// GET /api/v1/doctor/payouts
+func (h *PayoutHandler) ListMyPayouts(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	doctorID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	h.list(c, clinicID, &doctorID)
+}
+
+func (h *PayoutHandler) list(c *gin.Context, clinicID primitive.ObjectID, doctorID *primitive.ObjectID) {
+	requestID := middleware.GetRequestID(c)
+
+	statements, err := h.payoutService.List(c.Request.Context(), clinicID, doctorID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list payout statements")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"statements": statements})
+}
+
+// GetPayout returns a statement with its visits; doctors only see their own
+// GET /api/v1/boss/payouts/:id, GET /api/v1/doctor/payouts/:id
+func (h *PayoutHandler) GetPayout(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	statementID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid statement ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	statement, err := h.payoutService.Get(c.Request.Context(), statementID, clinicID, userID, middleware.GetUserRole(c))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get payout statement")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+// RecordPayment records money paid to the doctor against a statement
+// POST /api/v1/boss/payouts/:id/payments
+func (h *PayoutHandler) RecordPayment(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	statementID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid statement ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.RecordPayoutPaymentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	statement, err := h.payoutService.RecordPayment(c.Request.Context(), statementID, clinicID, userID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to record payout payment")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	payment := statement.Payments[len(statement.Payments)-1]
+	h.auditService.LogAsync(clinicID, userID, statement.ID, models.AuditActionPayoutPaid, "payout_statement", requestID, map[string]interface{}{
+		"doctor_id": statement.DoctorID.Hex(),
+		"amount":    payment.Amount,
+		"method":    payment.Method,
+		"owed":      statement.Owed(),
+	})
+
+	c.JSON(http.StatusOK, statement.ToResponse())
+}
+
+// ReopenPayout deletes an unpaid statement, unlocking its period
+// DELETE /api/v1/boss/payouts/:id
+func (h *PayoutHandler) ReopenPayout(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	statementID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid statement ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	statement, err := h.payoutService.Reopen(c.Request.Context(), statementID, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to reopen payout period")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	h.auditService.LogAsync(clinicID, userID, statement.ID, models.AuditActionPayoutReopened, "payout_statement", requestID, map[string]interface{}{
+		"doctor_id":   statement.DoctorID.Hex(),
+		"period_from": statement.PeriodFrom,
+		"period_to":   statement.PeriodTo,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payout period reopened"})
+}
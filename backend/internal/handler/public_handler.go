@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"medical-crm/internal/middleware"
+	"medical-crm/internal/models"
+	"medical-crm/internal/service"
+	apperrors "medical-crm/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PublicHandler serves the unauthenticated online booking API of a clinic
+type PublicHandler struct {
+	bookingService *service.PublicBookingService
+}
+
+func NewPublicHandler(bookingService *service.PublicBookingService) *PublicHandler {
+	return &PublicHandler{
+		bookingService: bookingService,
+	}
+}
+
+// GetClinic returns the public profile of a clinic
+// GET /api/v1/public/clinics/:slug
+func (h *PublicHandler) GetClinic(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinic, err := h.bookingService.GetClinic(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get clinic")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, clinic.ToPublicResponse())
+}
+
+// ListServices returns the bookable services of a clinic
+// GET /api/v1/public/clinics/:slug/services
+func (h *PublicHandler) ListServices(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	services, err := h.bookingService.ListServices(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list services")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"services": services})
+}
+
+// ListDoctors returns the doctors accepting online bookings
+// GET /api/v1/public/clinics/:slug/doctors
+func (h *PublicHandler) ListDoctors(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	doctors, err := h.bookingService.ListDoctors(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list doctors")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"doctors": doctors})
+}
+
+// GetDoctorSlots returns free slots of a doctor
+// GET /api/v1/public/clinics/:slug/doctors/:id/slots?from=YYYY-MM-DD&to=YYYY-MM-DD&service_ids=a,b
+func (h *PublicHandler) GetDoctorSlots(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	doctorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid doctor ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	from := c.Query("from")
+	to := c.DefaultQuery("to", from)
+	if from == "" {
+		appErr := apperrors.BadRequest("from is required")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var serviceIDs []string
+	if raw := c.Query("service_ids"); raw != "" {
+		serviceIDs = strings.Split(raw, ",")
+	}
+
+	slots, err := h.bookingService.GetSlots(c.Request.Context(), c.Param("slug"), doctorID, from, to, serviceIDs)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get free slots")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, slots)
+}
+
+// RequestCode sends a one-time verification code to the patient's phone
+// POST /api/v1/public/clinics/:slug/verification-codes
+func (h *PublicHandler) RequestCode(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	var dto models.RequestVerificationCodeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	if err := h.bookingService.RequestCode(c.Request.Context(), c.Param("slug"), dto); err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to send verification code")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification code sent"})
+}
+
+// CreateBooking requests an appointment; it stays pending until reception approves it
+// POST /api/v1/public/clinics/:slug/bookings
+func (h *PublicHandler) CreateBooking(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	var dto models.PublicBookingDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	booking, err := h.bookingService.Book(c.Request.Context(), c.Param("slug"), dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to create booking")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, booking)
+}
@@ -4,7 +4,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
+	"strings"
 
 	"medical-crm/internal/middleware"
 	"medical-crm/internal/models"
@@ -19,17 +19,23 @@ type ReceptionistHandler struct {
 	patientService     *service.PatientService
 	appointmentService *service.AppointmentService
 	userService        *service.UserService
+	waitlistService    *service.WaitlistService
+	reminderService    *service.ReminderService
 }
 
 func NewReceptionistHandler(
 	patientService *service.PatientService,
 	appointmentService *service.AppointmentService,
 	userService *service.UserService,
+	waitlistService *service.WaitlistService,
+	reminderService *service.ReminderService,
 ) *ReceptionistHandler {
 	return &ReceptionistHandler{
 		patientService:     patientService,
 		appointmentService: appointmentService,
 		userService:        userService,
+		waitlistService:    waitlistService,
+		reminderService:    reminderService,
 	}
 }
 
@@ -186,7 +192,18 @@ func (h *ReceptionistHandler) GetPatient(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, patient.ToResponse())
+	resp, err := h.patientService.Response(c.Request.Context(), patient)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get patient")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // UpdatePatient updates a patient
@@ -226,7 +243,18 @@ func (h *ReceptionistHandler) UpdatePatient(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, patient.ToResponse())
+	resp, err := h.patientService.Response(c.Request.Context(), patient)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to update patient")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // DeletePatient soft-deletes a patient
@@ -303,6 +331,79 @@ func (h *ReceptionistHandler) CreateAppointment(c *gin.Context) {
 	c.JSON(http.StatusCreated, appointment.ToResponse())
 }
 
+// CreateAppointmentSeries books a recurring series of appointments
+// POST /api/v1/appointments/series
+func (h *ReceptionistHandler) CreateAppointmentSeries(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.CreateAppointmentSeriesDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	series, err := h.appointmentService.CreateSeries(c.Request.Context(), dto, clinicID, userID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to create appointment series")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, series)
+}
+
+// GetAppointmentSeries returns a series with its occurrences
+// GET /api/v1/appointments/series/:id
+func (h *ReceptionistHandler) GetAppointmentSeries(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	seriesID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid series ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	series, err := h.appointmentService.GetSeries(c.Request.Context(), seriesID, clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get appointment series")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
 // ListAppointments returns appointments with optional filters
 // GET /api/v1/appointments
 // Query params: from, to, doctor_id, status, page, limit
@@ -333,9 +434,9 @@ func (h *ReceptionistHandler) ListAppointments(c *gin.Context) {
 
 	// Default: if no date params, show today only (more predictable than 7-day range)
 	if fromDate == "" && toDate == "" && singleDate == "" {
-		now := time.Now().UTC()
-		fromDate = now.Format("2006-01-02")
-		toDate = now.Format("2006-01-02")
+		today := h.appointmentService.Today(c.Request.Context(), clinicID)
+		fromDate = today
+		toDate = today
 	}
 
 	// Parse optional doctor ID
@@ -410,7 +511,8 @@ func (h *ReceptionistHandler) GetAppointment(c *gin.Context) {
 	c.JSON(http.StatusOK, appointment.ToResponse())
 }
 
-// RescheduleAppointment reschedules an appointment
+// RescheduleAppointment reschedules an appointment; with scope "following"
+// the rest of its series moves along
 // PUT /api/v1/appointments/:id/reschedule
 func (h *ReceptionistHandler) RescheduleAppointment(c *gin.Context) {
 	requestID := middleware.GetRequestID(c)
@@ -436,6 +538,26 @@ func (h *ReceptionistHandler) RescheduleAppointment(c *gin.Context) {
 		return
 	}
 
+	if dto.Scope == models.SeriesScopeFollowing {
+		moved, conflicts, err := h.appointmentService.RescheduleFollowing(c.Request.Context(), appointmentID, clinicID, dto.StartTime)
+		if err != nil {
+			if appErr, ok := err.(*apperrors.AppError); ok {
+				c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+				return
+			}
+			appErr := apperrors.Internal("Failed to reschedule appointments")
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Appointments rescheduled",
+			"moved":     moved,
+			"conflicts": conflicts,
+		})
+		return
+	}
+
 	if err := h.appointmentService.Reschedule(c.Request.Context(), appointmentID, clinicID, dto.StartTime); err != nil {
 		if appErr, ok := err.(*apperrors.AppError); ok {
 			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
@@ -451,6 +573,7 @@ func (h *ReceptionistHandler) RescheduleAppointment(c *gin.Context) {
 
 // CancelAppointment cancels an appointment
 // PUT /api/v1/appointments/:id/cancel
+// Query params: scope (this|following) for series occurrences
 func (h *ReceptionistHandler) CancelAppointment(c *gin.Context) {
 	requestID := middleware.GetRequestID(c)
 
@@ -461,6 +584,13 @@ func (h *ReceptionistHandler) CancelAppointment(c *gin.Context) {
 		return
 	}
 
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
 	appointmentID, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		appErr := apperrors.BadRequest("Invalid appointment ID")
@@ -468,7 +598,23 @@ func (h *ReceptionistHandler) CancelAppointment(c *gin.Context) {
 		return
 	}
 
-	if err := h.appointmentService.Cancel(c.Request.Context(), appointmentID, clinicID); err != nil {
+	if c.Query("scope") == models.SeriesScopeFollowing {
+		cancelled, err := h.appointmentService.CancelFollowing(c.Request.Context(), appointmentID, clinicID, userID)
+		if err != nil {
+			if appErr, ok := err.(*apperrors.AppError); ok {
+				c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+				return
+			}
+			appErr := apperrors.Internal("Failed to cancel appointments")
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Appointments cancelled", "cancelled": cancelled})
+		return
+	}
+
+	if err := h.appointmentService.Cancel(c.Request.Context(), appointmentID, clinicID, userID); err != nil {
 		if appErr, ok := err.(*apperrors.AppError); ok {
 			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
 			return
@@ -481,6 +627,252 @@ func (h *ReceptionistHandler) CancelAppointment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Appointment cancelled successfully"})
 }
 
+// ReopenAppointment brings a cancelled or no-show appointment back to scheduled
+// PUT /api/v1/appointments/:id/reopen
+func (h *ReceptionistHandler) ReopenAppointment(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	appointmentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid appointment ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	appointment, err := h.appointmentService.Reopen(c.Request.Context(), appointmentID, clinicID, userID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to reopen appointment")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, appointment.ToResponse())
+}
+
+// ApproveAppointment confirms a pending online booking; declining uses cancel
+// PUT /api/v1/appointments/:id/approve
+func (h *ReceptionistHandler) ApproveAppointment(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	appointmentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid appointment ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	if err := h.appointmentService.UpdateStatus(c.Request.Context(), appointmentID, clinicID, models.AppointmentStatusScheduled, userID); err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to approve appointment")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Appointment approved successfully"})
+}
+
+// ListReminders shows reminder deliveries for a day so reception can call
+// patients who weren't reached
+// GET /api/v1/appointments/reminders?date=YYYY-MM-DD&status=failed
+func (h *ReceptionistHandler) ListReminders(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	reminders, err := h.reminderService.ListDeliveries(c.Request.Context(), clinicID, c.Query("date"), c.Query("status"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list reminders")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reminders": reminders})
+}
+
+// ==================== Waitlist ====================
+
+// CreateWaitlistEntry adds a patient to the waitlist
+// POST /api/v1/appointments/waitlist
+func (h *ReceptionistHandler) CreateWaitlistEntry(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.CreateWaitlistEntryDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	entry, err := h.waitlistService.Create(c.Request.Context(), dto, clinicID, userID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to add to waitlist")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry.ToResponse())
+}
+
+// ListWaitlist returns the waitlist in queue order
+// GET /api/v1/appointments/waitlist?status=waiting
+func (h *ReceptionistHandler) ListWaitlist(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	entries, err := h.waitlistService.List(c.Request.Context(), clinicID, c.Query("status"))
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list waitlist")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// BookWaitlistOffer books the slot currently offered to a waitlist entry
+// POST /api/v1/appointments/waitlist/:id/book
+func (h *ReceptionistHandler) BookWaitlistOffer(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	entryID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid waitlist entry ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	appointment, err := h.appointmentService.BookWaitlistOffer(c.Request.Context(), entryID, clinicID, userID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to book waitlist offer")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, appointment.ToResponse())
+}
+
+// DeleteWaitlistEntry removes a patient from the waitlist
+// DELETE /api/v1/appointments/waitlist/:id
+func (h *ReceptionistHandler) DeleteWaitlistEntry(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	entryID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid waitlist entry ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	if err := h.waitlistService.Remove(c.Request.Context(), entryID, clinicID); err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to remove waitlist entry")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Waitlist entry removed"})
+}
+
 // ListDoctors returns all doctors for appointment selection
 // GET /api/v1/doctors
 func (h *ReceptionistHandler) ListDoctors(c *gin.Context) {
@@ -507,3 +899,51 @@ func (h *ReceptionistHandler) ListDoctors(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"doctors": responses})
 }
+
+// GetDoctorAvailability returns free slots of a doctor for a date range
+// GET /api/v1/doctors/:id/availability
+// Query params: from, to (YYYY-MM-DD), service_ids (comma-separated), duration (minutes)
+func (h *ReceptionistHandler) GetDoctorAvailability(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	doctorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid doctor ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	from := c.Query("from")
+	to := c.DefaultQuery("to", from)
+	if from == "" {
+		appErr := apperrors.BadRequest("from is required")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var serviceIDs []string
+	if raw := c.Query("service_ids"); raw != "" {
+		serviceIDs = strings.Split(raw, ",")
+	}
+	minutes, _ := strconv.Atoi(c.Query("duration"))
+
+	availability, err := h.appointmentService.GetAvailability(c.Request.Context(), clinicID, doctorID, from, to, serviceIDs, minutes)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get doctor availability")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
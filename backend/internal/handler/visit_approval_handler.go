@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+
+	"medical-crm/internal/middleware"
+	"medical-crm/internal/models"
+	"medical-crm/internal/service"
+	apperrors "medical-crm/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// VisitApprovalHandler serves the boss side of doctor discounts: the clinic's
+// discount policy and the visits held for approval under it
+type VisitApprovalHandler struct {
+	visitService *service.VisitService
+	auditService *service.AuditService
+}
+
+func NewVisitApprovalHandler(visitService *service.VisitService, auditService *service.AuditService) *VisitApprovalHandler {
+	return &VisitApprovalHandler{
+		visitService: visitService,
+		auditService: auditService,
+	}
+}
+
+// GetDiscountPolicy returns the clinic's limit on doctor discounts
+// GET /api/v1/boss/discount-policy
+func (h *VisitApprovalHandler) GetDiscountPolicy(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	policy, err := h.visitService.GetDiscountPolicy(c.Request.Context(), clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to get discount policy")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateDiscountPolicy sets or removes the clinic's limit on doctor discounts
+// PUT /api/v1/boss/discount-policy
+func (h *VisitApprovalHandler) UpdateDiscountPolicy(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.UpdateDiscountPolicyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		appErr := apperrors.Validation("Invalid request body: " + err.Error())
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	policy, err := h.visitService.UpdateDiscountPolicy(c.Request.Context(), clinicID, dto)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to update discount policy")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// ListPendingApproval returns visits waiting for the boss's decision
+// GET /api/v1/boss/visits/pending-approval
+func (h *VisitApprovalHandler) ListPendingApproval(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	visits, err := h.visitService.ListPendingApproval(c.Request.Context(), clinicID)
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to list visits")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"visits": visits})
+}
+
+// ApproveVisit completes a visit held for approval
+// PUT /api/v1/boss/visits/:id/approve
+func (h *VisitApprovalHandler) ApproveVisit(c *gin.Context) {
+	h.decide(c, models.ApprovalDecisionApproved)
+}
+
+// RejectVisit returns a visit held for approval to the doctor as a draft
+// PUT /api/v1/boss/visits/:id/reject
+func (h *VisitApprovalHandler) RejectVisit(c *gin.Context) {
+	h.decide(c, models.ApprovalDecisionRejected)
+}
+
+func (h *VisitApprovalHandler) decide(c *gin.Context, decision string) {
+	requestID := middleware.GetRequestID(c)
+
+	clinicID, err := middleware.GetClinicObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Clinic not found in token")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	userID, err := middleware.GetUserObjectID(c)
+	if err != nil {
+		appErr := apperrors.Unauthorized("Invalid user")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	visitID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		appErr := apperrors.BadRequest("Invalid visit ID")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	var dto models.VisitApprovalDecisionDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			appErr := apperrors.Validation("Invalid request body: " + err.Error())
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+	}
+
+	var visit *models.Visit
+	action := models.AuditActionVisitApproved
+	if decision == models.ApprovalDecisionApproved {
+		visit, err = h.visitService.Approve(c.Request.Context(), visitID, clinicID, userID, dto)
+	} else {
+		visit, err = h.visitService.Reject(c.Request.Context(), visitID, clinicID, userID, dto)
+		action = models.AuditActionVisitRejected
+	}
+	if err != nil {
+		if appErr, ok := err.(*apperrors.AppError); ok {
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			return
+		}
+		appErr := apperrors.Internal("Failed to decide on visit")
+		c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+		return
+	}
+
+	h.auditService.LogAsync(clinicID, userID, visit.ID, action, "visit", requestID, map[string]interface{}{
+		"doctor_id":    visit.DoctorID.Hex(),
+		"total":        visit.Total,
+		"discount":     visit.Approval.Discount,
+		"max_discount": visit.Approval.MaxDiscount,
+		"note":         visit.Approval.Note,
+	})
+
+	c.JSON(http.StatusOK, visit.ToResponse())
+}
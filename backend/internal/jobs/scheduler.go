@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"medical-crm/pkg/logger"
+)
+
+// Job is a unit of background work run on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs in the backend process until stopped
+type Scheduler struct {
+	jobs   []Job
+	log    *logger.Logger
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(log *logger.Logger) *Scheduler {
+	return &Scheduler{log: log}
+}
+
+// Add registers a job; jobs with a non-positive interval are disabled
+func (s *Scheduler) Add(job Job) {
+	if job.Interval <= 0 {
+		s.log.Infof("Background job %s disabled", job.Name)
+		return
+	}
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches every job in its own goroutine. Each job runs once at
+// startup and then on every tick; a run never overlaps the previous one.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			s.log.Infof("Background job %s started (every %s)", job.Name, job.Interval)
+
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			for {
+				s.runOnce(ctx, job)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(job)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("Background job "+job.Name+" panicked", fmt.Errorf("%v", r))
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+		s.log.Error("Background job "+job.Name+" failed", err)
+	}
+}
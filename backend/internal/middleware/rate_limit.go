@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apperrors "medical-crm/pkg/errors"
+)
+
+// RateLimiter counts requests per key in fixed time windows. State is kept in
+// memory, so limits apply per backend instance.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+	sweptAt time.Time
+}
+
+type rateBucket struct {
+	count   int
+	resetAt time.Time
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		buckets: make(map[string]*rateBucket),
+	}
+}
+
+// Allow records a request for key and reports whether it is within the limit
+func (l *RateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok || now.After(b.resetAt) {
+		b = &rateBucket{resetAt: now.Add(l.window)}
+		l.buckets[key] = b
+	}
+	b.count++
+	return b.count <= l.limit
+}
+
+// sweep drops expired buckets at most once per window so memory stays bounded
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.sweptAt) < l.window {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.After(b.resetAt) {
+			delete(l.buckets, key)
+		}
+	}
+	l.sweptAt = now
+}
+
+// RateLimit rejects clients exceeding the limiter's quota, keyed by client IP
+// and route so separate endpoints have separate budgets
+func RateLimit(limiter *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limiter.Allow(c.ClientIP() + " " + c.FullPath()) {
+			requestID := GetRequestID(c)
+			appErr := apperrors.RateLimited("Too many requests, please try again later")
+			c.JSON(appErr.HTTPStatus, apperrors.NewErrorResponse(appErr, requestID))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
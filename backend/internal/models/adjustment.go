@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Visit adjustment types
+const (
+	AdjustmentTypeVoid   = "void"   // Reverses everything not yet reversed
+	AdjustmentTypeRefund = "refund" // Reverses selected service lines
+)
+
+// AdjustmentLine is the reversed part of one service line of a visit
+type AdjustmentLine struct {
+	ServiceID   primitive.ObjectID `bson:"service_id" json:"service_id"`
+	ServiceName string             `bson:"service_name" json:"service_name"`
+	Price       float64            `bson:"price" json:"price"`
+	Quantity    int                `bson:"quantity" json:"quantity"`
+	Amount      float64            `bson:"amount" json:"amount"` // Price * Quantity less the line's share of the visit discount
+}
+
+// VisitAdjustment is a reversing entry against a completed visit. The visit's
+// billed lines are never edited; adjustments are posted on the clinic-local
+// date they are made and reduce billing and doctor earning in that period.
+type VisitAdjustment struct {
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ClinicID        primitive.ObjectID  `bson:"clinic_id" json:"clinic_id"`
+	VisitID         primitive.ObjectID  `bson:"visit_id" json:"visit_id"`
+	PatientID       primitive.ObjectID  `bson:"patient_id" json:"patient_id"`
+	DoctorID        primitive.ObjectID  `bson:"doctor_id" json:"doctor_id"`
+	VisitDate       string              `bson:"visit_date" json:"visit_date"`
+	Type            string              `bson:"type" json:"type"`
+	Lines           []AdjustmentLine    `bson:"lines" json:"lines"`
+	Amount          float64             `bson:"amount" json:"amount"`                     // Billing reversed
+	EarningReversal float64             `bson:"earning_reversal" json:"earning_reversal"` // Doctor earning clawed back
+	RefundAmount    float64             `bson:"refund_amount" json:"refund_amount"`       // Money paid back to the patient
+	RefundPaymentID *primitive.ObjectID `bson:"refund_payment_id,omitempty" json:"refund_payment_id,omitempty"`
+	Reason          string              `bson:"reason" json:"reason"`
+	Date            string              `bson:"date" json:"date"` // YYYY-MM-DD, clinic-local date of CreatedAt
+	CreatedBy       primitive.ObjectID  `bson:"created_by" json:"created_by"`
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// AdjustmentLineDTO selects a quantity of one service line to reverse
+type AdjustmentLineDTO struct {
+	ServiceID string `json:"service_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,gte=1"`
+}
+
+// CreateAdjustmentDTO is the input for voiding or partially refunding a visit
+type CreateAdjustmentDTO struct {
+	Type         string              `json:"type" binding:"required,oneof=void refund"`
+	Lines        []AdjustmentLineDTO `json:"lines,omitempty" binding:"omitempty,dive"` // Required for refund
+	Reason       string              `json:"reason" binding:"required,max=500"`
+	RefundMethod string              `json:"refund_method,omitempty" binding:"omitempty,max=32"` // A clinic payment method, defaults to cash
+	RefundShift  string              `json:"refund_shift_id,omitempty"`                          // Open shift a cash refund is paid from, defaults to your own
+}
+
+// VisitAdjustmentResponse is the API response for an adjustment
+type VisitAdjustmentResponse struct {
+	ID              string           `json:"id"`
+	VisitID         string           `json:"visit_id"`
+	PatientID       string           `json:"patient_id"`
+	PatientName     string           `json:"patient_name,omitempty"`
+	DoctorID        string           `json:"doctor_id"`
+	DoctorName      string           `json:"doctor_name,omitempty"`
+	VisitDate       string           `json:"visit_date"`
+	Type            string           `json:"type"`
+	Lines           []AdjustmentLine `json:"lines"`
+	Amount          float64          `json:"amount"`
+	EarningReversal float64          `json:"earning_reversal"`
+	RefundAmount    float64          `json:"refund_amount"`
+	RefundPaymentID string           `json:"refund_payment_id,omitempty"`
+	Reason          string           `json:"reason"`
+	Date            string           `json:"date"`
+	CreatedBy       string           `json:"created_by"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+// ToResponse converts VisitAdjustment to VisitAdjustmentResponse
+func (a *VisitAdjustment) ToResponse() VisitAdjustmentResponse {
+	resp := VisitAdjustmentResponse{
+		ID:              a.ID.Hex(),
+		VisitID:         a.VisitID.Hex(),
+		PatientID:       a.PatientID.Hex(),
+		DoctorID:        a.DoctorID.Hex(),
+		VisitDate:       a.VisitDate,
+		Type:            a.Type,
+		Lines:           a.Lines,
+		Amount:          a.Amount,
+		EarningReversal: a.EarningReversal,
+		RefundAmount:    a.RefundAmount,
+		Reason:          a.Reason,
+		Date:            a.Date,
+		CreatedBy:       a.CreatedBy.Hex(),
+		CreatedAt:       a.CreatedAt,
+	}
+	if a.RefundPaymentID != nil {
+		resp.RefundPaymentID = a.RefundPaymentID.Hex()
+	}
+	return resp
+}
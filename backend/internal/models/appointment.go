@@ -8,6 +8,7 @@ import (
 
 // AppointmentStatus constants
 const (
+	AppointmentStatusPending   = "pending" // Requested online, awaiting reception
 	AppointmentStatusScheduled = "scheduled"
 	AppointmentStatusConfirmed = "confirmed"
 	AppointmentStatusInProgress = "in_progress"
@@ -16,6 +17,59 @@ const (
 	AppointmentStatusNoShow    = "no_show"
 )
 
+// appointmentTransitions lists the statuses reachable from each status.
+// Pending online requests are approved (scheduled) or declined (cancelled).
+// Completed, cancelled and no-show are terminal; cancelled and no-show
+// appointments can only come back through an explicit reopen.
+var appointmentTransitions = map[string][]string{
+	AppointmentStatusPending: {
+		AppointmentStatusScheduled,
+		AppointmentStatusCancelled,
+	},
+	AppointmentStatusScheduled: {
+		AppointmentStatusConfirmed,
+		AppointmentStatusInProgress,
+		AppointmentStatusCancelled,
+		AppointmentStatusNoShow,
+	},
+	AppointmentStatusConfirmed: {
+		AppointmentStatusInProgress,
+		AppointmentStatusCancelled,
+		AppointmentStatusNoShow,
+	},
+	AppointmentStatusInProgress: {
+		AppointmentStatusCompleted,
+	},
+}
+
+// CanTransitionAppointment reports whether an appointment may move from one status to another
+func CanTransitionAppointment(from, to string) bool {
+	for _, next := range appointmentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanReopenAppointment reports whether an appointment in status may be reopened
+func CanReopenAppointment(status string) bool {
+	return status == AppointmentStatusCancelled || status == AppointmentStatusNoShow
+}
+
+// CanRescheduleAppointment reports whether an appointment in status may be moved
+func CanRescheduleAppointment(status string) bool {
+	return status == AppointmentStatusPending || status == AppointmentStatusScheduled || status == AppointmentStatusConfirmed
+}
+
+// AppointmentStatusChange records a single status transition
+type AppointmentStatusChange struct {
+	From      string             `bson:"from,omitempty" json:"from,omitempty"` // Empty for the initial status
+	To        string             `bson:"to" json:"to"`
+	ChangedBy primitive.ObjectID `bson:"changed_by" json:"changed_by"`
+	ChangedAt time.Time          `bson:"changed_at" json:"changed_at"`
+}
+
 // Appointment represents a scheduled appointment
 type Appointment struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
@@ -24,12 +78,24 @@ type Appointment struct {
 	DoctorID  primitive.ObjectID `bson:"doctor_id" json:"doctor_id"`
 	Date      string             `bson:"date" json:"date"`           // YYYY-MM-DD format for indexing
 	StartTime time.Time          `bson:"start_time" json:"start_time"` // Full datetime in UTC
-	EndTime   time.Time          `bson:"end_time" json:"end_time"`     // StartTime + 30 minutes
+	EndTime   time.Time          `bson:"end_time" json:"end_time"`     // StartTime + planned services duration
 	Status    string             `bson:"status" json:"status"`
 	Notes     string             `bson:"notes,omitempty" json:"notes,omitempty"`
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
 	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+
+	ServiceIDs    []primitive.ObjectID      `bson:"service_ids,omitempty" json:"service_ids,omitempty"` // Planned services
+	SeriesID      *primitive.ObjectID       `bson:"series_id,omitempty" json:"series_id,omitempty"`     // Set for occurrences of a recurring series
+	StatusHistory []AppointmentStatusChange `bson:"status_history,omitempty" json:"status_history,omitempty"`
+}
+
+// Duration returns the planned length of the appointment
+func (a *Appointment) Duration() time.Duration {
+	if a.EndTime.After(a.StartTime) {
+		return a.EndTime.Sub(a.StartTime)
+	}
+	return SlotDuration
 }
 
 // CreateAppointmentDTO is the input for creating an appointment
@@ -38,6 +104,8 @@ type CreateAppointmentDTO struct {
 	DoctorID  string    `json:"doctor_id" binding:"required"`
 	StartTime time.Time `json:"start_time" binding:"required"`
 	Notes     string    `json:"notes,omitempty"`
+
+	ServiceIDs []string `json:"service_ids,omitempty"` // Planned services; their durations define the end time
 }
 
 // UpdateAppointmentDTO is the input for updating an appointment
@@ -50,6 +118,7 @@ type UpdateAppointmentDTO struct {
 // RescheduleAppointmentDTO is the input for rescheduling
 type RescheduleAppointmentDTO struct {
 	StartTime time.Time `json:"start_time" binding:"required"`
+	Scope     string    `json:"scope,omitempty" binding:"omitempty,oneof=this following"` // Series occurrences only
 }
 
 // AppointmentResponse is the API response for an appointment
@@ -62,29 +131,45 @@ type AppointmentResponse struct {
 	Date        string    `json:"date"`
 	StartTime   time.Time `json:"start_time"`
 	EndTime     time.Time `json:"end_time"`
+	Duration    int       `json:"duration"` // In minutes
+	ServiceIDs  []string  `json:"service_ids,omitempty"`
+	SeriesID    string    `json:"series_id,omitempty"`
 	Status      string    `json:"status"`
 	Notes       string    `json:"notes,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
+
+	StatusHistory []AppointmentStatusChange `json:"status_history,omitempty"`
 }
 
 // ToResponse converts Appointment to AppointmentResponse
 func (a *Appointment) ToResponse() AppointmentResponse {
-	return AppointmentResponse{
+	resp := AppointmentResponse{
 		ID:        a.ID.Hex(),
 		PatientID: a.PatientID.Hex(),
 		DoctorID:  a.DoctorID.Hex(),
 		Date:      a.Date,
 		StartTime: a.StartTime,
 		EndTime:   a.EndTime,
+		Duration:  int(a.Duration() / time.Minute),
 		Status:    a.Status,
 		Notes:     a.Notes,
 		CreatedAt: a.CreatedAt,
+
+		StatusHistory: a.StatusHistory,
+	}
+	for _, id := range a.ServiceIDs {
+		resp.ServiceIDs = append(resp.ServiceIDs, id.Hex())
+	}
+	if a.SeriesID != nil {
+		resp.SeriesID = a.SeriesID.Hex()
 	}
+	return resp
 }
 
 // ValidAppointmentStatuses returns valid statuses
 func ValidAppointmentStatuses() []string {
 	return []string{
+		AppointmentStatusPending,
 		AppointmentStatusScheduled,
 		AppointmentStatusConfirmed,
 		AppointmentStatusInProgress,
@@ -94,5 +179,6 @@ func ValidAppointmentStatuses() []string {
 	}
 }
 
-// SlotDuration is the fixed appointment duration (30 minutes)
+// SlotDuration is the default appointment duration (30 minutes),
+// used when no planned services are given
 const SlotDuration = 30 * time.Minute
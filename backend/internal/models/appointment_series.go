@@ -0,0 +1,239 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Recurrence frequencies supported by the RRULE subset
+const (
+	RecurrenceDaily  = "DAILY"
+	RecurrenceWeekly = "WEEKLY"
+)
+
+// Scopes for changing an occurrence of a series
+const (
+	SeriesScopeThis      = "this"
+	SeriesScopeFollowing = "following"
+)
+
+// MaxSeriesOccurrences caps how many appointments one series may expand to
+const MaxSeriesOccurrences = 104
+
+// AppointmentSeries is a recurring booking; each occurrence is stored as an
+// individual Appointment carrying the series ID
+type AppointmentSeries struct {
+	ID         primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	ClinicID   primitive.ObjectID   `bson:"clinic_id" json:"clinic_id"`
+	PatientID  primitive.ObjectID   `bson:"patient_id" json:"patient_id"`
+	DoctorID   primitive.ObjectID   `bson:"doctor_id" json:"doctor_id"`
+	RRule      string               `bson:"rrule" json:"rrule"`           // e.g. FREQ=WEEKLY;COUNT=8
+	StartTime  time.Time            `bson:"start_time" json:"start_time"` // First occurrence, UTC
+	Duration   int                  `bson:"duration" json:"duration"`     // Minutes per occurrence
+	ServiceIDs []primitive.ObjectID `bson:"service_ids,omitempty" json:"service_ids,omitempty"`
+	Notes      string               `bson:"notes,omitempty" json:"notes,omitempty"`
+	CreatedAt  time.Time            `bson:"created_at" json:"created_at"`
+	CreatedBy  primitive.ObjectID   `bson:"created_by" json:"created_by"`
+}
+
+// CreateAppointmentSeriesDTO is the input for creating a recurring series
+type CreateAppointmentSeriesDTO struct {
+	PatientID  string    `json:"patient_id" binding:"required"`
+	DoctorID   string    `json:"doctor_id" binding:"required"`
+	StartTime  time.Time `json:"start_time" binding:"required"`
+	RRule      string    `json:"rrule" binding:"required"`
+	ServiceIDs []string  `json:"service_ids,omitempty"`
+	Notes      string    `json:"notes,omitempty"`
+}
+
+// OccurrenceConflict reports why a single occurrence could not be booked or moved
+type OccurrenceConflict struct {
+	Date      string    `json:"date"`
+	StartTime time.Time `json:"start_time"`
+	Code      string    `json:"code"`
+	Message   string    `json:"message"`
+}
+
+// AppointmentSeriesResponse is the API response for a series
+type AppointmentSeriesResponse struct {
+	ID           string                `json:"id"`
+	PatientID    string                `json:"patient_id"`
+	DoctorID     string                `json:"doctor_id"`
+	RRule        string                `json:"rrule"`
+	StartTime    time.Time             `json:"start_time"`
+	Duration     int                   `json:"duration"`
+	Notes        string                `json:"notes,omitempty"`
+	CreatedAt    time.Time             `json:"created_at"`
+	Appointments []AppointmentResponse `json:"appointments"`
+	Conflicts    []OccurrenceConflict  `json:"conflicts,omitempty"`
+}
+
+// ToResponse converts AppointmentSeries to AppointmentSeriesResponse
+func (s *AppointmentSeries) ToResponse() AppointmentSeriesResponse {
+	return AppointmentSeriesResponse{
+		ID:           s.ID.Hex(),
+		PatientID:    s.PatientID.Hex(),
+		DoctorID:     s.DoctorID.Hex(),
+		RRule:        s.RRule,
+		StartTime:    s.StartTime,
+		Duration:     s.Duration,
+		Notes:        s.Notes,
+		CreatedAt:    s.CreatedAt,
+		Appointments: []AppointmentResponse{},
+	}
+}
+
+// RecurrenceRule is a parsed RRULE subset: FREQ (DAILY/WEEKLY), INTERVAL,
+// COUNT or UNTIL, and BYDAY for weekly rules
+type RecurrenceRule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    string // YYYY-MM-DD, inclusive, clinic-local
+	ByDay    []time.Weekday
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// ParseRecurrenceRule parses an RRULE string such as "FREQ=WEEKLY;BYDAY=TU;COUNT=8"
+func ParseRecurrenceRule(s string) (*RecurrenceRule, error) {
+	rule := &RecurrenceRule{Interval: 1}
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			if value != RecurrenceDaily && value != RecurrenceWeekly {
+				return nil, fmt.Errorf("FREQ must be DAILY or WEEKLY")
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("INTERVAL must be a positive integer")
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("COUNT must be a positive integer")
+			}
+			rule.Count = n
+		case "UNTIL":
+			if len(value) < 8 {
+				return nil, fmt.Errorf("UNTIL must be YYYYMMDD")
+			}
+			until, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("UNTIL must be YYYYMMDD")
+			}
+			rule.Until = until.Format("2006-01-02")
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				wd, ok := rruleWeekdays[d]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY value %q", d)
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %s", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if (rule.Count == 0) == (rule.Until == "") {
+		return nil, fmt.Errorf("exactly one of COUNT or UNTIL is required")
+	}
+	if rule.Count > MaxSeriesOccurrences {
+		return nil, fmt.Errorf("COUNT must not exceed %d", MaxSeriesOccurrences)
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != RecurrenceWeekly {
+		return nil, fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	return rule, nil
+}
+
+// Expand returns the occurrence start times of the rule. Occurrences keep the
+// wall-clock time of start in loc, so a 10:00 series stays at 10:00 across
+// DST changes.
+func (r *RecurrenceRule) Expand(start time.Time, loc *time.Location) ([]time.Time, error) {
+	local := start.In(loc)
+	at := func(dayOffset int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+dayOffset, local.Hour(), local.Minute(), 0, 0, loc)
+	}
+
+	var occurrences []time.Time
+	// done reports whether t ends the expansion; otherwise t is appended
+	done := func(t time.Time) bool {
+		if r.Until != "" && LocalDate(t, loc) > r.Until {
+			return true
+		}
+		if r.Count > 0 && len(occurrences) >= r.Count {
+			return true
+		}
+		return false
+	}
+
+	if r.Freq == RecurrenceDaily {
+		for i := 0; ; i += r.Interval {
+			t := at(i)
+			if done(t) {
+				break
+			}
+			if len(occurrences) >= MaxSeriesOccurrences {
+				return nil, fmt.Errorf("recurrence expands to more than %d occurrences", MaxSeriesOccurrences)
+			}
+			occurrences = append(occurrences, t)
+		}
+		return occurrences, nil
+	}
+
+	// Weekly: offsets from the Monday of the start week (RFC 5545 default WKST=MO)
+	days := r.ByDay
+	if len(days) == 0 {
+		days = []time.Weekday{local.Weekday()}
+	}
+	offsets := make([]int, 0, len(days))
+	for _, d := range days {
+		offsets = append(offsets, (int(d)+6)%7)
+	}
+	sort.Ints(offsets)
+	mondayOffset := -((int(local.Weekday()) + 6) % 7)
+
+	for week := 0; ; week += r.Interval {
+		for _, off := range offsets {
+			dayOffset := mondayOffset + week*7 + off
+			if dayOffset < 0 {
+				continue
+			}
+			t := at(dayOffset)
+			if done(t) {
+				return occurrences, nil
+			}
+			if len(occurrences) >= MaxSeriesOccurrences {
+				return nil, fmt.Errorf("recurrence expands to more than %d occurrences", MaxSeriesOccurrences)
+			}
+			occurrences = append(occurrences, t)
+		}
+	}
+}
@@ -13,8 +13,26 @@ const (
 	AuditActionAppointmentStatusChanged AuditAction = "APPOINTMENT_STATUS_CHANGED"
 	AuditActionVisitStarted             AuditAction = "VISIT_STARTED"
 	AuditActionVisitFinished            AuditAction = "VISIT_FINISHED"
+	AuditActionAppointmentNoShow        AuditAction = "APPOINTMENT_NO_SHOW"
+	AuditActionPaymentRecorded          AuditAction = "PAYMENT_RECORDED"
+	AuditActionVisitAdjusted            AuditAction = "VISIT_ADJUSTED"
+	AuditActionCashShiftOpened          AuditAction = "CASH_SHIFT_OPENED"
+	AuditActionCashShiftClosed          AuditAction = "CASH_SHIFT_CLOSED"
+	AuditActionVisitApprovalRequested   AuditAction = "VISIT_APPROVAL_REQUESTED"
+	AuditActionVisitApproved            AuditAction = "VISIT_APPROVED"
+	AuditActionVisitRejected            AuditAction = "VISIT_REJECTED"
+	AuditActionPayoutPeriodClosed       AuditAction = "PAYOUT_PERIOD_CLOSED"
+	AuditActionPayoutPaid               AuditAction = "PAYOUT_PAID"
+	AuditActionPayoutReopened           AuditAction = "PAYOUT_REOPENED"
+	AuditActionClaimBatchCreated        AuditAction = "CLAIM_BATCH_CREATED"
+	AuditActionClaimBatchStatusChanged  AuditAction = "CLAIM_BATCH_STATUS_CHANGED"
+	AuditActionStockMovementRecorded    AuditAction = "STOCK_MOVEMENT_RECORDED"
+	AuditActionMedicalHistoryUpdated    AuditAction = "MEDICAL_HISTORY_UPDATED"
 )
 
+// SystemActorID is recorded as the actor of changes made by background jobs
+var SystemActorID = primitive.NilObjectID
+
 // AuditLog records doctor activities for tracking and compliance
 type AuditLog struct {
 	ID         primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
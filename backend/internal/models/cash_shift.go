@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CashShift status constants
+const (
+	CashShiftStatusOpen   = "open"
+	CashShiftStatusClosed = "closed"
+)
+
+// CashShift is a cashier's session at the cash register. Payments taken by
+// the cashier while the shift is open are linked to it; at close the counted
+// cash is compared with the opening float plus cash taken, less cash refunded.
+type CashShift struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID     primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	CashierID    primitive.ObjectID `bson:"cashier_id" json:"cashier_id"`
+	Status       string             `bson:"status" json:"status"`
+	Date         string             `bson:"date" json:"date"` // YYYY-MM-DD, clinic-local date of OpenedAt
+	OpenedAt     time.Time          `bson:"opened_at" json:"opened_at"`
+	OpeningFloat float64            `bson:"opening_float" json:"opening_float"`
+	OpeningNotes string             `bson:"opening_notes,omitempty" json:"opening_notes,omitempty"`
+	// Set at close
+	ClosedAt     *time.Time `bson:"closed_at,omitempty" json:"closed_at,omitempty"`
+	CashIn       float64    `bson:"cash_in" json:"cash_in"`             // Cash payments taken
+	CashOut      float64    `bson:"cash_out" json:"cash_out"`           // Cash refunded
+	ExpectedCash float64    `bson:"expected_cash" json:"expected_cash"` // OpeningFloat + CashIn - CashOut
+	CountedCash  float64    `bson:"counted_cash" json:"counted_cash"`
+	Discrepancy  float64    `bson:"discrepancy" json:"discrepancy"` // CountedCash - ExpectedCash; negative is a shortage
+	ClosingNotes string     `bson:"closing_notes,omitempty" json:"closing_notes,omitempty"`
+	CreatedAt    time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// OpenCashShiftDTO is the input for opening a shift
+type OpenCashShiftDTO struct {
+	OpeningFloat float64 `json:"opening_float" binding:"gte=0"`
+	Notes        string  `json:"notes,omitempty" binding:"max=500"`
+}
+
+// CloseCashShiftDTO is the input for closing a shift with the counted cash
+type CloseCashShiftDTO struct {
+	CountedCash *float64 `json:"counted_cash" binding:"required,gte=0"`
+	Notes       string   `json:"notes,omitempty" binding:"max=500"`
+}
+
+// CashShiftResponse is the API response for a shift. For open shifts the
+// cash totals are live.
+type CashShiftResponse struct {
+	ID           string            `json:"id"`
+	CashierID    string            `json:"cashier_id"`
+	CashierName  string            `json:"cashier_name,omitempty"`
+	Status       string            `json:"status"`
+	Date         string            `json:"date"`
+	OpenedAt     time.Time         `json:"opened_at"`
+	ClosedAt     *time.Time        `json:"closed_at,omitempty"`
+	OpeningFloat float64           `json:"opening_float"`
+	CashIn       float64           `json:"cash_in"`
+	CashOut      float64           `json:"cash_out"`
+	ExpectedCash float64           `json:"expected_cash"`
+	CountedCash  *float64          `json:"counted_cash,omitempty"`
+	Discrepancy  *float64          `json:"discrepancy,omitempty"`
+	OpeningNotes string            `json:"opening_notes,omitempty"`
+	ClosingNotes string            `json:"closing_notes,omitempty"`
+	Payments     []PaymentResponse `json:"payments,omitempty"` // Cash payments and refunds of the shift, on detail views
+}
+
+// ToResponse converts CashShift to CashShiftResponse
+func (s *CashShift) ToResponse() CashShiftResponse {
+	resp := CashShiftResponse{
+		ID:           s.ID.Hex(),
+		CashierID:    s.CashierID.Hex(),
+		Status:       s.Status,
+		Date:         s.Date,
+		OpenedAt:     s.OpenedAt,
+		ClosedAt:     s.ClosedAt,
+		OpeningFloat: s.OpeningFloat,
+		CashIn:       s.CashIn,
+		CashOut:      s.CashOut,
+		ExpectedCash: s.ExpectedCash,
+		OpeningNotes: s.OpeningNotes,
+		ClosingNotes: s.ClosingNotes,
+	}
+	if s.Status == CashShiftStatusClosed {
+		counted, discrepancy := s.CountedCash, s.Discrepancy
+		resp.CountedCash = &counted
+		resp.Discrepancy = &discrepancy
+	}
+	return resp
+}
+
+// CashShiftSummary is a shift line of the daily report
+type CashShiftSummary struct {
+	ID           string   `json:"id"`
+	CashierID    string   `json:"cashier_id"`
+	CashierName  string   `json:"cashier_name,omitempty"`
+	Status       string   `json:"status"`
+	ExpectedCash float64  `json:"expected_cash"`
+	CountedCash  *float64 `json:"counted_cash,omitempty"`
+	Discrepancy  *float64 `json:"discrepancy,omitempty"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -10,9 +11,13 @@ import (
 type Clinic struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name      string             `bson:"name" json:"name"`
-	Timezone  string             `bson:"timezone" json:"timezone"` // e.g., "America/New_York"
+	Slug      string             `bson:"slug,omitempty" json:"slug,omitempty"` // Public booking URL key
+	Timezone  string             `bson:"timezone" json:"timezone"`             // e.g., "America/New_York"
 	Address   string             `bson:"address,omitempty" json:"address,omitempty"`
 	Phone     string             `bson:"phone,omitempty" json:"phone,omitempty"`
+	Reminders *ReminderSettings  `bson:"reminders,omitempty" json:"reminders,omitempty"`
+	Payments  []PaymentMethod    `bson:"payment_methods,omitempty" json:"payment_methods,omitempty"` // Defaults apply when empty
+	Discounts *DiscountPolicy    `bson:"discount_policy,omitempty" json:"discount_policy,omitempty"`
 	IsActive  bool               `bson:"is_active" json:"is_active"`
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
@@ -21,6 +26,7 @@ type Clinic struct {
 // CreateClinicDTO is the input for creating a clinic
 type CreateClinicDTO struct {
 	Name     string `json:"name" binding:"required,min=2,max=100"`
+	Slug     string `json:"slug,omitempty" binding:"omitempty,min=3,max=50"` // Derived from the name if empty
 	Timezone string `json:"timezone" binding:"required"`
 	Address  string `json:"address,omitempty"`
 	Phone    string `json:"phone,omitempty"`
@@ -29,6 +35,7 @@ type CreateClinicDTO struct {
 // UpdateClinicDTO is the input for updating a clinic (superadmin only)
 type UpdateClinicDTO struct {
 	Name     *string `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
+	Slug     *string `json:"slug,omitempty" binding:"omitempty,min=3,max=50"`
 	Timezone *string `json:"timezone,omitempty"`
 	Address  *string `json:"address,omitempty"`
 	Phone    *string `json:"phone,omitempty"`
@@ -39,6 +46,7 @@ type UpdateClinicDTO struct {
 type ClinicResponse struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
+	Slug      string    `json:"slug,omitempty"`
 	Timezone  string    `json:"timezone"`
 	Address   string    `json:"address,omitempty"`
 	Phone     string    `json:"phone,omitempty"`
@@ -51,6 +59,7 @@ func (c *Clinic) ToResponse() ClinicResponse {
 	return ClinicResponse{
 		ID:        c.ID.Hex(),
 		Name:      c.Name,
+		Slug:      c.Slug,
 		Timezone:  c.Timezone,
 		Address:   c.Address,
 		Phone:     c.Phone,
@@ -58,3 +67,49 @@ func (c *Clinic) ToResponse() ClinicResponse {
 		CreatedAt: c.CreatedAt,
 	}
 }
+
+// Location returns the clinic's time zone, falling back to UTC when unset or unknown
+func (c *Clinic) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LocalDate returns the calendar date (YYYY-MM-DD) of t in loc
+func LocalDate(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("2006-01-02")
+}
+
+// Slugify derives a URL slug from a clinic name: lowercase ASCII letters and
+// digits joined by single hyphens. It returns "" if nothing usable remains.
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+	}
+	slug := b.String()
+	if len(slug) > 50 {
+		slug = strings.TrimRight(slug[:50], "-")
+	}
+	return slug
+}
+
+// ValidSlug reports whether s is a canonical slug as produced by Slugify
+func ValidSlug(s string) bool {
+	return len(s) >= 3 && Slugify(s) == s
+}
@@ -1,18 +1,22 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // DoctorContract represents a contract between a clinic and a doctor
-// that defines the doctor's share percentage for visits
+// that defines the doctor's share percentage for visits. Rules pay other
+// rates on matching services; lines no rule matches earn SharePercentage.
 type DoctorContract struct {
 	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	ClinicID        primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
 	DoctorID        primitive.ObjectID `bson:"doctor_id" json:"doctor_id"`
-	SharePercentage float64            `bson:"share_percentage" json:"share_percentage"`     // 0-100
+	SharePercentage float64            `bson:"share_percentage" json:"share_percentage"`     // 0-100, default rule
+	Rules           []CommissionRule   `bson:"rules,omitempty" json:"rules,omitempty"`       // Evaluated in order, first match wins
+	DeductCosts     bool               `bson:"deduct_costs" json:"deduct_costs"`             // Percentage shares are paid on line amounts less material and lab costs
 	StartDate       string             `bson:"start_date" json:"start_date"`                 // YYYY-MM-DD
 	EndDate         string             `bson:"end_date,omitempty" json:"end_date,omitempty"` // YYYY-MM-DD, empty = ongoing
 	IsActive        bool               `bson:"is_active" json:"is_active"`
@@ -22,36 +26,120 @@ type DoctorContract struct {
 	CreatedBy       primitive.ObjectID `bson:"created_by" json:"created_by"`
 }
 
+// CommissionRule pays the doctor either a share of a visit line's net amount
+// or a fixed amount per unit. A rule matches lines by service or by service
+// category; a rule with neither matches every line.
+type CommissionRule struct {
+	Name            string               `bson:"name,omitempty" json:"name,omitempty"`
+	Category        string               `bson:"category,omitempty" json:"category,omitempty"`
+	ServiceIDs      []primitive.ObjectID `bson:"service_ids,omitempty" json:"service_ids,omitempty"`
+	SharePercentage *float64             `bson:"share_percentage,omitempty" json:"share_percentage,omitempty"` // 0-100
+	FixedAmount     *float64             `bson:"fixed_amount,omitempty" json:"fixed_amount,omitempty"`         // Per unit
+	Tiers           []CommissionTier     `bson:"tiers,omitempty" json:"tiers,omitempty"`                       // Ascending by threshold
+}
+
+// CommissionTier raises a percentage rule's share once the doctor's revenue
+// in the calendar month, before the visit, reaches MinMonthlyRevenue
+type CommissionTier struct {
+	MinMonthlyRevenue float64 `bson:"min_monthly_revenue" json:"min_monthly_revenue" binding:"gt=0"`
+	SharePercentage   float64 `bson:"share_percentage" json:"share_percentage" binding:"min=0,max=100"`
+}
+
+// Matches reports whether the rule applies to a visit line
+func (r *CommissionRule) Matches(line *VisitService) bool {
+	if len(r.ServiceIDs) == 0 && r.Category == "" {
+		return true
+	}
+	for _, id := range r.ServiceIDs {
+		if id == line.ServiceID {
+			return true
+		}
+	}
+	return r.Category != "" && strings.EqualFold(r.Category, line.Category)
+}
+
+// ShareAt returns the rule's percentage for a doctor with the given
+// month-to-date revenue: the highest tier reached, else the base share
+func (r *CommissionRule) ShareAt(monthRevenue float64) float64 {
+	share := 0.0
+	if r.SharePercentage != nil {
+		share = *r.SharePercentage
+	}
+	for _, t := range r.Tiers {
+		if monthRevenue >= t.MinMonthlyRevenue {
+			share = t.SharePercentage
+		}
+	}
+	return share
+}
+
+// HasTiers reports whether any rule depends on the doctor's monthly revenue
+func (dc *DoctorContract) HasTiers() bool {
+	for _, r := range dc.Rules {
+		if len(r.Tiers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleFor returns the first rule matching the line, or nil for the default share
+func (dc *DoctorContract) RuleFor(line *VisitService) *CommissionRule {
+	for i := range dc.Rules {
+		if dc.Rules[i].Matches(line) {
+			return &dc.Rules[i]
+		}
+	}
+	return nil
+}
+
+// CommissionRuleDTO is the input for a contract rule. Exactly one of
+// share_percentage and fixed_amount is required; tiers need a percentage.
+type CommissionRuleDTO struct {
+	Name            string           `json:"name" binding:"max=100"`
+	Category        string           `json:"category" binding:"max=100"`
+	ServiceIDs      []string         `json:"service_ids"`
+	SharePercentage *float64         `json:"share_percentage" binding:"omitempty,min=0,max=100"`
+	FixedAmount     *float64         `json:"fixed_amount" binding:"omitempty,gte=0"`
+	Tiers           []CommissionTier `json:"tiers" binding:"omitempty,dive"`
+}
+
 // CreateDoctorContractDTO is the data transfer object for creating a contract
 type CreateDoctorContractDTO struct {
-	DoctorID        string  `json:"doctor_id" binding:"required"`
-	SharePercentage float64 `json:"share_percentage" binding:"required,min=0,max=100"`
-	StartDate       string  `json:"start_date" binding:"required"`
-	EndDate         string  `json:"end_date"`
-	Notes           string  `json:"notes"`
+	DoctorID        string              `json:"doctor_id" binding:"required"`
+	SharePercentage float64             `json:"share_percentage" binding:"required,min=0,max=100"`
+	StartDate       string              `json:"start_date" binding:"required"`
+	EndDate         string              `json:"end_date"`
+	Notes           string              `json:"notes"`
+	Rules           []CommissionRuleDTO `json:"rules" binding:"omitempty,dive"`
+	DeductCosts     bool                `json:"deduct_costs"`
 }
 
 // UpdateDoctorContractDTO is the data transfer object for updating a contract
 type UpdateDoctorContractDTO struct {
-	SharePercentage *float64 `json:"share_percentage" binding:"omitempty,min=0,max=100"`
-	EndDate         *string  `json:"end_date"`
-	IsActive        *bool    `json:"is_active"`
-	Notes           *string  `json:"notes"`
+	SharePercentage *float64            `json:"share_percentage" binding:"omitempty,min=0,max=100"`
+	EndDate         *string             `json:"end_date"`
+	IsActive        *bool               `json:"is_active"`
+	Notes           *string             `json:"notes"`
+	Rules           []CommissionRuleDTO `json:"rules" binding:"omitempty,dive"` // Replaces all rules when present; [] clears them
+	DeductCosts     *bool               `json:"deduct_costs"`
 }
 
 // DoctorContractResponse is the response format for doctor contracts
 type DoctorContractResponse struct {
-	ID              string  `json:"id"`
-	ClinicID        string  `json:"clinic_id"`
-	DoctorID        string  `json:"doctor_id"`
-	DoctorName      string  `json:"doctor_name,omitempty"` // Populated when joined with user data
-	SharePercentage float64 `json:"share_percentage"`
-	StartDate       string  `json:"start_date"`
-	EndDate         string  `json:"end_date,omitempty"`
-	IsActive        bool    `json:"is_active"`
-	Notes           string  `json:"notes,omitempty"`
-	CreatedAt       string  `json:"created_at"`
-	UpdatedAt       string  `json:"updated_at"`
+	ID              string           `json:"id"`
+	ClinicID        string           `json:"clinic_id"`
+	DoctorID        string           `json:"doctor_id"`
+	DoctorName      string           `json:"doctor_name,omitempty"` // Populated when joined with user data
+	SharePercentage float64          `json:"share_percentage"`
+	Rules           []CommissionRule `json:"rules,omitempty"`
+	DeductCosts     bool             `json:"deduct_costs"`
+	StartDate       string           `json:"start_date"`
+	EndDate         string           `json:"end_date,omitempty"`
+	IsActive        bool             `json:"is_active"`
+	Notes           string           `json:"notes,omitempty"`
+	CreatedAt       string           `json:"created_at"`
+	UpdatedAt       string           `json:"updated_at"`
 }
 
 // ToResponse converts a DoctorContract to its response format
@@ -61,6 +149,8 @@ func (dc *DoctorContract) ToResponse() DoctorContractResponse {
 		ClinicID:        dc.ClinicID.Hex(),
 		DoctorID:        dc.DoctorID.Hex(),
 		SharePercentage: dc.SharePercentage,
+		Rules:           dc.Rules,
+		DeductCosts:     dc.DeductCosts,
 		StartDate:       dc.StartDate,
 		EndDate:         dc.EndDate,
 		IsActive:        dc.IsActive,
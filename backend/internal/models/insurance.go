@@ -0,0 +1,193 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Claim statuses. A claim is pending until its batch is submitted to the
+// payer; batches use the same statuses.
+const (
+	ClaimStatusPending   = "pending"
+	ClaimStatusSubmitted = "submitted"
+	ClaimStatusPaid      = "paid"
+	ClaimStatusRejected  = "rejected" // The patient owes the whole visit
+)
+
+// Claim batch sequence and its printed prefix
+const (
+	CounterClaimBatch = "claim_batch"
+	ClaimBatchPrefix  = "CLM"
+)
+
+// InsurancePayer is an insurance company the clinic bills for covered patients
+type InsurancePayer struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID  primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	Name      string             `bson:"name" json:"name"`
+	Code      string             `bson:"code,omitempty" json:"code,omitempty"` // Payer's identifier for the clinic, printed on exports
+	Phone     string             `bson:"phone,omitempty" json:"phone,omitempty"`
+	Email     string             `bson:"email,omitempty" json:"email,omitempty"`
+	Notes     string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	IsActive  bool               `bson:"is_active" json:"is_active"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// CreateInsurancePayerDTO is the input for adding a payer
+type CreateInsurancePayerDTO struct {
+	Name  string `json:"name" binding:"required,min=1,max=100"`
+	Code  string `json:"code,omitempty" binding:"max=32"`
+	Phone string `json:"phone,omitempty" binding:"max=20"`
+	Email string `json:"email,omitempty" binding:"omitempty,email"`
+	Notes string `json:"notes,omitempty" binding:"max=500"`
+}
+
+// UpdateInsurancePayerDTO is the input for updating a payer
+type UpdateInsurancePayerDTO struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Code     *string `json:"code,omitempty" binding:"omitempty,max=32"`
+	Phone    *string `json:"phone,omitempty" binding:"omitempty,max=20"`
+	Email    *string `json:"email,omitempty" binding:"omitempty,email"`
+	Notes    *string `json:"notes,omitempty" binding:"omitempty,max=500"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+// InsurancePolicy is a patient's cover with a payer. The payer pays
+// CoveragePercent of each visit, up to MaxPerVisit per visit and AnnualLimit
+// per calendar year; zero limits mean no limit.
+type InsurancePolicy struct {
+	ID              primitive.ObjectID `bson:"_id" json:"id"`
+	PayerID         primitive.ObjectID `bson:"payer_id" json:"payer_id"`
+	PolicyNumber    string             `bson:"policy_number" json:"policy_number"`
+	CoveragePercent float64            `bson:"coverage_percent" json:"coverage_percent"`
+	MaxPerVisit     float64            `bson:"max_per_visit,omitempty" json:"max_per_visit,omitempty"`
+	AnnualLimit     float64            `bson:"annual_limit,omitempty" json:"annual_limit,omitempty"`
+	ValidFrom       string             `bson:"valid_from" json:"valid_from"`                 // YYYY-MM-DD
+	ValidTo         string             `bson:"valid_to,omitempty" json:"valid_to,omitempty"` // YYYY-MM-DD, empty = open-ended
+	IsActive        bool               `bson:"is_active" json:"is_active"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Covers reports whether the policy is in force on a clinic-local date
+func (p *InsurancePolicy) Covers(date string) bool {
+	return p.IsActive && p.ValidFrom <= date && (p.ValidTo == "" || date <= p.ValidTo)
+}
+
+// InsurancePolicyDTO is the input for adding or replacing a patient's policy
+type InsurancePolicyDTO struct {
+	PayerID         string  `json:"payer_id" binding:"required"`
+	PolicyNumber    string  `json:"policy_number" binding:"required,min=1,max=64"`
+	CoveragePercent float64 `json:"coverage_percent" binding:"required,gt=0,max=100"`
+	MaxPerVisit     float64 `json:"max_per_visit,omitempty" binding:"gte=0"`
+	AnnualLimit     float64 `json:"annual_limit,omitempty" binding:"gte=0"`
+	ValidFrom       string  `json:"valid_from" binding:"required"`
+	ValidTo         string  `json:"valid_to,omitempty"`
+	IsActive        *bool   `json:"is_active,omitempty"` // Defaults to true
+}
+
+// VisitClaim is the payer's part of a completed visit, split off the total
+// under the patient's policy at completion
+type VisitClaim struct {
+	PayerID         primitive.ObjectID  `bson:"payer_id" json:"payer_id"`
+	PolicyID        primitive.ObjectID  `bson:"policy_id" json:"policy_id"`
+	PolicyNumber    string              `bson:"policy_number" json:"policy_number"`
+	CoveragePercent float64             `bson:"coverage_percent" json:"coverage_percent"`
+	Amount          float64             `bson:"amount" json:"amount"` // Payer share of the total as billed
+	Status          string              `bson:"status" json:"status"`
+	BatchID         *primitive.ObjectID `bson:"batch_id,omitempty" json:"batch_id,omitempty"`
+}
+
+// ClaimBatch groups a payer's claims for submission. Lines are frozen when
+// the batch is created.
+type ClaimBatch struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID    primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	PayerID     primitive.ObjectID `bson:"payer_id" json:"payer_id"`
+	Number      int64              `bson:"number" json:"number"`
+	ToDate      string             `bson:"to_date" json:"to_date"` // Claims for visits up to this date
+	Status      string             `bson:"status" json:"status"`
+	Claims      []ClaimLine        `bson:"claims" json:"claims"`
+	Amount      float64            `bson:"amount" json:"amount"`
+	Note        string             `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedBy   primitive.ObjectID `bson:"created_by" json:"created_by"`
+	SubmittedAt *time.Time         `bson:"submitted_at,omitempty" json:"submitted_at,omitempty"`
+	SettledAt   *time.Time         `bson:"settled_at,omitempty" json:"settled_at,omitempty"` // Paid or rejected
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ClaimLine is one visit's claim as submitted in a batch
+type ClaimLine struct {
+	VisitID       primitive.ObjectID `bson:"visit_id" json:"visit_id"`
+	PatientID     primitive.ObjectID `bson:"patient_id" json:"patient_id"`
+	PatientName   string             `bson:"patient_name" json:"patient_name"`
+	PolicyNumber  string             `bson:"policy_number" json:"policy_number"`
+	Date          string             `bson:"date" json:"date"`
+	InvoiceNumber int64              `bson:"invoice_number,omitempty" json:"invoice_number,omitempty"`
+	Total         float64            `bson:"total" json:"total"`   // Visit total less reversals
+	Amount        float64            `bson:"amount" json:"amount"` // Claimed from the payer
+}
+
+// CreateClaimBatchDTO is the input for batching a payer's pending claims
+type CreateClaimBatchDTO struct {
+	PayerID string `json:"payer_id" binding:"required"`
+	ToDate  string `json:"to_date,omitempty"` // Defaults to today
+	Note    string `json:"note,omitempty" binding:"max=500"`
+}
+
+// UpdateClaimBatchStatusDTO is the input for moving a batch along
+type UpdateClaimBatchStatusDTO struct {
+	Status string `json:"status" binding:"required,oneof=submitted paid rejected"`
+	Note   string `json:"note,omitempty" binding:"max=500"`
+}
+
+// ClaimBatchResponse is the API response for a claim batch
+type ClaimBatchResponse struct {
+	ID          string      `json:"id"`
+	PayerID     string      `json:"payer_id"`
+	PayerName   string      `json:"payer_name,omitempty"`
+	Number      string      `json:"number"`
+	ToDate      string      `json:"to_date"`
+	Status      string      `json:"status"`
+	ClaimCount  int         `json:"claim_count"`
+	Amount      float64     `json:"amount"`
+	Note        string      `json:"note,omitempty"`
+	Claims      []ClaimLine `json:"claims,omitempty"`
+	SubmittedAt *time.Time  `json:"submitted_at,omitempty"`
+	SettledAt   *time.Time  `json:"settled_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// ToResponse converts ClaimBatch to ClaimBatchResponse without its lines
+func (b *ClaimBatch) ToResponse() ClaimBatchResponse {
+	return ClaimBatchResponse{
+		ID:          b.ID.Hex(),
+		PayerID:     b.PayerID.Hex(),
+		Number:      FormatDocumentNumber(ClaimBatchPrefix, b.Number),
+		ToDate:      b.ToDate,
+		Status:      b.Status,
+		ClaimCount:  len(b.Claims),
+		Amount:      b.Amount,
+		Note:        b.Note,
+		SubmittedAt: b.SubmittedAt,
+		SettledAt:   b.SettledAt,
+		CreatedAt:   b.CreatedAt,
+	}
+}
+
+// ClaimResponse is a visit's claim as listed for the boss
+type ClaimResponse struct {
+	VisitID       string  `json:"visit_id"`
+	PatientID     string  `json:"patient_id"`
+	PatientName   string  `json:"patient_name,omitempty"`
+	PayerID       string  `json:"payer_id"`
+	PolicyNumber  string  `json:"policy_number"`
+	Date          string  `json:"date"`
+	InvoiceNumber string  `json:"invoice_number,omitempty"`
+	Total         float64 `json:"total"`
+	Amount        float64 `json:"amount"` // Payer share after reversals
+	Status        string  `json:"status"`
+	BatchID       string  `json:"batch_id,omitempty"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Stock movement types. Quantities are signed: purchases add stock,
+// consumption and write-offs remove it, adjustments go either way.
+const (
+	StockMovementPurchase    = "purchase"
+	StockMovementConsumption = "consumption" // Used up by a completed visit
+	StockMovementAdjustment  = "adjustment"  // Correction after a stock count
+	StockMovementWriteOff    = "write_off"   // Expired, damaged or lost
+)
+
+// InventoryItem is a consumable the clinic keeps in stock
+type InventoryItem struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID          primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	Name              string             `bson:"name" json:"name"`
+	SKU               string             `bson:"sku,omitempty" json:"sku,omitempty"`
+	Unit              string             `bson:"unit" json:"unit"`                               // pcs, ml, g, ...
+	Quantity          float64            `bson:"quantity" json:"quantity"`                       // On hand; may go negative when visits use unrecorded stock
+	LowStockThreshold float64            `bson:"low_stock_threshold" json:"low_stock_threshold"` // 0 = not tracked
+	UnitCost          float64            `bson:"unit_cost" json:"unit_cost"`                     // Last purchase price
+	IsActive          bool               `bson:"is_active" json:"is_active"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt         time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// IsLow reports whether the item is at or below its low-stock threshold
+func (i *InventoryItem) IsLow() bool {
+	return i.LowStockThreshold > 0 && i.Quantity <= i.LowStockThreshold
+}
+
+// CreateInventoryItemDTO is the input for adding an item. Stock is added
+// with a purchase or adjustment movement.
+type CreateInventoryItemDTO struct {
+	Name              string  `json:"name" binding:"required,min=1,max=100"`
+	SKU               string  `json:"sku,omitempty" binding:"max=50"`
+	Unit              string  `json:"unit" binding:"required,min=1,max=20"`
+	LowStockThreshold float64 `json:"low_stock_threshold,omitempty" binding:"gte=0"`
+	UnitCost          float64 `json:"unit_cost,omitempty" binding:"gte=0"`
+}
+
+// UpdateInventoryItemDTO is the input for updating an item
+type UpdateInventoryItemDTO struct {
+	Name              *string  `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	SKU               *string  `json:"sku,omitempty" binding:"omitempty,max=50"`
+	Unit              *string  `json:"unit,omitempty" binding:"omitempty,min=1,max=20"`
+	LowStockThreshold *float64 `json:"low_stock_threshold,omitempty" binding:"omitempty,gte=0"`
+	UnitCost          *float64 `json:"unit_cost,omitempty" binding:"omitempty,gte=0"`
+	IsActive          *bool    `json:"is_active,omitempty"`
+}
+
+// StockMovement is one change to an item's stock
+type StockMovement struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ClinicID  primitive.ObjectID  `bson:"clinic_id" json:"clinic_id"`
+	ItemID    primitive.ObjectID  `bson:"item_id" json:"item_id"`
+	Type      string              `bson:"type" json:"type"`
+	Quantity  float64             `bson:"quantity" json:"quantity"` // Signed change
+	Balance   float64             `bson:"balance" json:"balance"`   // On hand after the movement
+	UnitCost  float64             `bson:"unit_cost,omitempty" json:"unit_cost,omitempty"`
+	VisitID   *primitive.ObjectID `bson:"visit_id,omitempty" json:"visit_id,omitempty"`
+	ExpenseID *primitive.ObjectID `bson:"expense_id,omitempty" json:"expense_id,omitempty"`
+	Date      string              `bson:"date" json:"date"` // YYYY-MM-DD, clinic-local
+	Note      string              `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedBy primitive.ObjectID  `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// CreateStockMovementDTO is the input for recording a purchase, adjustment
+// or write-off. Purchases and write-offs take a positive quantity,
+// adjustments a signed one.
+type CreateStockMovementDTO struct {
+	Type          string  `json:"type" binding:"required,oneof=purchase adjustment write_off"`
+	Quantity      float64 `json:"quantity" binding:"required"`
+	UnitCost      float64 `json:"unit_cost,omitempty" binding:"gte=0"` // Purchases only
+	Date          string  `json:"date,omitempty"`                      // Defaults to today
+	Note          string  `json:"note,omitempty" binding:"max=500"`
+	CreateExpense bool    `json:"create_expense,omitempty"` // Record the purchase as a supplies expense
+}
+
+// ServiceMaterial is an item a service uses up, per unit of the service
+type ServiceMaterial struct {
+	ItemID   primitive.ObjectID `bson:"item_id" json:"item_id"`
+	Quantity float64            `bson:"quantity" json:"quantity"`
+}
+
+// ServiceMaterialDTO is one line of a service's bill of materials
+type ServiceMaterialDTO struct {
+	ItemID   string  `json:"item_id" binding:"required"`
+	Quantity float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+// SetServiceMaterialsDTO replaces a service's bill of materials
+type SetServiceMaterialsDTO struct {
+	Materials []ServiceMaterialDTO `json:"materials" binding:"dive"`
+}
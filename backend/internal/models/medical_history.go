@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Medical alert types
+const (
+	MedicalAlertAllergy       = "allergy"
+	MedicalAlertBloodThinners = "blood_thinners"
+	MedicalAlertPregnancy     = "pregnancy"
+	MedicalAlertOther         = "alert" // Entered by staff
+)
+
+// MedicalHistory is a patient's anamnesis. Every change is saved as a new
+// version; Version counts them.
+type MedicalHistory struct {
+	Allergies      []Allergy          `bson:"allergies,omitempty" json:"allergies,omitempty"`
+	Conditions     []string           `bson:"conditions,omitempty" json:"conditions,omitempty"`   // Chronic conditions, e.g. diabetes
+	Medications    []string           `bson:"medications,omitempty" json:"medications,omitempty"` // Currently taken
+	Pregnant       bool               `bson:"pregnant" json:"pregnant"`
+	BloodThinners  bool               `bson:"blood_thinners" json:"blood_thinners"`
+	Alerts         []string           `bson:"alerts,omitempty" json:"alerts,omitempty"` // Other warnings shown at every visit
+	Notes          string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	LastReviewedAt string             `bson:"last_reviewed_at" json:"last_reviewed_at"` // YYYY-MM-DD, clinic-local
+	Version        int                `bson:"version" json:"version"`
+	UpdatedBy      primitive.ObjectID `bson:"updated_by" json:"updated_by"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Allergy is a substance the patient reacts to
+type Allergy struct {
+	Substance string `bson:"substance" json:"substance"` // e.g. penicillin, latex, lidocaine
+	Reaction  string `bson:"reaction,omitempty" json:"reaction,omitempty"`
+	Severity  string `bson:"severity,omitempty" json:"severity,omitempty"` // mild, moderate, severe
+}
+
+// MedicalAlert is a warning a doctor must see before treating the patient
+type MedicalAlert struct {
+	Type    string `bson:"type" json:"type"`
+	Message string `bson:"message" json:"message"`
+}
+
+// CriticalAlerts returns the warnings to show when a visit starts: every
+// allergy, blood thinners, pregnancy and the alerts entered by staff
+func (h *MedicalHistory) CriticalAlerts() []MedicalAlert {
+	if h == nil {
+		return nil
+	}
+	var alerts []MedicalAlert
+	for _, a := range h.Allergies {
+		msg := "Allergy: " + a.Substance
+		if a.Reaction != "" {
+			msg += " (" + a.Reaction + ")"
+		}
+		alerts = append(alerts, MedicalAlert{Type: MedicalAlertAllergy, Message: msg})
+	}
+	if h.BloodThinners {
+		alerts = append(alerts, MedicalAlert{Type: MedicalAlertBloodThinners, Message: "Takes blood thinners"})
+	}
+	if h.Pregnant {
+		alerts = append(alerts, MedicalAlert{Type: MedicalAlertPregnancy, Message: "Pregnant"})
+	}
+	for _, a := range h.Alerts {
+		alerts = append(alerts, MedicalAlert{Type: MedicalAlertOther, Message: a})
+	}
+	return alerts
+}
+
+// AllergyDTO is one allergy in a medical history update
+type AllergyDTO struct {
+	Substance string `json:"substance" binding:"required,min=1,max=100"`
+	Reaction  string `json:"reaction,omitempty" binding:"max=200"`
+	Severity  string `json:"severity,omitempty" binding:"omitempty,oneof=mild moderate severe"`
+}
+
+// UpdateMedicalHistoryDTO replaces a patient's medical history. Saving marks
+// the history reviewed today. Version, when set, must match the current
+// version so concurrent edits are not lost.
+type UpdateMedicalHistoryDTO struct {
+	Allergies     []AllergyDTO `json:"allergies,omitempty" binding:"omitempty,dive"`
+	Conditions    []string     `json:"conditions,omitempty" binding:"omitempty,dive,min=1,max=200"`
+	Medications   []string     `json:"medications,omitempty" binding:"omitempty,dive,min=1,max=200"`
+	Pregnant      bool         `json:"pregnant"`
+	BloodThinners bool         `json:"blood_thinners"`
+	Alerts        []string     `json:"alerts,omitempty" binding:"omitempty,dive,min=1,max=200"`
+	Notes         string       `json:"notes,omitempty" binding:"max=2000"`
+	Version       int          `json:"version,omitempty" binding:"gte=0"`
+}
+
+// MedicalHistoryVersion is a saved version of a patient's medical history
+type MedicalHistoryVersion struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID  primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	PatientID primitive.ObjectID `bson:"patient_id" json:"patient_id"`
+	History   MedicalHistory     `bson:"history" json:"history"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// MedicalHistoryResponse is a patient's current medical history with the
+// alerts derived from it
+type MedicalHistoryResponse struct {
+	PatientID string          `json:"patient_id"`
+	History   *MedicalHistory `json:"history"` // Null when never recorded
+	Alerts    []MedicalAlert  `json:"alerts"`
+}
@@ -8,19 +8,23 @@ import (
 
 // Patient represents a patient record
 type Patient struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	ClinicID  primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
-	FirstName string             `bson:"first_name" json:"first_name"`
-	LastName  string             `bson:"last_name" json:"last_name"`
-	Phone     string             `bson:"phone" json:"phone"`
-	DOB       *time.Time         `bson:"dob,omitempty" json:"dob,omitempty"`
-	Gender    string             `bson:"gender,omitempty" json:"gender,omitempty"` // male, female, other
-	Address   string             `bson:"address,omitempty" json:"address,omitempty"`
-	Notes     string             `bson:"notes,omitempty" json:"notes,omitempty"`
-	IsActive  bool               `bson:"is_active" json:"is_active"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
-	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID       primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	FirstName      string             `bson:"first_name" json:"first_name"`
+	LastName       string             `bson:"last_name" json:"last_name"`
+	Phone          string             `bson:"phone" json:"phone"`
+	Email          string             `bson:"email,omitempty" json:"email,omitempty"`
+	Telegram       string             `bson:"telegram_chat_id,omitempty" json:"telegram_chat_id,omitempty"` // Chat ID for Telegram reminders
+	DOB            *time.Time         `bson:"dob,omitempty" json:"dob,omitempty"`
+	Gender         string             `bson:"gender,omitempty" json:"gender,omitempty"` // male, female, other
+	Address        string             `bson:"address,omitempty" json:"address,omitempty"`
+	Notes          string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	Policies       []InsurancePolicy  `bson:"insurance_policies,omitempty" json:"insurance_policies,omitempty"`
+	MedicalHistory *MedicalHistory    `bson:"medical_history,omitempty" json:"medical_history,omitempty"` // Set via MedicalHistoryService
+	IsActive       bool               `bson:"is_active" json:"is_active"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+	CreatedBy      primitive.ObjectID `bson:"created_by" json:"created_by"`
 }
 
 // CreatePatientDTO is the input for creating a patient
@@ -28,6 +32,8 @@ type CreatePatientDTO struct {
 	FirstName string     `json:"first_name" binding:"required,min=1,max=50"`
 	LastName  string     `json:"last_name" binding:"required,min=1,max=50"`
 	Phone     string     `json:"phone" binding:"required,min=5,max=20"`
+	Email     string     `json:"email,omitempty" binding:"omitempty,email"`
+	Telegram  string     `json:"telegram_chat_id,omitempty" binding:"omitempty,max=32"`
 	DOB       *time.Time `json:"dob,omitempty"`
 	Gender    string     `json:"gender,omitempty" binding:"omitempty,oneof=male female other"`
 	Address   string     `json:"address,omitempty"`
@@ -39,6 +45,8 @@ type UpdatePatientDTO struct {
 	FirstName string     `json:"first_name,omitempty" binding:"omitempty,min=1,max=50"`
 	LastName  string     `json:"last_name,omitempty" binding:"omitempty,min=1,max=50"`
 	Phone     string     `json:"phone,omitempty" binding:"omitempty,min=5,max=20"`
+	Email     string     `json:"email,omitempty" binding:"omitempty,email"`
+	Telegram  string     `json:"telegram_chat_id,omitempty" binding:"omitempty,max=32"`
 	DOB       *time.Time `json:"dob,omitempty"`
 	Gender    string     `json:"gender,omitempty" binding:"omitempty,oneof=male female other"`
 	Address   string     `json:"address,omitempty"`
@@ -52,12 +60,18 @@ type PatientResponse struct {
 	LastName  string     `json:"last_name"`
 	FullName  string     `json:"full_name"`
 	Phone     string     `json:"phone"`
+	Email     string     `json:"email,omitempty"`
+	Telegram  string     `json:"telegram_chat_id,omitempty"`
 	DOB       *time.Time `json:"dob,omitempty"`
 	Gender    string     `json:"gender,omitempty"`
 	Address   string     `json:"address,omitempty"`
 	Notes     string     `json:"notes,omitempty"`
 	IsActive  bool       `json:"is_active"`
 	CreatedAt time.Time  `json:"created_at"`
+
+	Policies []InsurancePolicy `json:"insurance_policies,omitempty"`
+
+	NoShowCount int `json:"no_show_count"` // Appointments marked no_show
 }
 
 // ToResponse converts Patient to PatientResponse
@@ -68,13 +82,26 @@ func (p *Patient) ToResponse() PatientResponse {
 		LastName:  p.LastName,
 		FullName:  p.FirstName + " " + p.LastName,
 		Phone:     p.Phone,
+		Email:     p.Email,
+		Telegram:  p.Telegram,
 		DOB:       p.DOB,
 		Gender:    p.Gender,
 		Address:   p.Address,
 		Notes:     p.Notes,
 		IsActive:  p.IsActive,
 		CreatedAt: p.CreatedAt,
+		Policies:  p.Policies,
+	}
+}
+
+// PolicyOn returns the patient's first policy in force on a date, if any
+func (p *Patient) PolicyOn(date string) *InsurancePolicy {
+	for i := range p.Policies {
+		if p.Policies[i].Covers(date) {
+			return &p.Policies[i]
+		}
 	}
+	return nil
 }
 
 // PaginatedPatientsResponse is the paginated list of patients
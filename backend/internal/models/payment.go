@@ -0,0 +1,233 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Visit payment status constants, derived from paid vs total
+const (
+	PaymentStatusUnpaid  = "unpaid"
+	PaymentStatusPartial = "partial"
+	PaymentStatusPaid    = "paid"
+	PaymentStatusVoided  = "voided" // Fully reversed
+)
+
+// MoneyEpsilon absorbs float rounding when comparing amounts
+const MoneyEpsilon = 0.005
+
+// Per-clinic document sequences and their printed prefixes
+const (
+	CounterInvoice = "invoice"
+	CounterReceipt = "receipt"
+	InvoicePrefix  = "INV"
+	ReceiptPrefix  = "RCT"
+)
+
+// PaymentMethodSplit is stored as the method of a payment settled with several methods
+const PaymentMethodSplit = "split"
+
+// PaymentMethod is a way a clinic takes money, e.g. cash, card, bank transfer
+type PaymentMethod struct {
+	Code     string `bson:"code" json:"code"` // Stored on payments, e.g. "payme"
+	Name     string `bson:"name" json:"name"`
+	IsActive bool   `bson:"is_active" json:"is_active"` // Inactive methods stay in reports but take no new payments
+}
+
+// DefaultPaymentMethods apply to clinics that never configured their own
+func DefaultPaymentMethods() []PaymentMethod {
+	return []PaymentMethod{
+		{Code: PaymentTypeCash, Name: "Cash", IsActive: true},
+		{Code: PaymentTypeCard, Name: "Card", IsActive: true},
+	}
+}
+
+// PaymentMethods returns the clinic's configured payment methods or the defaults
+func (c *Clinic) PaymentMethods() []PaymentMethod {
+	if len(c.Payments) == 0 {
+		return DefaultPaymentMethods()
+	}
+	return c.Payments
+}
+
+// AcceptsPaymentMethod reports whether the clinic takes new payments by code
+func (c *Clinic) AcceptsPaymentMethod(code string) bool {
+	for _, m := range c.PaymentMethods() {
+		if m.Code == code {
+			return m.IsActive
+		}
+	}
+	return false
+}
+
+// PaymentMethodName returns the display name of a method code, or the code itself
+func (c *Clinic) PaymentMethodName(code string) string {
+	for _, m := range c.PaymentMethods() {
+		if m.Code == code {
+			return m.Name
+		}
+	}
+	return code
+}
+
+// ValidPaymentMethodCode reports whether code is a lowercase identifier
+// usable as a payment method, e.g. "bank_transfer"
+func ValidPaymentMethodCode(code string) bool {
+	if len(code) < 2 || len(code) > 32 || code == PaymentMethodSplit {
+		return false
+	}
+	for i, r := range code {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case (r >= '0' && r <= '9') || r == '_':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// PaymentMethodDTO configures one payment method
+type PaymentMethodDTO struct {
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name" binding:"required,max=50"`
+	IsActive *bool  `json:"is_active,omitempty"` // Defaults to true
+}
+
+// UpdatePaymentMethodsDTO replaces the clinic's payment methods. Methods
+// already used by payments should be deactivated rather than removed so
+// reports keep their names.
+type UpdatePaymentMethodsDTO struct {
+	Methods []PaymentMethodDTO `json:"methods" binding:"required,min=1,dive"`
+}
+
+// PaymentSplit is the part of a payment settled with one method
+type PaymentSplit struct {
+	Method string  `bson:"method" json:"method"`
+	Amount float64 `bson:"amount" json:"amount"`
+}
+
+// FormatDocumentNumber renders a sequence number for print, e.g. INV-000042
+func FormatDocumentNumber(prefix string, n int64) string {
+	return fmt.Sprintf("%s-%06d", prefix, n)
+}
+
+// Payment is a ledger document for money received from a patient against a
+// visit. Refunds are entries with a negative amount linked to the adjustment
+// that caused them, so ledger sums are always net.
+type Payment struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ClinicID      primitive.ObjectID  `bson:"clinic_id" json:"clinic_id"`
+	VisitID       primitive.ObjectID  `bson:"visit_id" json:"visit_id"`
+	PatientID     primitive.ObjectID  `bson:"patient_id" json:"patient_id"`
+	Amount        float64             `bson:"amount" json:"amount"`
+	Method        string              `bson:"method" json:"method"`                     // Clinic payment method code, or "split"
+	Splits        []PaymentSplit      `bson:"splits,omitempty" json:"splits,omitempty"` // Per-method amounts of a split payment
+	CashierID     primitive.ObjectID  `bson:"cashier_id" json:"cashier_id"`
+	PaidAt        time.Time           `bson:"paid_at" json:"paid_at"`
+	Date          string              `bson:"date" json:"date"` // YYYY-MM-DD, clinic-local date of PaidAt
+	Notes         string              `bson:"notes,omitempty" json:"notes,omitempty"`
+	ReceiptNumber int64               `bson:"receipt_number,omitempty" json:"receipt_number,omitempty"` // Per-clinic sequence
+	AdjustmentID  *primitive.ObjectID `bson:"adjustment_id,omitempty" json:"adjustment_id,omitempty"`   // Set on refunds
+	ShiftID       *primitive.ObjectID `bson:"shift_id,omitempty" json:"shift_id,omitempty"`             // Cashier's shift open when taken
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// IsRefund reports whether the entry pays money back to the patient
+func (p *Payment) IsRefund() bool {
+	return p.Amount < 0
+}
+
+// MethodAmounts returns the amount per method, one entry unless split
+func (p *Payment) MethodAmounts() []PaymentSplit {
+	if len(p.Splits) > 0 {
+		return p.Splits
+	}
+	return []PaymentSplit{{Method: p.Method, Amount: p.Amount}}
+}
+
+// CreatePaymentDTO is the input for recording a payment, either with one
+// method and amount or split over several methods
+type CreatePaymentDTO struct {
+	VisitID string            `json:"visit_id" binding:"required"`
+	Amount  float64           `json:"amount,omitempty" binding:"omitempty,gt=0"` // Sum of splits if omitted
+	Method  string            `json:"method,omitempty"`
+	Splits  []PaymentSplitDTO `json:"splits,omitempty" binding:"omitempty,dive"`
+	Notes   string            `json:"notes,omitempty" binding:"max=500"`
+}
+
+// PaymentSplitDTO is the amount paid with one method in a split payment
+type PaymentSplitDTO struct {
+	Method string  `json:"method" binding:"required"`
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// PaymentResponse is the API response for a payment
+type PaymentResponse struct {
+	ID            string         `json:"id"`
+	VisitID       string         `json:"visit_id"`
+	PatientID     string         `json:"patient_id"`
+	PatientName   string         `json:"patient_name,omitempty"`
+	Amount        float64        `json:"amount"`
+	Method        string         `json:"method"`
+	Splits        []PaymentSplit `json:"splits,omitempty"`
+	CashierID     string         `json:"cashier_id"`
+	CashierName   string         `json:"cashier_name,omitempty"`
+	PaidAt        time.Time      `json:"paid_at"`
+	Date          string         `json:"date"`
+	Notes         string         `json:"notes,omitempty"`
+	ReceiptNumber string         `json:"receipt_number,omitempty"`
+	AdjustmentID  string         `json:"adjustment_id,omitempty"`
+	ShiftID       string         `json:"shift_id,omitempty"`
+}
+
+// ToResponse converts Payment to PaymentResponse
+func (p *Payment) ToResponse() PaymentResponse {
+	resp := PaymentResponse{
+		ID:        p.ID.Hex(),
+		VisitID:   p.VisitID.Hex(),
+		PatientID: p.PatientID.Hex(),
+		Amount:    p.Amount,
+		Method:    p.Method,
+		Splits:    p.Splits,
+		CashierID: p.CashierID.Hex(),
+		PaidAt:    p.PaidAt,
+		Date:      p.Date,
+		Notes:     p.Notes,
+	}
+	if p.ReceiptNumber > 0 {
+		resp.ReceiptNumber = FormatDocumentNumber(ReceiptPrefix, p.ReceiptNumber)
+	}
+	if p.AdjustmentID != nil {
+		resp.AdjustmentID = p.AdjustmentID.Hex()
+	}
+	if p.ShiftID != nil {
+		resp.ShiftID = p.ShiftID.Hex()
+	}
+	return resp
+}
+
+// VisitBalance is the paid and outstanding amount of one completed visit
+type VisitBalance struct {
+	VisitID     string  `json:"visit_id"`
+	Date        string  `json:"date"`
+	Total       float64 `json:"total"`
+	Paid        float64 `json:"paid"`
+	Outstanding float64 `json:"outstanding"`
+	Status      string  `json:"payment_status"`
+}
+
+// PatientBalanceResponse sums a patient's billed and paid amounts over
+// completed visits, net of voids and refunds
+type PatientBalanceResponse struct {
+	PatientID   string         `json:"patient_id"`
+	Billed      float64        `json:"billed"`
+	Paid        float64        `json:"paid"`
+	Outstanding float64        `json:"outstanding"`   // What the patient owes
+	Visits      []VisitBalance `json:"unpaid_visits"` // Visits with an outstanding amount, oldest first
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PayoutStatement freezes a doctor's earnings for a closed period. Visits
+// dated in the period and reversals posted in it can no longer change; what
+// is owed is settled with payout payments.
+type PayoutStatement struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID     primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	DoctorID     primitive.ObjectID `bson:"doctor_id" json:"doctor_id"`
+	PeriodFrom   string             `bson:"period_from" json:"period_from"` // YYYY-MM-DD, clinic-local
+	PeriodTo     string             `bson:"period_to" json:"period_to"`     // YYYY-MM-DD, inclusive
+	Visits       []PayoutVisit      `bson:"visits" json:"visits"`
+	Reversals    []PayoutReversal   `bson:"reversals" json:"reversals"`
+	Revenue      float64            `bson:"revenue" json:"revenue"`             // Billed for the period's visits
+	GrossEarning float64            `bson:"gross_earning" json:"gross_earning"` // Earning on the period's visits
+	ClawedBack   float64            `bson:"clawed_back" json:"clawed_back"`     // Earning reversed by voids and refunds posted in the period
+	Earning      float64            `bson:"earning" json:"earning"`             // GrossEarning - ClawedBack, the amount owed
+	PaidAmount   float64            `bson:"paid_amount" json:"paid_amount"`
+	Payments     []PayoutPayment    `bson:"payments" json:"payments"`
+	Notes        string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	ClosedBy     primitive.ObjectID `bson:"closed_by" json:"closed_by"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// PayoutVisit is a visit's earning as frozen on the statement
+type PayoutVisit struct {
+	VisitID       primitive.ObjectID `bson:"visit_id" json:"visit_id"`
+	PatientID     primitive.ObjectID `bson:"patient_id" json:"patient_id"`
+	Date          string             `bson:"date" json:"date"`
+	InvoiceNumber int64              `bson:"invoice_number,omitempty" json:"invoice_number,omitempty"`
+	Total         float64            `bson:"total" json:"total"`
+	DoctorShare   float64            `bson:"doctor_share" json:"doctor_share"`
+	Earning       float64            `bson:"earning" json:"earning"`
+}
+
+// PayoutReversal is a void or refund posted in the period
+type PayoutReversal struct {
+	AdjustmentID    primitive.ObjectID `bson:"adjustment_id" json:"adjustment_id"`
+	VisitID         primitive.ObjectID `bson:"visit_id" json:"visit_id"`
+	VisitDate       string             `bson:"visit_date" json:"visit_date"`
+	Date            string             `bson:"date" json:"date"`
+	Amount          float64            `bson:"amount" json:"amount"`
+	EarningReversal float64            `bson:"earning_reversal" json:"earning_reversal"`
+}
+
+// PayoutPayment is money paid to the doctor against a statement
+type PayoutPayment struct {
+	ID        primitive.ObjectID `bson:"id" json:"id"`
+	Amount    float64            `bson:"amount" json:"amount"`
+	Method    string             `bson:"method" json:"method"`
+	Date      string             `bson:"date" json:"date"` // YYYY-MM-DD, clinic-local
+	Notes     string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Owed returns what is left to pay on the statement
+func (p *PayoutStatement) Owed() float64 {
+	if p.Earning-p.PaidAmount < MoneyEpsilon {
+		return 0
+	}
+	return p.Earning - p.PaidAmount
+}
+
+// PaymentStatus returns unpaid, partial or paid. A statement whose
+// claw-backs exceed its earnings owes nothing and counts as paid.
+func (p *PayoutStatement) PaymentStatus() string {
+	switch {
+	case p.Owed() == 0:
+		return PaymentStatusPaid
+	case p.PaidAmount < MoneyEpsilon:
+		return PaymentStatusUnpaid
+	default:
+		return PaymentStatusPartial
+	}
+}
+
+// ClosePayoutPeriodDTO is the input for closing a doctor's period
+type ClosePayoutPeriodDTO struct {
+	DoctorID string `json:"doctor_id" binding:"required"`
+	From     string `json:"from" binding:"required"` // YYYY-MM-DD
+	To       string `json:"to" binding:"required"`   // YYYY-MM-DD, not after today
+	Notes    string `json:"notes,omitempty" binding:"max=500"`
+}
+
+// RecordPayoutPaymentDTO is the input for paying a doctor against a statement
+type RecordPayoutPaymentDTO struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Method string  `json:"method,omitempty" binding:"omitempty,max=32"` // Defaults to cash
+	Notes  string  `json:"notes,omitempty" binding:"max=500"`
+}
+
+// PayoutStatementResponse is the API response for a payout statement
+type PayoutStatementResponse struct {
+	ID            string           `json:"id"`
+	DoctorID      string           `json:"doctor_id"`
+	DoctorName    string           `json:"doctor_name,omitempty"`
+	PeriodFrom    string           `json:"period_from"`
+	PeriodTo      string           `json:"period_to"`
+	VisitsCount   int              `json:"visits_count"`
+	Visits        []PayoutVisit    `json:"visits,omitempty"` // Only on single statements
+	Reversals     []PayoutReversal `json:"reversals,omitempty"`
+	Revenue       float64          `json:"revenue"`
+	GrossEarning  float64          `json:"gross_earning"`
+	ClawedBack    float64          `json:"clawed_back"`
+	Earning       float64          `json:"earning"`
+	PaidAmount    float64          `json:"paid_amount"`
+	Owed          float64          `json:"owed"`
+	PaymentStatus string           `json:"payment_status"`
+	Payments      []PayoutPayment  `json:"payments"`
+	Notes         string           `json:"notes,omitempty"`
+	ClosedBy      string           `json:"closed_by"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// ToResponse converts PayoutStatement to PayoutStatementResponse without visit details
+func (p *PayoutStatement) ToResponse() PayoutStatementResponse {
+	return PayoutStatementResponse{
+		ID:            p.ID.Hex(),
+		DoctorID:      p.DoctorID.Hex(),
+		PeriodFrom:    p.PeriodFrom,
+		PeriodTo:      p.PeriodTo,
+		VisitsCount:   len(p.Visits),
+		Revenue:       p.Revenue,
+		GrossEarning:  p.GrossEarning,
+		ClawedBack:    p.ClawedBack,
+		Earning:       p.Earning,
+		PaidAmount:    p.PaidAmount,
+		Owed:          p.Owed(),
+		PaymentStatus: p.PaymentStatus(),
+		Payments:      p.Payments,
+		Notes:         p.Notes,
+		ClosedBy:      p.ClosedBy.Hex(),
+		CreatedAt:     p.CreatedAt,
+	}
+}
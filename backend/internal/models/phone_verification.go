@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Phone verification limits for online booking
+const (
+	VerificationCodeLength   = 6
+	VerificationCodeTTL      = 10 * time.Minute
+	VerificationResendAfter  = time.Minute
+	VerificationMaxAttempts  = 5
+	VerificationMaxPerPeriod = 5 // Codes per phone per VerificationCodeTTL window
+)
+
+// PhoneVerification holds a one-time code sent to a phone before an online booking
+type PhoneVerification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID  primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	Phone     string             `bson:"phone" json:"phone"`
+	CodeHash  string             `bson:"code_hash" json:"-"`
+	Attempts  int                `bson:"attempts" json:"attempts"`
+	Used      bool               `bson:"used" json:"used"`             // Set while a booking made with the code stands
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"` // Removed by a TTL index
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+// RequestVerificationCodeDTO asks for a one-time code to be sent to a phone
+type RequestVerificationCodeDTO struct {
+	Phone string `json:"phone" binding:"required,min=9,max=13"`
+}
+
+// PublicBookingDTO is an online appointment request from a patient
+type PublicBookingDTO struct {
+	FirstName  string    `json:"first_name" binding:"required,min=1,max=50"`
+	LastName   string    `json:"last_name" binding:"required,min=1,max=50"`
+	Phone      string    `json:"phone" binding:"required,min=9,max=13"`
+	Code       string    `json:"code" binding:"required"` // From RequestVerificationCodeDTO
+	DoctorID   string    `json:"doctor_id" binding:"required"`
+	StartTime  time.Time `json:"start_time" binding:"required"`
+	ServiceIDs []string  `json:"service_ids,omitempty"`
+	Notes      string    `json:"notes,omitempty" binding:"max=500"`
+}
+
+// PublicClinicResponse is the public profile of a clinic
+type PublicClinicResponse struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Timezone string `json:"timezone"`
+	Address  string `json:"address,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+}
+
+// PublicDoctorResponse is the public view of a bookable doctor
+type PublicDoctorResponse struct {
+	ID       string `json:"id"`
+	FullName string `json:"full_name"`
+}
+
+// PublicServiceResponse is the public view of a bookable service
+type PublicServiceResponse struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Price       float64 `json:"price"`
+	Duration    int     `json:"duration"`
+}
+
+// PublicBookingResponse confirms an online request without exposing patient data
+type PublicBookingResponse struct {
+	ID         string    `json:"id"`
+	Status     string    `json:"status"`
+	DoctorName string    `json:"doctor_name"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	Duration   int       `json:"duration"` // In minutes
+}
+
+// ToPublicResponse converts Clinic to PublicClinicResponse
+func (c *Clinic) ToPublicResponse() PublicClinicResponse {
+	return PublicClinicResponse{
+		Name:     c.Name,
+		Slug:     c.Slug,
+		Timezone: c.Timezone,
+		Address:  c.Address,
+		Phone:    c.Phone,
+	}
+}
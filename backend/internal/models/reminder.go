@@ -0,0 +1,145 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reminder channel constants
+const (
+	ReminderChannelSMS      = "sms"
+	ReminderChannelTelegram = "telegram"
+	ReminderChannelEmail    = "email"
+)
+
+// ReminderDelivery status constants
+const (
+	ReminderStatusPending = "pending" // Claimed by the job, being sent
+	ReminderStatusSent    = "sent"
+	ReminderStatusFailed  = "failed"
+)
+
+// MaxReminderHoursBefore is the earliest reminder offset, one week ahead
+const MaxReminderHoursBefore = 168
+
+// DefaultReminderTemplate is used when a clinic has not set its own
+const DefaultReminderTemplate = "{{.PatientName}}, you have an appointment with {{.DoctorName}} at {{.ClinicName}} on {{.Date}} at {{.Time}}."
+
+// ReminderSettings configures appointment reminders of a clinic
+type ReminderSettings struct {
+	Enabled     bool     `bson:"enabled" json:"enabled"`
+	HoursBefore []int    `bson:"hours_before" json:"hours_before"` // One reminder per offset
+	Channels    []string `bson:"channels" json:"channels"`         // In order of preference; the first the patient can be reached on is used
+	Template    string   `bson:"template,omitempty" json:"template,omitempty"`
+}
+
+// DefaultReminderSettings are returned for clinics that never configured reminders
+func DefaultReminderSettings() ReminderSettings {
+	return ReminderSettings{
+		Enabled:     false,
+		HoursBefore: []int{24},
+		Channels:    []string{ReminderChannelSMS},
+		Template:    DefaultReminderTemplate,
+	}
+}
+
+// ReminderTemplateData are the fields available to reminder templates.
+// Date and Time are in the clinic's time zone.
+type ReminderTemplateData struct {
+	PatientName string
+	DoctorName  string
+	ClinicName  string
+	ClinicPhone string
+	Date        string // YYYY-MM-DD
+	Time        string // HH:MM
+}
+
+// RenderReminder executes a reminder template (Go text/template syntax)
+func RenderReminder(tmpl string, data ReminderTemplateData) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultReminderTemplate
+	}
+	t, err := template.New("reminder").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid reminder template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid reminder template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// UpdateReminderSettingsDTO is the input for configuring reminders
+type UpdateReminderSettingsDTO struct {
+	Enabled     bool     `json:"enabled"`
+	HoursBefore []int    `json:"hours_before" binding:"required,min=1,max=5,dive,min=1,max=168"`
+	Channels    []string `json:"channels" binding:"required,min=1,dive,oneof=sms telegram email"`
+	Template    string   `json:"template,omitempty" binding:"max=1000"`
+}
+
+// ReminderDelivery records one reminder for one appointment and its delivery attempts
+type ReminderDelivery struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID         primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	AppointmentID    primitive.ObjectID `bson:"appointment_id" json:"appointment_id"`
+	PatientID        primitive.ObjectID `bson:"patient_id" json:"patient_id"`
+	AppointmentStart time.Time          `bson:"appointment_start" json:"appointment_start"` // A rescheduled appointment is reminded again
+	AppointmentDate  string             `bson:"appointment_date" json:"appointment_date"`   // YYYY-MM-DD, clinic-local
+	HoursBefore      int                `bson:"hours_before" json:"hours_before"`
+	Channel          string             `bson:"channel,omitempty" json:"channel,omitempty"`
+	To               string             `bson:"to,omitempty" json:"to,omitempty"`
+	Body             string             `bson:"body,omitempty" json:"body,omitempty"`
+	Status           string             `bson:"status" json:"status"`
+	Attempts         int                `bson:"attempts" json:"attempts"`
+	LastError        string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	LastAttemptAt    *time.Time         `bson:"last_attempt_at,omitempty" json:"last_attempt_at,omitempty"`
+	NextAttemptAt    *time.Time         `bson:"next_attempt_at,omitempty" json:"next_attempt_at,omitempty"` // Unset when no retry is planned
+	SentAt           *time.Time         `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ReminderDeliveryResponse is the API response for a reminder delivery
+type ReminderDeliveryResponse struct {
+	ID               string     `json:"id"`
+	AppointmentID    string     `json:"appointment_id"`
+	PatientID        string     `json:"patient_id"`
+	PatientName      string     `json:"patient_name,omitempty"`
+	PatientPhone     string     `json:"patient_phone,omitempty"`
+	AppointmentStart time.Time  `json:"appointment_start"`
+	AppointmentDate  string     `json:"appointment_date"`
+	HoursBefore      int        `json:"hours_before"`
+	Channel          string     `json:"channel,omitempty"`
+	To               string     `json:"to,omitempty"`
+	Status           string     `json:"status"`
+	Attempts         int        `json:"attempts"`
+	LastError        string     `json:"last_error,omitempty"`
+	LastAttemptAt    *time.Time `json:"last_attempt_at,omitempty"`
+	NextAttemptAt    *time.Time `json:"next_attempt_at,omitempty"`
+	SentAt           *time.Time `json:"sent_at,omitempty"`
+}
+
+// ToResponse converts ReminderDelivery to ReminderDeliveryResponse
+func (d *ReminderDelivery) ToResponse() ReminderDeliveryResponse {
+	return ReminderDeliveryResponse{
+		ID:               d.ID.Hex(),
+		AppointmentID:    d.AppointmentID.Hex(),
+		PatientID:        d.PatientID.Hex(),
+		AppointmentStart: d.AppointmentStart,
+		AppointmentDate:  d.AppointmentDate,
+		HoursBefore:      d.HoursBefore,
+		Channel:          d.Channel,
+		To:               d.To,
+		Status:           d.Status,
+		Attempts:         d.Attempts,
+		LastError:        d.LastError,
+		LastAttemptAt:    d.LastAttemptAt,
+		NextAttemptAt:    d.NextAttemptAt,
+		SentAt:           d.SentAt,
+	}
+}
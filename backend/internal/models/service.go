@@ -12,7 +12,10 @@ type Service struct {
 	ClinicID    primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
 	Name        string             `bson:"name" json:"name"`
 	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Category    string             `bson:"category" json:"category,omitempty"` // Groups services for doctor commission rules
 	Price       float64            `bson:"price" json:"price"` // In clinic's currency
+	Cost        float64            `bson:"cost" json:"cost"`   // Material cost per unit
+	Materials   []ServiceMaterial  `bson:"materials,omitempty" json:"materials,omitempty"` // Stock consumed per unit
 	Duration    int                `bson:"duration" json:"duration"` // In minutes
 	IsActive    bool               `bson:"is_active" json:"is_active"`
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
@@ -24,7 +27,9 @@ type Service struct {
 type CreateServiceDTO struct {
 	Name        string  `json:"name" binding:"required,min=1,max=100"`
 	Description string  `json:"description,omitempty" binding:"max=500"`
+	Category    string  `json:"category,omitempty" binding:"max=100"`
 	Price       float64 `json:"price" binding:"required,gte=0"`
+	Cost        float64 `json:"cost,omitempty" binding:"gte=0"`
 	Duration    int     `json:"duration,omitempty" binding:"omitempty,gte=5,lte=480"` // 5 min to 8 hours
 }
 
@@ -32,20 +37,25 @@ type CreateServiceDTO struct {
 type UpdateServiceDTO struct {
 	Name        string   `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
 	Description string   `json:"description,omitempty" binding:"max=500"`
+	Category    *string  `json:"category,omitempty" binding:"omitempty,max=100"`
 	Price       *float64 `json:"price,omitempty" binding:"omitempty,gte=0"`
+	Cost        *float64 `json:"cost,omitempty" binding:"omitempty,gte=0"`
 	Duration    *int     `json:"duration,omitempty" binding:"omitempty,gte=5,lte=480"`
 	IsActive    *bool    `json:"is_active,omitempty"`
 }
 
 // ServiceResponse is the API response for a service
 type ServiceResponse struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description,omitempty"`
-	Price       float64   `json:"price"`
-	Duration    int       `json:"duration"`
-	IsActive    bool      `json:"is_active"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Category    string            `json:"category,omitempty"`
+	Price       float64           `json:"price"`
+	Cost        float64           `json:"cost"`
+	Materials   []ServiceMaterial `json:"materials,omitempty"`
+	Duration    int               `json:"duration"`
+	IsActive    bool              `json:"is_active"`
+	CreatedAt   time.Time         `json:"created_at"`
 }
 
 // ToResponse converts Service to ServiceResponse
@@ -54,7 +64,10 @@ func (s *Service) ToResponse() ServiceResponse {
 		ID:          s.ID.Hex(),
 		Name:        s.Name,
 		Description: s.Description,
+		Category:    s.Category,
 		Price:       s.Price,
+		Cost:        s.Cost,
+		Materials:   s.Materials,
 		Duration:    s.Duration,
 		IsActive:    s.IsActive,
 		CreatedAt:   s.CreatedAt,
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ServicePrice is one entry of a service's price history. The price of a
+// service on a date is the entry with the latest EffectiveFrom on or before it.
+type ServicePrice struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID      primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	ServiceID     primitive.ObjectID `bson:"service_id" json:"service_id"`
+	Price         float64            `bson:"price" json:"price"`
+	EffectiveFrom string             `bson:"effective_from" json:"effective_from"` // YYYY-MM-DD, clinic-local
+	Reason        string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	CreatedBy     primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// ScheduleServicePriceDTO is the input for a price change, today or later
+type ScheduleServicePriceDTO struct {
+	Price         float64 `json:"price" binding:"gte=0"`
+	EffectiveFrom string  `json:"effective_from,omitempty"` // YYYY-MM-DD, defaults to today
+	Reason        string  `json:"reason,omitempty" binding:"max=500"`
+}
+
+// ServicePriceResponse is the API response for a price history entry
+type ServicePriceResponse struct {
+	ID            string    `json:"id"`
+	ServiceID     string    `json:"service_id"`
+	Price         float64   `json:"price"`
+	EffectiveFrom string    `json:"effective_from"`
+	Reason        string    `json:"reason,omitempty"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	Current       bool      `json:"current"` // The price in effect today
+}
+
+// ToResponse converts ServicePrice to ServicePriceResponse
+func (p *ServicePrice) ToResponse() ServicePriceResponse {
+	return ServicePriceResponse{
+		ID:            p.ID.Hex(),
+		ServiceID:     p.ServiceID.Hex(),
+		Price:         p.Price,
+		EffectiveFrom: p.EffectiveFrom,
+		Reason:        p.Reason,
+		CreatedBy:     p.CreatedBy.Hex(),
+		CreatedAt:     p.CreatedAt,
+	}
+}
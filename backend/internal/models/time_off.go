@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TimeOffType constants
+const (
+	TimeOffTypeClosure = "closure" // Whole clinic closed (holiday, renovation)
+	TimeOffTypeLeave   = "leave"   // Single doctor on leave/vacation
+)
+
+// TimeOff blocks booking for the whole clinic or for a single doctor
+type TimeOff struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ClinicID  primitive.ObjectID  `bson:"clinic_id" json:"clinic_id"`
+	Type      string              `bson:"type" json:"type"`
+	DoctorID  *primitive.ObjectID `bson:"doctor_id,omitempty" json:"doctor_id,omitempty"` // Leave only
+	StartTime time.Time           `bson:"start_time" json:"start_time"`                   // Inclusive, UTC
+	EndTime   time.Time           `bson:"end_time" json:"end_time"`                       // Exclusive, UTC
+	Reason    string              `bson:"reason,omitempty" json:"reason,omitempty"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+	CreatedBy primitive.ObjectID  `bson:"created_by" json:"created_by"`
+}
+
+// CreateClosureDTO is the input for closing the clinic on whole days
+type CreateClosureDTO struct {
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD, clinic-local
+	EndDate   string `json:"end_date,omitempty"`            // YYYY-MM-DD inclusive, defaults to start_date
+	Reason    string `json:"reason,omitempty"`
+}
+
+// CreateLeaveDTO is the input for a doctor's leave
+type CreateLeaveDTO struct {
+	DoctorID  string    `json:"doctor_id" binding:"required"`
+	StartTime time.Time `json:"start_time" binding:"required"`
+	EndTime   time.Time `json:"end_time" binding:"required"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// TimeOffResponse is the API response for a closure or leave
+type TimeOffResponse struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	DoctorID  string    `json:"doctor_id,omitempty"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ToResponse converts TimeOff to TimeOffResponse
+func (t *TimeOff) ToResponse() TimeOffResponse {
+	resp := TimeOffResponse{
+		ID:        t.ID.Hex(),
+		Type:      t.Type,
+		StartTime: t.StartTime,
+		EndTime:   t.EndTime,
+		Reason:    t.Reason,
+		CreatedAt: t.CreatedAt,
+	}
+	if t.DoctorID != nil {
+		resp.DoctorID = t.DoctorID.Hex()
+	}
+	return resp
+}
+
+// CreateTimeOffResponse is returned when a closure or leave is created.
+// AffectedAppointments lists bookings that now fall into the blocked window
+// and need to be moved by reception.
+type CreateTimeOffResponse struct {
+	TimeOff              TimeOffResponse       `json:"time_off"`
+	AffectedAppointments []AppointmentResponse `json:"affected_appointments"`
+}
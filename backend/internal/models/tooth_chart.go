@@ -0,0 +1,167 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tooth conditions. Caries and fillings may be charted on single surfaces;
+// the others describe the whole tooth. Healthy clears what was charted.
+const (
+	ToothConditionHealthy   = "healthy"
+	ToothConditionCaries    = "caries"
+	ToothConditionFilling   = "filling"
+	ToothConditionCrown     = "crown"
+	ToothConditionRootCanal = "root_canal"
+	ToothConditionImplant   = "implant"
+	ToothConditionBridge    = "bridge"
+	ToothConditionMissing   = "missing"
+)
+
+// Tooth surfaces: mesial, distal, occlusal, incisal, buccal, lingual
+var ToothSurfaces = []string{"M", "D", "O", "I", "B", "L"}
+
+// ValidTooth reports whether t is an FDI tooth number: quadrants 1-4 with
+// teeth 1-8 (permanent) or quadrants 5-8 with teeth 1-5 (primary)
+func ValidTooth(t string) bool {
+	if len(t) != 2 || t[0] < '1' || t[0] > '8' || t[1] < '1' {
+		return false
+	}
+	if t[0] <= '4' {
+		return t[1] <= '8'
+	}
+	return t[1] <= '5'
+}
+
+// ValidSurface reports whether s is a tooth surface code
+func ValidSurface(s string) bool {
+	for _, v := range ToothSurfaces {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// SurfaceCondition reports whether a condition can be charted per surface
+func SurfaceCondition(condition string) bool {
+	return condition == ToothConditionCaries || condition == ToothConditionFilling || condition == ToothConditionHealthy
+}
+
+// ToothChartChange is one change to a patient's chart made during a visit
+type ToothChartChange struct {
+	Tooth     string   `bson:"tooth" json:"tooth"`                           // FDI number, e.g. "36"
+	Surfaces  []string `bson:"surfaces,omitempty" json:"surfaces,omitempty"` // Empty = whole tooth
+	Condition string   `bson:"condition" json:"condition"`
+	Note      string   `bson:"note,omitempty" json:"note,omitempty"`
+}
+
+// ToothChartChangeDTO is the input for a chart change on a visit
+type ToothChartChangeDTO struct {
+	Tooth     string   `json:"tooth" binding:"required"`
+	Surfaces  []string `json:"surfaces,omitempty"`
+	Condition string   `json:"condition" binding:"required,oneof=healthy caries filling crown root_canal implant bridge missing"`
+	Note      string   `json:"note,omitempty" binding:"max=500"`
+}
+
+// ToothChartEntry is a chart change on the patient's timeline, linked to
+// the visit that made it
+type ToothChartEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClinicID  primitive.ObjectID `bson:"clinic_id" json:"clinic_id"`
+	PatientID primitive.ObjectID `bson:"patient_id" json:"patient_id"`
+	VisitID   primitive.ObjectID `bson:"visit_id" json:"visit_id"`
+	DoctorID  primitive.ObjectID `bson:"doctor_id" json:"doctor_id"`
+	Date      string             `bson:"date" json:"date"` // Visit date, YYYY-MM-DD
+	Seq       int                `bson:"seq" json:"-"`     // Order within the visit
+	Tooth     string             `bson:"tooth" json:"tooth"`
+	Surfaces  []string           `bson:"surfaces,omitempty" json:"surfaces,omitempty"`
+	Condition string             `bson:"condition" json:"condition"`
+	Note      string             `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// ToothState is a tooth as charted at some date
+type ToothState struct {
+	Tooth     string            `json:"tooth"`
+	Condition string            `json:"condition,omitempty"` // Whole-tooth condition
+	Surfaces  map[string]string `json:"surfaces,omitempty"`  // Surface code -> condition
+	Date      string            `json:"date"`                // Last change
+	VisitID   string            `json:"visit_id"`            // Visit of the last change
+}
+
+// ToothChartResponse is a patient's chart as of a date. Teeth never charted
+// are not listed.
+type ToothChartResponse struct {
+	PatientID string       `json:"patient_id"`
+	Date      string       `json:"date"`
+	Teeth     []ToothState `json:"teeth"`
+}
+
+// BuildToothChart replays timeline entries, oldest first, into the state of
+// each tooth. A whole-tooth change replaces the tooth's surface conditions.
+func BuildToothChart(entries []ToothChartEntry) []ToothState {
+	teeth := make(map[string]*ToothState)
+	for _, e := range entries {
+		state, ok := teeth[e.Tooth]
+		if !ok {
+			state = &ToothState{Tooth: e.Tooth}
+			teeth[e.Tooth] = state
+		}
+		state.Date = e.Date
+		state.VisitID = e.VisitID.Hex()
+
+		if len(e.Surfaces) == 0 {
+			state.Surfaces = nil
+			state.Condition = e.Condition
+			if e.Condition == ToothConditionHealthy {
+				state.Condition = ""
+			}
+			continue
+		}
+		if state.Surfaces == nil {
+			state.Surfaces = make(map[string]string)
+		}
+		for _, s := range e.Surfaces {
+			if e.Condition == ToothConditionHealthy {
+				delete(state.Surfaces, s)
+			} else {
+				state.Surfaces[s] = e.Condition
+			}
+		}
+		if len(state.Surfaces) == 0 {
+			state.Surfaces = nil
+		}
+	}
+
+	states := make([]ToothState, 0, len(teeth))
+	for _, state := range teeth {
+		states = append(states, *state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Tooth < states[j].Tooth })
+	return states
+}
+
+// ToothTreatment is a visit line done on a tooth
+type ToothTreatment struct {
+	VisitID     string   `json:"visit_id"`
+	Date        string   `json:"date"`
+	Status      string   `json:"status"` // Visit status
+	DoctorID    string   `json:"doctor_id"`
+	DoctorName  string   `json:"doctor_name,omitempty"`
+	ServiceID   string   `json:"service_id"`
+	ServiceName string   `json:"service_name"`
+	Surfaces    []string `json:"surfaces,omitempty"`
+	Diagnosis   string   `json:"diagnosis,omitempty"`
+}
+
+// ToothHistoryResponse is everything done and charted on one tooth, newest
+// treatment first and chart changes oldest first
+type ToothHistoryResponse struct {
+	PatientID  string            `json:"patient_id"`
+	Tooth      string            `json:"tooth"`
+	Treatments []ToothTreatment  `json:"treatments"`
+	Chart      []ToothChartEntry `json:"chart"`
+}
@@ -22,7 +22,8 @@ type User struct {
 	FirstName    string              `bson:"first_name" json:"first_name"`
 	LastName     string              `bson:"last_name" json:"last_name"`
 	Role         string              `bson:"role" json:"role"`
-	ClinicID     *primitive.ObjectID `bson:"clinic_id,omitempty" json:"clinic_id,omitempty"` // nil for superadmin
+	ClinicID     *primitive.ObjectID `bson:"clinic_id,omitempty" json:"clinic_id,omitempty"`         // nil for superadmin
+	WorkSchedule *WorkSchedule       `bson:"work_schedule,omitempty" json:"work_schedule,omitempty"` // Doctors only
 	IsActive     bool                `bson:"is_active" json:"is_active"`
 	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
 	UpdatedAt    time.Time           `bson:"updated_at" json:"updated_at"`
@@ -59,26 +60,28 @@ type RefreshTokenDTO struct {
 
 // UserResponse is the API response for a user
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Phone     string    `json:"phone"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Role      string    `json:"role"`
-	ClinicID  string    `json:"clinic_id,omitempty"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           string        `json:"id"`
+	Phone        string        `json:"phone"`
+	FirstName    string        `json:"first_name"`
+	LastName     string        `json:"last_name"`
+	Role         string        `json:"role"`
+	ClinicID     string        `json:"clinic_id,omitempty"`
+	WorkSchedule *WorkSchedule `json:"work_schedule,omitempty"`
+	IsActive     bool          `json:"is_active"`
+	CreatedAt    time.Time     `json:"created_at"`
 }
 
 // ToResponse converts User to UserResponse
 func (u *User) ToResponse() UserResponse {
 	resp := UserResponse{
-		ID:        u.ID.Hex(),
-		Phone:     u.Phone,
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		Role:      u.Role,
-		IsActive:  u.IsActive,
-		CreatedAt: u.CreatedAt,
+		ID:           u.ID.Hex(),
+		Phone:        u.Phone,
+		FirstName:    u.FirstName,
+		LastName:     u.LastName,
+		Role:         u.Role,
+		WorkSchedule: u.WorkSchedule,
+		IsActive:     u.IsActive,
+		CreatedAt:    u.CreatedAt,
 	}
 	if u.ClinicID != nil {
 		resp.ClinicID = u.ClinicID.Hex()
@@ -8,28 +8,108 @@ import (
 
 // VisitStatus constants
 const (
-	VisitStatusStarted   = "started"
-	VisitStatusCompleted = "completed"
+	VisitStatusStarted         = "started"
+	VisitStatusPendingApproval = "pending_approval" // Completed by the doctor with discounts above the clinic's limit
+	VisitStatusCompleted       = "completed"
 )
 
-// PaymentType constants
+// PaymentType constants are the codes of the default payment methods; clinics
+// can configure others (see PaymentMethod)
 const (
 	PaymentTypeCash = "cash"
 	PaymentTypeCard = "card"
 )
 
-// ValidPaymentTypes returns valid payment types
-func ValidPaymentTypes() []string {
-	return []string{PaymentTypeCash, PaymentTypeCard}
+// VisitService represents a service performed during a visit. Price is the
+// list price; a line may be charged at an overridden unit price and carry
+// its own discount, both with a reason. Earning is the doctor's commission
+// on the line, set when the visit is completed. Cost is the service's
+// material cost per unit when the line was added.
+type VisitService struct {
+	ServiceID      primitive.ObjectID `bson:"service_id" json:"service_id"`
+	ServiceName    string             `bson:"service_name" json:"service_name"`
+	Category       string             `bson:"category,omitempty" json:"category,omitempty"`
+	Price          float64            `bson:"price" json:"price"`
+	Cost           float64            `bson:"cost,omitempty" json:"cost,omitempty"`
+	Quantity       int                `bson:"quantity" json:"quantity"`
+	Teeth          []string           `bson:"teeth,omitempty" json:"teeth,omitempty"`       // FDI numbers the line treated
+	Surfaces       []string           `bson:"surfaces,omitempty" json:"surfaces,omitempty"` // Surfaces treated on each of Teeth
+	PriceOverride  *float64           `bson:"price_override,omitempty" json:"price_override,omitempty"`
+	DiscountType   string             `bson:"discount_type,omitempty" json:"discount_type,omitempty"` // "percentage" or "fixed"
+	DiscountValue  float64            `bson:"discount_value,omitempty" json:"discount_value,omitempty"`
+	DiscountAmount float64            `bson:"discount_amount,omitempty" json:"discount_amount,omitempty"`
+	Reason         string             `bson:"reason,omitempty" json:"reason,omitempty"` // Why the line was overridden or discounted
+	Subtotal       float64            `bson:"subtotal" json:"subtotal"`                 // UnitPrice * Quantity - DiscountAmount
+	Earning        float64            `bson:"earning,omitempty" json:"earning,omitempty"`
+	EarningRule    string             `bson:"earning_rule,omitempty" json:"earning_rule,omitempty"` // How Earning was computed, e.g. "Implants: 25%"
 }
 
-// VisitService represents a service performed during a visit
-type VisitService struct {
-	ServiceID   primitive.ObjectID `bson:"service_id" json:"service_id"`
-	ServiceName string             `bson:"service_name" json:"service_name"`
-	Price       float64            `bson:"price" json:"price"`
-	Quantity    int                `bson:"quantity" json:"quantity"`
-	Subtotal    float64            `bson:"subtotal" json:"subtotal"` // Price * Quantity
+// UnitPrice returns the price charged per unit, before the line discount
+func (l *VisitService) UnitPrice() float64 {
+	if l.PriceOverride != nil {
+		return *l.PriceOverride
+	}
+	return l.Price
+}
+
+// NetUnitPrice returns the price charged per unit after the line discount
+func (l *VisitService) NetUnitPrice() float64 {
+	if l.Quantity <= 0 {
+		return 0
+	}
+	return l.Subtotal / float64(l.Quantity)
+}
+
+// Adjusted reports whether the line is overridden or discounted
+func (l *VisitService) Adjusted() bool {
+	return l.PriceOverride != nil || l.DiscountType != ""
+}
+
+// Calculate computes the line discount and subtotal
+func (l *VisitService) Calculate() {
+	gross := l.UnitPrice() * float64(l.Quantity)
+	switch l.DiscountType {
+	case "percentage":
+		l.DiscountAmount = gross * (l.DiscountValue / 100)
+	case "fixed":
+		l.DiscountAmount = l.DiscountValue
+	default:
+		l.DiscountAmount = 0
+	}
+	if l.DiscountAmount > gross {
+		l.DiscountAmount = gross
+	}
+	l.Subtotal = gross - l.DiscountAmount
+}
+
+// ReductionPercent returns how far below list price the line is charged, in
+// percent; lines charged above list price return 0
+func (l *VisitService) ReductionPercent() float64 {
+	list := l.Price * float64(l.Quantity)
+	if list <= 0 || l.Subtotal >= list {
+		return 0
+	}
+	return (list - l.Subtotal) / list * 100
+}
+
+// LabInvoice is an external lab's bill for work done for a visit, e.g. a
+// crown. An invoice tied to a service is a cost of that line; others are
+// spread over the visit's lines by subtotal.
+type LabInvoice struct {
+	Lab           string              `bson:"lab" json:"lab"`
+	InvoiceNumber string              `bson:"invoice_number,omitempty" json:"invoice_number,omitempty"`
+	Amount        float64             `bson:"amount" json:"amount"`
+	ServiceID     *primitive.ObjectID `bson:"service_id,omitempty" json:"service_id,omitempty"`
+	Notes         string              `bson:"notes,omitempty" json:"notes,omitempty"`
+}
+
+// LabInvoiceDTO is the input for a lab invoice on a visit
+type LabInvoiceDTO struct {
+	Lab           string  `json:"lab" binding:"required,max=100"`
+	InvoiceNumber string  `json:"invoice_number,omitempty" binding:"max=50"`
+	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	ServiceID     string  `json:"service_id,omitempty"`
+	Notes         string  `json:"notes,omitempty" binding:"max=500"`
 }
 
 // VisitPlanStep represents a simple step in visit draft treatment plan
@@ -40,31 +120,42 @@ type VisitPlanStep struct {
 
 // Visit represents a patient visit/consultation
 type Visit struct {
-	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
-	ClinicID       primitive.ObjectID  `bson:"clinic_id" json:"clinic_id"`
-	AppointmentID  *primitive.ObjectID `bson:"appointment_id,omitempty" json:"appointment_id,omitempty"`
-	PatientID      primitive.ObjectID  `bson:"patient_id" json:"patient_id"`
-	DoctorID       primitive.ObjectID  `bson:"doctor_id" json:"doctor_id"`
-	Date           string              `bson:"date" json:"date"` // YYYY-MM-DD format
-	Status         string              `bson:"status" json:"status"`
-	Diagnosis      string              `bson:"diagnosis,omitempty" json:"diagnosis,omitempty"`
-	Notes          string              `bson:"notes,omitempty" json:"notes,omitempty"`
-	Comment        string              `bson:"comment,omitempty" json:"comment,omitempty"`
-	AffectedTeeth  []string            `bson:"affected_teeth,omitempty" json:"affected_teeth,omitempty"`
-	PlanSteps      []VisitPlanStep     `bson:"plan_steps,omitempty" json:"plan_steps,omitempty"`
-	XRayImages     []string            `bson:"xray_images,omitempty" json:"xray_images,omitempty"`
-	Services       []VisitService      `bson:"services" json:"services"`
-	Subtotal       float64             `bson:"subtotal" json:"subtotal"`                               // Sum of all services
-	DiscountType   string              `bson:"discount_type,omitempty" json:"discount_type,omitempty"` // "percentage" or "fixed"
-	DiscountValue  float64             `bson:"discount_value" json:"discount_value"`
-	DiscountAmount float64             `bson:"discount_amount" json:"discount_amount"`               // Calculated discount
-	Total          float64             `bson:"total" json:"total"`                                   // Subtotal - DiscountAmount
-	DoctorShare    float64             `bson:"doctor_share" json:"doctor_share"`                     // Percentage of total
-	DoctorEarning  float64             `bson:"doctor_earning" json:"doctor_earning"`                 // Calculated earning
-	PaymentType    string              `bson:"payment_type,omitempty" json:"payment_type,omitempty"` // "cash" or "card"
-	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
-	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
-	CompletedAt    *time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ClinicID        primitive.ObjectID  `bson:"clinic_id" json:"clinic_id"`
+	AppointmentID   *primitive.ObjectID `bson:"appointment_id,omitempty" json:"appointment_id,omitempty"`
+	PatientID       primitive.ObjectID  `bson:"patient_id" json:"patient_id"`
+	DoctorID        primitive.ObjectID  `bson:"doctor_id" json:"doctor_id"`
+	Date            string              `bson:"date" json:"date"` // YYYY-MM-DD format
+	Status          string              `bson:"status" json:"status"`
+	MedicalAlerts   []MedicalAlert      `bson:"medical_alerts,omitempty" json:"medical_alerts,omitempty"` // Patient's alerts when the visit started
+	Diagnosis       string              `bson:"diagnosis,omitempty" json:"diagnosis,omitempty"`
+	Notes           string              `bson:"notes,omitempty" json:"notes,omitempty"`
+	Comment         string              `bson:"comment,omitempty" json:"comment,omitempty"`
+	AffectedTeeth   []string            `bson:"affected_teeth,omitempty" json:"affected_teeth,omitempty"`
+	ChartChanges    []ToothChartChange  `bson:"chart_changes,omitempty" json:"chart_changes,omitempty"` // Applied to the patient's tooth chart
+	PlanSteps       []VisitPlanStep     `bson:"plan_steps,omitempty" json:"plan_steps,omitempty"`
+	XRayImages      []string            `bson:"xray_images,omitempty" json:"xray_images,omitempty"`
+	Services        []VisitService      `bson:"services" json:"services"`
+	LabInvoices     []LabInvoice        `bson:"lab_invoices,omitempty" json:"lab_invoices,omitempty"`
+	Subtotal        float64             `bson:"subtotal" json:"subtotal"`                               // Sum of all services
+	DiscountType    string              `bson:"discount_type,omitempty" json:"discount_type,omitempty"` // "percentage" or "fixed"
+	DiscountValue   float64             `bson:"discount_value" json:"discount_value"`
+	DiscountAmount  float64             `bson:"discount_amount" json:"discount_amount"`                       // Calculated discount
+	Total           float64             `bson:"total" json:"total"`                                           // Subtotal - DiscountAmount
+	DoctorShare     float64             `bson:"doctor_share" json:"doctor_share"`                             // Percentage of total
+	DeductCosts     bool                `bson:"deduct_costs,omitempty" json:"deduct_costs,omitempty"`         // Doctor's share is computed on total less costs
+	Costs           float64             `bson:"costs,omitempty" json:"costs,omitempty"`                       // Material and lab costs
+	DoctorEarning   float64             `bson:"doctor_earning" json:"doctor_earning"`                         // Calculated earning
+	PaymentType     string              `bson:"payment_type,omitempty" json:"payment_type,omitempty"`         // Preferred method only; money received is in the payments ledger
+	PaidAmount      float64             `bson:"paid_amount" json:"paid_amount"`                               // Sum of ledger payments for the visit
+	InvoiceNumber   int64               `bson:"invoice_number,omitempty" json:"invoice_number,omitempty"`     // Per-clinic sequence, assigned at completion
+	ReversedAmount  float64             `bson:"reversed_amount,omitempty" json:"reversed_amount,omitempty"`   // Sum of adjustments; billed fields stay as completed
+	ReversedEarning float64             `bson:"reversed_earning,omitempty" json:"reversed_earning,omitempty"` // Doctor earning clawed back by adjustments
+	Approval        *VisitApproval      `bson:"approval,omitempty" json:"approval,omitempty"`                 // Set when discounts needed boss approval
+	Claim           *VisitClaim         `bson:"claim,omitempty" json:"claim,omitempty"`                       // Insurer's part, set at completion for insured patients
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at"`
+	CompletedAt     *time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
 }
 
 // StartVisitDTO is the input for starting a visit
@@ -73,66 +164,94 @@ type StartVisitDTO struct {
 	PatientID     string `json:"patient_id" binding:"required"`
 }
 
-// AddVisitServiceDTO is the input for adding a service to a visit
+// AddVisitServiceDTO is the input for adding a service to a visit. An
+// overridden price or a line discount requires a reason. A service done on
+// several teeth with different surfaces takes one line per tooth. A line
+// naming teeth is billed once per tooth, so its quantity, when given, must
+// match them; otherwise quantity defaults to 1.
 type AddVisitServiceDTO struct {
-	ServiceID string `json:"service_id" binding:"required"`
-	Quantity  int    `json:"quantity" binding:"required,gte=1"`
+	ServiceID     string   `json:"service_id" binding:"required"`
+	Quantity      int      `json:"quantity,omitempty" binding:"omitempty,gte=1"`
+	Teeth         []string `json:"teeth,omitempty"`    // FDI numbers, e.g. ["36", "37"]
+	Surfaces      []string `json:"surfaces,omitempty"` // M, D, O, I, B, L; requires teeth
+	PriceOverride *float64 `json:"price_override,omitempty" binding:"omitempty,gte=0"`
+	DiscountType  string   `json:"discount_type,omitempty" binding:"omitempty,oneof=percentage fixed"`
+	DiscountValue float64  `json:"discount_value,omitempty" binding:"omitempty,gte=0"`
+	Reason        string   `json:"reason,omitempty" binding:"max=500"`
 }
 
 // CompleteVisitDTO is the input for completing a visit
 type CompleteVisitDTO struct {
-	Diagnosis     string               `json:"diagnosis,omitempty"`
-	Notes         string               `json:"notes,omitempty"`
-	Services      []AddVisitServiceDTO `json:"services" binding:"required,dive"`
-	DiscountType  string               `json:"discount_type,omitempty" binding:"omitempty,oneof=percentage fixed"`
-	DiscountValue float64              `json:"discount_value,omitempty" binding:"omitempty,gte=0"`
-	PaymentType   string               `json:"payment_type" binding:"required,oneof=cash card"`
-	AffectedTeeth []string             `json:"affected_teeth,omitempty"`
-	XRayImages    []string             `json:"xray_images,omitempty"`
+	Diagnosis     string                `json:"diagnosis,omitempty"`
+	Notes         string                `json:"notes,omitempty"`
+	Services      []AddVisitServiceDTO  `json:"services" binding:"required,dive"`
+	DiscountType  string                `json:"discount_type,omitempty" binding:"omitempty,oneof=percentage fixed"`
+	DiscountValue float64               `json:"discount_value,omitempty" binding:"omitempty,gte=0"`
+	PaymentType   string                `json:"payment_type,omitempty" binding:"omitempty,max=32"` // Preferred method code; payments are recorded separately
+	AffectedTeeth []string              `json:"affected_teeth,omitempty"`
+	ChartChanges  []ToothChartChangeDTO `json:"chart_changes,omitempty" binding:"omitempty,dive"` // Replaces the draft's chart changes when present
+	XRayImages    []string              `json:"xray_images,omitempty"`
+	LabInvoices   []LabInvoiceDTO       `json:"lab_invoices,omitempty" binding:"omitempty,dive"` // Replaces the draft's invoices when present
 	// DoctorShare is now determined by the active doctor contract, not submitted by the doctor
 }
 
 // SaveVisitDraftDTO is the input for saving visit draft/progress
 type SaveVisitDraftDTO struct {
-	Diagnosis     string               `json:"diagnosis,omitempty"`
-	Services      []AddVisitServiceDTO `json:"services,omitempty"`
-	DiscountType  string               `json:"discount_type,omitempty" binding:"omitempty,oneof=percentage fixed"`
-	DiscountValue float64              `json:"discount_value,omitempty" binding:"omitempty,gte=0"`
-	PaymentType   string               `json:"payment_type,omitempty" binding:"omitempty,oneof=cash card"`
-	AffectedTeeth []string             `json:"affected_teeth,omitempty"`
-	PlanSteps     []VisitPlanStep      `json:"plan_steps,omitempty"`
-	Comment       string               `json:"comment,omitempty"`
-	XRayImages    []string             `json:"xray_images,omitempty"`
+	Diagnosis     string                `json:"diagnosis,omitempty"`
+	Services      []AddVisitServiceDTO  `json:"services,omitempty"`
+	DiscountType  string                `json:"discount_type,omitempty" binding:"omitempty,oneof=percentage fixed"`
+	DiscountValue float64               `json:"discount_value,omitempty" binding:"omitempty,gte=0"`
+	PaymentType   string                `json:"payment_type,omitempty" binding:"omitempty,max=32"`
+	AffectedTeeth []string              `json:"affected_teeth,omitempty"`
+	ChartChanges  []ToothChartChangeDTO `json:"chart_changes,omitempty" binding:"omitempty,dive"` // Replaces the draft's chart changes when present; [] clears them
+	PlanSteps     []VisitPlanStep       `json:"plan_steps,omitempty"`
+	Comment       string                `json:"comment,omitempty"`
+	XRayImages    []string              `json:"xray_images,omitempty"`
+	LabInvoices   []LabInvoiceDTO       `json:"lab_invoices,omitempty" binding:"omitempty,dive"` // Replaces the draft's invoices when present; [] clears them
 }
 
 // VisitResponse is the API response for a visit
 type VisitResponse struct {
-	ID             string          `json:"id"`
-	AppointmentID  string          `json:"appointment_id,omitempty"`
-	PatientID      string          `json:"patient_id"`
-	PatientName    string          `json:"patient_name,omitempty"`
-	DoctorID       string          `json:"doctor_id"`
-	DoctorName     string          `json:"doctor_name,omitempty"`
-	Date           string          `json:"date"`
-	Status         string          `json:"status"`
-	Diagnosis      string          `json:"diagnosis,omitempty"`
-	Notes          string          `json:"notes,omitempty"`
-	Comment        string          `json:"comment,omitempty"`
-	AffectedTeeth  []string        `json:"affected_teeth,omitempty"`
-	PlanSteps      []VisitPlanStep `json:"plan_steps,omitempty"`
-	XRayImages     []string        `json:"xray_images,omitempty"`
-	Services       []VisitService  `json:"services"`
-	Subtotal       float64         `json:"subtotal"`
-	DiscountType   string          `json:"discount_type,omitempty"`
-	DiscountValue  float64         `json:"discount_value"`
-	DiscountAmount float64         `json:"discount_amount"`
-	Total          float64         `json:"total"`
-	TotalAmount    float64         `json:"total_amount"`
-	DoctorShare    float64         `json:"doctor_share"`
-	DoctorEarning  float64         `json:"doctor_earning"`
-	PaymentType    string          `json:"payment_type,omitempty"`
-	CreatedAt      time.Time       `json:"created_at"`
-	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+	ID             string             `json:"id"`
+	AppointmentID  string             `json:"appointment_id,omitempty"`
+	PatientID      string             `json:"patient_id"`
+	PatientName    string             `json:"patient_name,omitempty"`
+	DoctorID       string             `json:"doctor_id"`
+	DoctorName     string             `json:"doctor_name,omitempty"`
+	Date           string             `json:"date"`
+	Status         string             `json:"status"`
+	MedicalAlerts  []MedicalAlert     `json:"medical_alerts,omitempty"`
+	Diagnosis      string             `json:"diagnosis,omitempty"`
+	Notes          string             `json:"notes,omitempty"`
+	Comment        string             `json:"comment,omitempty"`
+	AffectedTeeth  []string           `json:"affected_teeth,omitempty"`
+	ChartChanges   []ToothChartChange `json:"chart_changes,omitempty"`
+	PlanSteps      []VisitPlanStep    `json:"plan_steps,omitempty"`
+	XRayImages     []string           `json:"xray_images,omitempty"`
+	Services       []VisitService     `json:"services"`
+	LabInvoices    []LabInvoice       `json:"lab_invoices,omitempty"`
+	Subtotal       float64            `json:"subtotal"`
+	DiscountType   string             `json:"discount_type,omitempty"`
+	DiscountValue  float64            `json:"discount_value"`
+	DiscountAmount float64            `json:"discount_amount"`
+	Total          float64            `json:"total"`
+	TotalAmount    float64            `json:"total_amount"`
+	DoctorShare    float64            `json:"doctor_share"`
+	DoctorEarning  float64            `json:"doctor_earning"`
+	Costs          float64            `json:"costs,omitempty"`
+	PaymentType    string             `json:"payment_type,omitempty"`
+	PaidAmount     float64            `json:"paid_amount"`
+	Outstanding    float64            `json:"outstanding"`
+	PaymentStatus  string             `json:"payment_status,omitempty"` // Only for completed visits
+	InvoiceNumber  string             `json:"invoice_number,omitempty"`
+	ReversedAmount float64            `json:"reversed_amount,omitempty"`
+	NetTotal       float64            `json:"net_total"` // Total less reversals
+	PayerShare     float64            `json:"payer_share,omitempty"`
+	PatientShare   float64            `json:"patient_share"`
+	Claim          *VisitClaim        `json:"claim,omitempty"`
+	Approval       *VisitApproval     `json:"approval,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	CompletedAt    *time.Time         `json:"completed_at,omitempty"`
 }
 
 // ToResponse converts Visit to VisitResponse
@@ -143,13 +262,16 @@ func (v *Visit) ToResponse() VisitResponse {
 		DoctorID:       v.DoctorID.Hex(),
 		Date:           v.Date,
 		Status:         v.Status,
+		MedicalAlerts:  v.MedicalAlerts,
 		Diagnosis:      v.Diagnosis,
 		Notes:          v.Notes,
 		Comment:        v.Comment,
 		AffectedTeeth:  v.AffectedTeeth,
+		ChartChanges:   v.ChartChanges,
 		PlanSteps:      v.PlanSteps,
 		XRayImages:     v.XRayImages,
 		Services:       v.Services,
+		LabInvoices:    v.LabInvoices,
 		Subtotal:       v.Subtotal,
 		DiscountType:   v.DiscountType,
 		DiscountValue:  v.DiscountValue,
@@ -158,21 +280,119 @@ func (v *Visit) ToResponse() VisitResponse {
 		TotalAmount:    v.Total,
 		DoctorShare:    v.DoctorShare,
 		DoctorEarning:  v.DoctorEarning,
+		Costs:          v.Costs,
 		PaymentType:    v.PaymentType,
+		PaidAmount:     v.PaidAmount,
+		ReversedAmount: v.ReversedAmount,
+		NetTotal:       v.NetTotal(),
+		PayerShare:     v.PayerShare(),
+		PatientShare:   v.PatientShare(),
+		Claim:          v.Claim,
+		Approval:       v.Approval,
 		CreatedAt:      v.CreatedAt,
 		CompletedAt:    v.CompletedAt,
 	}
 	if v.AppointmentID != nil {
 		resp.AppointmentID = v.AppointmentID.Hex()
 	}
+	if v.InvoiceNumber > 0 {
+		resp.InvoiceNumber = FormatDocumentNumber(InvoicePrefix, v.InvoiceNumber)
+	}
+	if v.Status == VisitStatusCompleted {
+		resp.Outstanding = v.Outstanding()
+		resp.PaymentStatus = v.PaymentStatus()
+	}
 	return resp
 }
 
+// NetTotal returns the billed total less reversed amounts
+func (v *Visit) NetTotal() float64 {
+	if v.Total-v.ReversedAmount < MoneyEpsilon {
+		return 0
+	}
+	return v.Total - v.ReversedAmount
+}
+
+// NetEarning returns the doctor earning less clawed-back amounts
+func (v *Visit) NetEarning() float64 {
+	if v.DoctorEarning-v.ReversedEarning < MoneyEpsilon {
+		return 0
+	}
+	return v.DoctorEarning - v.ReversedEarning
+}
+
+// PayerShare returns the insurer's part of the net total. Reversals reduce
+// it in proportion; a rejected claim leaves the whole visit to the patient.
+func (v *Visit) PayerShare() float64 {
+	return v.NetTotal() - v.PatientShareOf(v.NetTotal())
+}
+
+// PatientShare returns the patient's part of the net total
+func (v *Visit) PatientShare() float64 {
+	return v.PatientShareOf(v.NetTotal())
+}
+
+// PatientShareOf returns the patient's part of a net total under the visit's claim
+func (v *Visit) PatientShareOf(net float64) float64 {
+	if v.Claim == nil || v.Claim.Status == ClaimStatusRejected || v.Total <= 0 {
+		return net
+	}
+	return net - v.Claim.Amount*net/v.Total
+}
+
+// Outstanding returns the part of the patient's share not yet paid
+func (v *Visit) Outstanding() float64 {
+	if v.PatientShare()-v.PaidAmount < MoneyEpsilon {
+		return 0
+	}
+	return v.PatientShare() - v.PaidAmount
+}
+
+// PaymentStatus returns unpaid, partial, paid or voided
+func (v *Visit) PaymentStatus() string {
+	switch {
+	case v.ReversedAmount > 0 && v.NetTotal() == 0:
+		return PaymentStatusVoided
+	case v.Outstanding() == 0:
+		return PaymentStatusPaid
+	case v.PaidAmount < MoneyEpsilon:
+		return PaymentStatusUnpaid
+	default:
+		return PaymentStatusPartial
+	}
+}
+
+// ListSubtotal returns the visit's services at list price
+func (v *Visit) ListSubtotal() float64 {
+	list := 0.0
+	for _, l := range v.Services {
+		list += l.Price * float64(l.Quantity)
+	}
+	return list
+}
+
+// MaxReductionPercent returns the largest reduction below list price on the
+// visit: the deepest line, or the visit as a whole including its discount
+func (v *Visit) MaxReductionPercent() float64 {
+	max := 0.0
+	for i := range v.Services {
+		if r := v.Services[i].ReductionPercent(); r > max {
+			max = r
+		}
+	}
+	if list := v.ListSubtotal(); list > 0 && v.Total < list {
+		if r := (list - v.Total) / list * 100; r > max {
+			max = r
+		}
+	}
+	return max
+}
+
 // CalculateTotal calculates the visit totals
 func (v *Visit) CalculateTotal() {
 	v.Subtotal = 0
 	for i := range v.Services {
-		v.Services[i].Subtotal = v.Services[i].Price * float64(v.Services[i].Quantity)
+		v.Services[i].Calculate()
 		v.Subtotal += v.Services[i].Subtotal
 	}
 
@@ -196,6 +416,75 @@ func (v *Visit) CalculateTotal() {
 		v.Total = 0
 	}
 
-	// Calculate doctor earning
-	v.DoctorEarning = v.Total * (v.DoctorShare / 100)
+	// Calculate doctor earning, on each line's amount less its costs when
+	// the contract deducts them
+	v.Costs = 0
+	v.DoctorEarning = 0
+	for i := range v.Services {
+		v.Costs += v.LineCosts(i)
+		v.DoctorEarning += v.EarningBase(i) * (v.DoctorShare / 100)
+	}
+	if v.Subtotal <= 0 {
+		// Nothing to spread unassigned lab work over
+		for _, inv := range v.LabInvoices {
+			if inv.ServiceID == nil {
+				v.Costs += inv.Amount
+			}
+		}
+	}
+}
+
+// LineCosts returns the material and lab costs of a line: its materials, its
+// part of the lab invoices for its service and of the other lab invoices.
+// Invoices are split by subtotal so each is counted once over the visit.
+func (v *Visit) LineCosts(i int) float64 {
+	line := &v.Services[i]
+	costs := line.Cost * float64(line.Quantity)
+	for _, inv := range v.LabInvoices {
+		switch {
+		case inv.ServiceID != nil && *inv.ServiceID == line.ServiceID:
+			costs += inv.Amount * v.serviceShare(i)
+		case inv.ServiceID == nil && v.Subtotal > 0:
+			costs += inv.Amount * line.Subtotal / v.Subtotal
+		}
+	}
+	return costs
+}
+
+// serviceShare returns line i's part of the lines billing the same service,
+// by subtotal, or an equal part when they are all free
+func (v *Visit) serviceShare(i int) float64 {
+	var subtotal float64
+	count := 0
+	for _, l := range v.Services {
+		if l.ServiceID == v.Services[i].ServiceID {
+			subtotal += l.Subtotal
+			count++
+		}
+	}
+	if subtotal > 0 {
+		return v.Services[i].Subtotal / subtotal
+	}
+	return 1 / float64(count)
+}
+
+// NetLineAmount returns a line's share of the visit total, i.e. its subtotal
+// less its part of the visit discount
+func (v *Visit) NetLineAmount(i int) float64 {
+	if v.Subtotal <= 0 {
+		return 0
+	}
+	return v.Services[i].Subtotal * v.Total / v.Subtotal
+}
+
+// EarningBase returns the amount a percentage share of a line is paid on
+func (v *Visit) EarningBase(i int) float64 {
+	base := v.NetLineAmount(i)
+	if v.DeductCosts {
+		base -= v.LineCosts(i)
+	}
+	if base < 0 {
+		return 0
+	}
+	return base
 }
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Approval decisions
+const (
+	ApprovalDecisionApproved = "approved"
+	ApprovalDecisionRejected = "rejected"
+)
+
+// DiscountPolicy limits the price reductions a doctor may grant without boss
+// approval. A clinic without a policy has no limit.
+type DiscountPolicy struct {
+	MaxDoctorDiscount float64 `bson:"max_doctor_discount" json:"max_doctor_discount"` // Percent of list price, per line and per visit
+}
+
+// UpdateDiscountPolicyDTO sets the clinic's discount policy; a null limit removes it
+type UpdateDiscountPolicyDTO struct {
+	MaxDoctorDiscount *float64 `json:"max_doctor_discount" binding:"omitempty,gte=0,lte=100"`
+}
+
+// DiscountPolicyResponse is the API response for the discount policy
+type DiscountPolicyResponse struct {
+	MaxDoctorDiscount *float64 `json:"max_doctor_discount"` // Null when doctors are not limited
+}
+
+// VisitApproval records why a visit was held for boss approval and the decision
+type VisitApproval struct {
+	Discount    float64             `bson:"discount" json:"discount"`         // Largest reduction requested, percent of list price
+	MaxDiscount float64             `bson:"max_discount" json:"max_discount"` // Policy limit when requested
+	RequestedAt time.Time           `bson:"requested_at" json:"requested_at"`
+	Decision    string              `bson:"decision,omitempty" json:"decision,omitempty"`
+	DecidedBy   *primitive.ObjectID `bson:"decided_by,omitempty" json:"decided_by,omitempty"`
+	DecidedAt   *time.Time          `bson:"decided_at,omitempty" json:"decided_at,omitempty"`
+	Note        string              `bson:"note,omitempty" json:"note,omitempty"`
+}
+
+// VisitApprovalDecisionDTO is the boss's decision on a pending visit
+type VisitApprovalDecisionDTO struct {
+	Note string `json:"note,omitempty" binding:"max=500"`
+}
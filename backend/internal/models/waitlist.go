@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WaitlistStatus constants
+const (
+	WaitlistStatusWaiting   = "waiting"
+	WaitlistStatusOffered   = "offered"
+	WaitlistStatusBooked    = "booked"
+	WaitlistStatusCancelled = "cancelled"
+)
+
+// WaitlistOffer is a freed slot offered to a waitlist entry
+type WaitlistOffer struct {
+	AppointmentID primitive.ObjectID `bson:"appointment_id" json:"appointment_id"` // Cancelled/no-show appointment that freed the slot
+	DoctorID      primitive.ObjectID `bson:"doctor_id" json:"doctor_id"`
+	StartTime     time.Time          `bson:"start_time" json:"start_time"`
+	EndTime       time.Time          `bson:"end_time" json:"end_time"`
+	OfferedAt     time.Time          `bson:"offered_at" json:"offered_at"`
+	ExpiresAt     time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// WaitlistEntry is a patient waiting for an earlier or any free slot
+type WaitlistEntry struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ClinicID  primitive.ObjectID  `bson:"clinic_id" json:"clinic_id"`
+	PatientID primitive.ObjectID  `bson:"patient_id" json:"patient_id"`
+	DoctorID  *primitive.ObjectID `bson:"doctor_id,omitempty" json:"doctor_id,omitempty"` // Preferred doctor, any if empty
+	FromDate  string              `bson:"from_date" json:"from_date"`                     // YYYY-MM-DD, clinic-local
+	ToDate    string              `bson:"to_date" json:"to_date"`                         // YYYY-MM-DD inclusive
+	TimeFrom  string              `bson:"time_from,omitempty" json:"time_from,omitempty"` // HH:MM, earliest start
+	TimeTo    string              `bson:"time_to,omitempty" json:"time_to,omitempty"`     // HH:MM, latest end
+	Status    string              `bson:"status" json:"status"`
+	Offer     *WaitlistOffer      `bson:"offer,omitempty" json:"offer,omitempty"`
+	Notes     string              `bson:"notes,omitempty" json:"notes,omitempty"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `bson:"updated_at" json:"updated_at"`
+	CreatedBy primitive.ObjectID  `bson:"created_by" json:"created_by"`
+}
+
+// CreateWaitlistEntryDTO is the input for adding a patient to the waitlist
+type CreateWaitlistEntryDTO struct {
+	PatientID string `json:"patient_id" binding:"required"`
+	DoctorID  string `json:"doctor_id,omitempty"`
+	FromDate  string `json:"from_date" binding:"required"`
+	ToDate    string `json:"to_date" binding:"required"`
+	TimeFrom  string `json:"time_from,omitempty"`
+	TimeTo    string `json:"time_to,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+}
+
+// WaitlistEntryResponse is the API response for a waitlist entry
+type WaitlistEntryResponse struct {
+	ID          string         `json:"id"`
+	PatientID   string         `json:"patient_id"`
+	PatientName string         `json:"patient_name,omitempty"`
+	DoctorID    string         `json:"doctor_id,omitempty"`
+	FromDate    string         `json:"from_date"`
+	ToDate      string         `json:"to_date"`
+	TimeFrom    string         `json:"time_from,omitempty"`
+	TimeTo      string         `json:"time_to,omitempty"`
+	Status      string         `json:"status"`
+	Offer       *WaitlistOffer `json:"offer,omitempty"`
+	Notes       string         `json:"notes,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// ToResponse converts WaitlistEntry to WaitlistEntryResponse
+func (w *WaitlistEntry) ToResponse() WaitlistEntryResponse {
+	resp := WaitlistEntryResponse{
+		ID:        w.ID.Hex(),
+		PatientID: w.PatientID.Hex(),
+		FromDate:  w.FromDate,
+		ToDate:    w.ToDate,
+		TimeFrom:  w.TimeFrom,
+		TimeTo:    w.TimeTo,
+		Status:    w.Status,
+		Offer:     w.Offer,
+		Notes:     w.Notes,
+		CreatedAt: w.CreatedAt,
+	}
+	if w.DoctorID != nil {
+		resp.DoctorID = w.DoctorID.Hex()
+	}
+	return resp
+}
+
+// Matches reports whether a slot fits the entry's doctor, date range and
+// time-of-day preference. start and end must be in the clinic's location.
+func (w *WaitlistEntry) Matches(doctorID primitive.ObjectID, start, end time.Time) bool {
+	if w.DoctorID != nil && *w.DoctorID != doctorID {
+		return false
+	}
+
+	date := start.Format("2006-01-02")
+	if date < w.FromDate || date > w.ToDate {
+		return false
+	}
+
+	startMin := start.Hour()*60 + start.Minute()
+	endMin := end.Hour()*60 + end.Minute()
+	if w.TimeFrom != "" {
+		if from, err := ParseClock(w.TimeFrom); err == nil && startMin < from {
+			return false
+		}
+	}
+	if w.TimeTo != "" {
+		if to, err := ParseClock(w.TimeTo); err == nil && endMin > to {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TimeRange is a wall-clock interval within a day in the clinic's timezone
+type TimeRange struct {
+	Start string `bson:"start" json:"start" binding:"required"` // HH:MM
+	End   string `bson:"end" json:"end" binding:"required"`     // HH:MM
+}
+
+// WorkDay describes a doctor's shifts and breaks on one weekday
+type WorkDay struct {
+	Weekday int         `bson:"weekday" json:"weekday" binding:"min=0,max=6"` // 0 = Sunday
+	Shifts  []TimeRange `bson:"shifts" json:"shifts" binding:"required,min=1,dive"`
+	Breaks  []TimeRange `bson:"breaks,omitempty" json:"breaks,omitempty" binding:"omitempty,dive"`
+}
+
+// WorkSchedule is a doctor's weekly working schedule.
+// Weekdays that are not listed are days off.
+type WorkSchedule struct {
+	Days      []WorkDay `bson:"days" json:"days"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// UpdateWorkScheduleDTO is the input for setting a doctor's weekly schedule
+type UpdateWorkScheduleDTO struct {
+	Days []WorkDay `json:"days" binding:"dive"`
+}
+
+// Interval is an absolute time interval
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// DayAvailability lists free slots of a doctor on one clinic-local date
+type DayAvailability struct {
+	Date  string     `json:"date"` // YYYY-MM-DD
+	Slots []Interval `json:"slots"`
+}
+
+// DoctorAvailabilityResponse is the API response for doctor availability
+type DoctorAvailabilityResponse struct {
+	DoctorID string            `json:"doctor_id"`
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Duration int               `json:"duration"` // Requested slot length in minutes
+	Days     []DayAvailability `json:"days"`
+}
+
+// ParseClock parses HH:MM into minutes since midnight
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validate checks that shifts and breaks are well-formed and that
+// each weekday appears at most once
+func (ws *WorkSchedule) Validate() error {
+	seen := make(map[int]bool)
+	for _, day := range ws.Days {
+		if day.Weekday < 0 || day.Weekday > 6 {
+			return fmt.Errorf("weekday must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		if seen[day.Weekday] {
+			return fmt.Errorf("weekday %d is listed more than once", day.Weekday)
+		}
+		seen[day.Weekday] = true
+
+		shifts, err := clockRanges(day.Shifts)
+		if err != nil {
+			return err
+		}
+		for i := 1; i < len(shifts); i++ {
+			if shifts[i][0] < shifts[i-1][1] {
+				return fmt.Errorf("shifts overlap on weekday %d", day.Weekday)
+			}
+		}
+
+		breaks, err := clockRanges(day.Breaks)
+		if err != nil {
+			return err
+		}
+		for _, b := range breaks {
+			inside := false
+			for _, sh := range shifts {
+				if b[0] >= sh[0] && b[1] <= sh[1] {
+					inside = true
+					break
+				}
+			}
+			if !inside {
+				return fmt.Errorf("break must lie within a shift on weekday %d", day.Weekday)
+			}
+		}
+	}
+	return nil
+}
+
+// Day returns the schedule for a weekday, or nil on a day off
+func (ws *WorkSchedule) Day(weekday time.Weekday) *WorkDay {
+	for i := range ws.Days {
+		if ws.Days[i].Weekday == int(weekday) {
+			return &ws.Days[i]
+		}
+	}
+	return nil
+}
+
+// WorkingIntervals returns the absolute working intervals (shifts minus breaks)
+// for the clinic-local calendar day containing date
+func (ws *WorkSchedule) WorkingIntervals(date time.Time, loc *time.Location) []Interval {
+	date = date.In(loc)
+	day := ws.Day(date.Weekday())
+	if day == nil {
+		return nil
+	}
+
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	at := func(minutes int) time.Time {
+		return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), minutes/60, minutes%60, 0, 0, loc)
+	}
+
+	shifts, _ := clockRanges(day.Shifts)
+	breaks, _ := clockRanges(day.Breaks)
+
+	var intervals []Interval
+	for _, sh := range shifts {
+		intervals = append(intervals, Interval{Start: at(sh[0]), End: at(sh[1])})
+	}
+	for _, b := range breaks {
+		intervals = SubtractInterval(intervals, Interval{Start: at(b[0]), End: at(b[1])})
+	}
+	return intervals
+}
+
+// SubtractInterval removes cut from every interval in the list
+func SubtractInterval(intervals []Interval, cut Interval) []Interval {
+	result := make([]Interval, 0, len(intervals))
+	for _, iv := range intervals {
+		if !cut.Start.Before(iv.End) || !cut.End.After(iv.Start) {
+			result = append(result, iv)
+			continue
+		}
+		if cut.Start.After(iv.Start) {
+			result = append(result, Interval{Start: iv.Start, End: cut.Start})
+		}
+		if cut.End.Before(iv.End) {
+			result = append(result, Interval{Start: cut.End, End: iv.End})
+		}
+	}
+	return result
+}
+
+// ContainsInterval reports whether candidate lies entirely within one of the intervals
+func ContainsInterval(intervals []Interval, candidate Interval) bool {
+	for _, iv := range intervals {
+		if !candidate.Start.Before(iv.Start) && !candidate.End.After(iv.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// clockRanges parses and sorts a list of HH:MM ranges into minute pairs
+func clockRanges(ranges []TimeRange) ([][2]int, error) {
+	result := make([][2]int, 0, len(ranges))
+	for _, r := range ranges {
+		start, err := ParseClock(r.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := ParseClock(r.End)
+		if err != nil {
+			return nil, err
+		}
+		if end <= start {
+			return nil, fmt.Errorf("range %s-%s must end after it starts", r.Start, r.End)
+		}
+		result = append(result, [2]int{start, end})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i][0] < result[j][0] })
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type VisitAdjustmentRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewVisitAdjustmentRepository(db *mongo.Database, timeout time.Duration) *VisitAdjustmentRepository {
+	return &VisitAdjustmentRepository{
+		collection: db.Collection("visit_adjustments"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts an adjustment document
+func (r *VisitAdjustmentRepository) Create(ctx context.Context, adjustment *models.VisitAdjustment) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	adjustment.CreatedAt = time.Now().UTC()
+	if adjustment.Lines == nil {
+		adjustment.Lines = []models.AdjustmentLine{}
+	}
+
+	result, err := r.collection.InsertOne(ctx, adjustment)
+	if err != nil {
+		return err
+	}
+
+	adjustment.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Delete removes an adjustment; only used to undo a partially applied one
+func (r *VisitAdjustmentRepository) Delete(ctx context.Context, id, clinicID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "clinic_id": clinicID})
+	return err
+}
+
+// ListByVisit returns a visit's adjustments, oldest first
+func (r *VisitAdjustmentRepository) ListByVisit(ctx context.Context, visitID, clinicID primitive.ObjectID) ([]models.VisitAdjustment, error) {
+	return r.find(ctx, bson.M{"clinic_id": clinicID, "visit_id": visitID})
+}
+
+// ListByDateRange returns adjustments posted in a clinic-local date range (inclusive), oldest first
+func (r *VisitAdjustmentRepository) ListByDateRange(ctx context.Context, clinicID primitive.ObjectID, fromDate, toDate string) ([]models.VisitAdjustment, error) {
+	return r.find(ctx, bson.M{
+		"clinic_id": clinicID,
+		"date":      bson.M{"$gte": fromDate, "$lte": toDate},
+	})
+}
+
+// ListByDoctorAndDateRange returns a doctor's adjustments posted in a
+// clinic-local date range (inclusive), oldest first
+func (r *VisitAdjustmentRepository) ListByDoctorAndDateRange(ctx context.Context, clinicID, doctorID primitive.ObjectID, fromDate, toDate string) ([]models.VisitAdjustment, error) {
+	return r.find(ctx, bson.M{
+		"clinic_id": clinicID,
+		"doctor_id": doctorID,
+		"date":      bson.M{"$gte": fromDate, "$lte": toDate},
+	})
+}
+
+// SumEarningReversalsByDoctor sums the earnings clawed back from doctors by
+// adjustments posted in a clinic-local date range (inclusive)
+func (r *VisitAdjustmentRepository) SumEarningReversalsByDoctor(ctx context.Context, clinicID primitive.ObjectID, fromDate, toDate string) (map[primitive.ObjectID]float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"clinic_id": clinicID,
+			"date":      bson.M{"$gte": fromDate, "$lte": toDate},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$doctor_id",
+			"reversal": bson.M{"$sum": "$earning_reversal"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	result := make(map[primitive.ObjectID]float64)
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Reversal float64            `bson:"reversal"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		result[doc.ID] = doc.Reversal
+	}
+	return result, cursor.Err()
+}
+
+func (r *VisitAdjustmentRepository) find(ctx context.Context, filter bson.M) ([]models.VisitAdjustment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var adjustments []models.VisitAdjustment
+	if err = cursor.All(ctx, &adjustments); err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
@@ -24,19 +24,34 @@ func NewAppointmentRepository(db *mongo.Database, timeout time.Duration) *Appoin
 	}
 }
 
-// Create creates an appointment using atomic operation to prevent overlaps
-// The unique index on (clinic_id, doctor_id, start_time) ensures no double-booking
+// Create creates an appointment. Callers are expected to run CheckOverlap first;
+// the unique index on (clinic_id, doctor_id, start_time) additionally rejects
+// concurrent inserts at the same start time.
+// EndTime defaults to StartTime + SlotDuration, Status to scheduled and Date
+// to the UTC date of StartTime when not set by the caller; services pass the
+// clinic-local date.
 func (r *AppointmentRepository) Create(ctx context.Context, appointment *models.Appointment) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
 	appointment.CreatedAt = time.Now().UTC()
 	appointment.UpdatedAt = appointment.CreatedAt
-	appointment.EndTime = appointment.StartTime.Add(models.SlotDuration)
-	appointment.Status = models.AppointmentStatusScheduled
+	if !appointment.EndTime.After(appointment.StartTime) {
+		appointment.EndTime = appointment.StartTime.Add(models.SlotDuration)
+	}
+	if appointment.Status == "" {
+		appointment.Status = models.AppointmentStatusScheduled
+	}
+	appointment.StatusHistory = []models.AppointmentStatusChange{{
+		To:        appointment.Status,
+		ChangedBy: appointment.CreatedBy,
+		ChangedAt: appointment.CreatedAt,
+	}}
 
 	// Format date for indexing
-	appointment.Date = appointment.StartTime.UTC().Format("2006-01-02")
+	if appointment.Date == "" {
+		appointment.Date = appointment.StartTime.UTC().Format("2006-01-02")
+	}
 
 	result, err := r.collection.InsertOne(ctx, appointment)
 	if err != nil {
@@ -69,16 +84,19 @@ func (r *AppointmentRepository) GetByID(ctx context.Context, id, clinicID primit
 	return &appointment, nil
 }
 
-// CheckOverlap checks if a time slot is available for a doctor
-func (r *AppointmentRepository) CheckOverlap(ctx context.Context, clinicID, doctorID primitive.ObjectID, startTime time.Time, excludeID *primitive.ObjectID) (bool, error) {
+// CheckOverlap checks if the interval [startTime, endTime) intersects any
+// active appointment of the doctor
+func (r *AppointmentRepository) CheckOverlap(ctx context.Context, clinicID, doctorID primitive.ObjectID, startTime, endTime time.Time, excludeID *primitive.ObjectID) (bool, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
+	// Two intervals intersect when each one starts before the other ends
 	filter := bson.M{
 		"clinic_id":  clinicID,
 		"doctor_id":  doctorID,
-		"start_time": startTime,
-		"status":     bson.M{"$nin": []string{models.AppointmentStatusCancelled}},
+		"start_time": bson.M{"$lt": endTime},
+		"end_time":   bson.M{"$gt": startTime},
+		"status":     bson.M{"$nin": []string{models.AppointmentStatusCancelled, models.AppointmentStatusNoShow}},
 	}
 
 	// Exclude the current appointment when rescheduling
@@ -153,6 +171,38 @@ func (r *AppointmentRepository) ListByDoctorRange(ctx context.Context, clinicID,
 	return appointments, nil
 }
 
+// ListByInterval returns active appointments intersecting [from, to),
+// optionally restricted to one doctor
+func (r *AppointmentRepository) ListByInterval(ctx context.Context, clinicID primitive.ObjectID, doctorID *primitive.ObjectID, from, to time.Time) ([]models.Appointment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":  clinicID,
+		"start_time": bson.M{"$lt": to},
+		"end_time":   bson.M{"$gt": from},
+		"status":     bson.M{"$nin": []string{models.AppointmentStatusCancelled, models.AppointmentStatusNoShow}},
+	}
+	if doctorID != nil {
+		filter["doctor_id"] = *doctorID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var appointments []models.Appointment
+	if err = cursor.All(ctx, &appointments); err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
 // ListByClinicAndDate returns all appointments for a clinic on a date
 func (r *AppointmentRepository) ListByClinicAndDate(ctx context.Context, clinicID primitive.ObjectID, date string, page, pageSize int) ([]models.Appointment, int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
@@ -246,7 +296,9 @@ func (r *AppointmentRepository) ListByClinicAndDateRange(ctx context.Context, cl
 	return appointments, total, nil
 }
 
-// Update updates an appointment
+// Update updates an appointment. The write only applies while the stored
+// status still equals appointment.Status; otherwise mongo.ErrNoDocuments is
+// returned so a concurrent status change is not overwritten.
 func (r *AppointmentRepository) Update(ctx context.Context, appointment *models.Appointment) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
@@ -256,67 +308,207 @@ func (r *AppointmentRepository) Update(ctx context.Context, appointment *models.
 	filter := bson.M{
 		"_id":       appointment.ID,
 		"clinic_id": appointment.ClinicID,
+		"status":    appointment.Status,
 	}
 
-	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": appointment})
-	return err
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": appointment})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
-// Reschedule atomically reschedules an appointment to a new time
-func (r *AppointmentRepository) Reschedule(ctx context.Context, id, clinicID primitive.ObjectID, newStartTime time.Time) error {
+// TransitionStatus moves an appointment from one status to another and
+// appends the change to its status history. It returns mongo.ErrNoDocuments
+// when the appointment is no longer in status from, so concurrent transitions
+// cannot both succeed.
+func (r *AppointmentRepository) TransitionStatus(ctx context.Context, id, clinicID primitive.ObjectID, from, to string, actorID primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	// Get the existing appointment
-	existing, err := r.GetByID(ctx, id, clinicID)
-	if err != nil {
-		return err
+	now := time.Now().UTC()
+	filter := bson.M{
+		"_id":       id,
+		"clinic_id": clinicID,
+		"status":    from,
 	}
 
-	// Delete the old and create new in a transaction-like manner
-	// First, update the old appointment to cancelled
-	_, err = r.collection.UpdateOne(
+	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": id, "clinic_id": clinicID},
-		bson.M{"$set": bson.M{
-			"status":     models.AppointmentStatusCancelled,
-			"updated_at": time.Now().UTC(),
-		}},
+		filter,
+		bson.M{
+			"$set": bson.M{
+				"status":     to,
+				"updated_at": now,
+			},
+			"$push": bson.M{"status_history": models.AppointmentStatusChange{
+				From:      from,
+				To:        to,
+				ChangedBy: actorID,
+				ChangedAt: now,
+			}},
+		},
 	)
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ListBySeries returns all occurrences of a series ordered by start time
+func (r *AppointmentRepository) ListBySeries(ctx context.Context, clinicID, seriesID primitive.ObjectID) ([]models.Appointment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id": clinicID,
+		"series_id": seriesID,
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
 
-	// Create new appointment
-	newAppointment := &models.Appointment{
-		ClinicID:  clinicID,
-		PatientID: existing.PatientID,
-		DoctorID:  existing.DoctorID,
-		StartTime: newStartTime,
-		Notes:     existing.Notes,
-		CreatedBy: existing.CreatedBy,
+	var appointments []models.Appointment
+	if err = cursor.All(ctx, &appointments); err != nil {
+		return nil, err
 	}
 
-	return r.Create(ctx, newAppointment)
+	return appointments, nil
 }
 
-// UpdateStatus updates only the status of an appointment
-func (r *AppointmentRepository) UpdateStatus(ctx context.Context, id, clinicID primitive.ObjectID, status string) error {
+// ListUpcomingInSeries returns scheduled or confirmed occurrences of a series
+// starting at or after from, ordered by start time
+func (r *AppointmentRepository) ListUpcomingInSeries(ctx context.Context, clinicID, seriesID primitive.ObjectID, from time.Time) ([]models.Appointment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":  clinicID,
+		"series_id":  seriesID,
+		"start_time": bson.M{"$gte": from},
+		"status":     bson.M{"$in": []string{models.AppointmentStatusScheduled, models.AppointmentStatusConfirmed}},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var appointments []models.Appointment
+	if err = cursor.All(ctx, &appointments); err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// ListOverdue returns scheduled or confirmed appointments that ended at or
+// before the given time
+func (r *AppointmentRepository) ListOverdue(ctx context.Context, clinicID primitive.ObjectID, endedBefore time.Time) ([]models.Appointment, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
 	filter := bson.M{
-		"_id":       id,
 		"clinic_id": clinicID,
+		"status":    bson.M{"$in": []string{models.AppointmentStatusScheduled, models.AppointmentStatusConfirmed}},
+		"end_time":  bson.M{"$lte": endedBefore},
 	}
 
-	_, err := r.collection.UpdateOne(
-		ctx,
-		filter,
-		bson.M{"$set": bson.M{
-			"status":     status,
-			"updated_at": time.Now().UTC(),
-		}},
-	)
-	return err
+	opts := options.Find().SetSort(bson.D{{Key: "end_time", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var appointments []models.Appointment
+	if err = cursor.All(ctx, &appointments); err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// ListStartingBetween returns scheduled or confirmed appointments starting in [from, to)
+func (r *AppointmentRepository) ListStartingBetween(ctx context.Context, clinicID primitive.ObjectID, from, to time.Time) ([]models.Appointment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":  clinicID,
+		"status":     bson.M{"$in": []string{models.AppointmentStatusScheduled, models.AppointmentStatusConfirmed}},
+		"start_time": bson.M{"$gte": from, "$lt": to},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var appointments []models.Appointment
+	if err = cursor.All(ctx, &appointments); err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// CountNoShowsByPatients returns the number of no-show appointments per patient
+func (r *AppointmentRepository) CountNoShowsByPatients(ctx context.Context, clinicID primitive.ObjectID, patientIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	counts := make(map[primitive.ObjectID]int)
+	if len(patientIDs) == 0 {
+		return counts, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"clinic_id":  clinicID,
+			"patient_id": bson.M{"$in": patientIDs},
+			"status":     models.AppointmentStatusNoShow,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$patient_id",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		PatientID primitive.ObjectID `bson:"_id"`
+		Count     int                `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	for _, res := range results {
+		counts[res.PatientID] = res.Count
+	}
+	return counts, nil
 }
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AppointmentSeriesRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewAppointmentSeriesRepository(db *mongo.Database, timeout time.Duration) *AppointmentSeriesRepository {
+	return &AppointmentSeriesRepository{
+		collection: db.Collection("appointment_series"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts a new series
+func (r *AppointmentSeriesRepository) Create(ctx context.Context, series *models.AppointmentSeries) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	series.CreatedAt = time.Now().UTC()
+
+	result, err := r.collection.InsertOne(ctx, series)
+	if err != nil {
+		return err
+	}
+
+	series.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves a series with clinic isolation
+func (r *AppointmentSeriesRepository) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.AppointmentSeries, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var series models.AppointmentSeries
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}).Decode(&series)
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
@@ -49,6 +49,7 @@ func (r *AuditLogRepository) FindByClinicAndFilters(
 	clinicID primitive.ObjectID,
 	doctorID *primitive.ObjectID,
 	startDate, endDate string,
+	loc *time.Location,
 	limit int64,
 ) ([]models.AuditLog, error) {
 	filter := bson.M{"clinic_id": clinicID}
@@ -57,21 +58,20 @@ func (r *AuditLogRepository) FindByClinicAndFilters(
 		filter["actor_user_id"] = *doctorID
 	}
 
-	// Date range filter
+	// Date range filter (whole days in loc)
 	if startDate != "" || endDate != "" {
 		dateFilter := bson.M{}
 		if startDate != "" {
-			startTime, err := time.Parse("2006-01-02", startDate)
+			startTime, err := time.ParseInLocation("2006-01-02", startDate, loc)
 			if err == nil {
 				dateFilter["$gte"] = startTime
 			}
 		}
 		if endDate != "" {
-			endTime, err := time.Parse("2006-01-02", endDate)
+			endTime, err := time.ParseInLocation("2006-01-02", endDate, loc)
 			if err == nil {
-				// End of day
-				endTime = endTime.Add(24*time.Hour - time.Second)
-				dateFilter["$lte"] = endTime
+				// Start of the next day
+				dateFilter["$lt"] = endTime.AddDate(0, 0, 1)
 			}
 		}
 		if len(dateFilter) > 0 {
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CashShiftRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewCashShiftRepository(db *mongo.Database, timeout time.Duration) *CashShiftRepository {
+	return &CashShiftRepository{
+		collection: db.Collection("cash_shifts"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts an open shift. A unique index allows one open shift per
+// cashier, so a concurrent second open fails with a duplicate key error.
+func (r *CashShiftRepository) Create(ctx context.Context, shift *models.CashShift) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	shift.CreatedAt = time.Now().UTC()
+	shift.UpdatedAt = shift.CreatedAt
+	shift.Status = models.CashShiftStatusOpen
+
+	result, err := r.collection.InsertOne(ctx, shift)
+	if err != nil {
+		return err
+	}
+
+	shift.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves a shift with clinic isolation
+func (r *CashShiftRepository) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.CashShift, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var shift models.CashShift
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}).Decode(&shift)
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// GetOpen returns the cashier's open shift
+func (r *CashShiftRepository) GetOpen(ctx context.Context, clinicID, cashierID primitive.ObjectID) (*models.CashShift, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":  clinicID,
+		"cashier_id": cashierID,
+		"status":     models.CashShiftStatusOpen,
+	}
+	var shift models.CashShift
+	if err := r.collection.FindOne(ctx, filter).Decode(&shift); err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// Close stores the closing figures of an open shift. It returns false if the
+// shift is no longer open.
+func (r *CashShiftRepository) Close(ctx context.Context, shift *models.CashShift) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":       shift.ID,
+		"clinic_id": shift.ClinicID,
+		"status":    models.CashShiftStatusOpen,
+	}
+	update := bson.M{"$set": bson.M{
+		"status":        models.CashShiftStatusClosed,
+		"closed_at":     shift.ClosedAt,
+		"cash_in":       shift.CashIn,
+		"cash_out":      shift.CashOut,
+		"expected_cash": shift.ExpectedCash,
+		"counted_cash":  shift.CountedCash,
+		"discrepancy":   shift.Discrepancy,
+		"closing_notes": shift.ClosingNotes,
+		"updated_at":    time.Now().UTC(),
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// List returns shifts opened in a clinic-local date range (inclusive),
+// optionally of one cashier. Newest first.
+func (r *CashShiftRepository) List(ctx context.Context, clinicID primitive.ObjectID, cashierID *primitive.ObjectID, fromDate, toDate string) ([]models.CashShift, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id": clinicID,
+		"date":      bson.M{"$gte": fromDate, "$lte": toDate},
+	}
+	if cashierID != nil {
+		filter["cashier_id"] = *cashierID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "opened_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var shifts []models.CashShift
+	if err = cursor.All(ctx, &shifts); err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
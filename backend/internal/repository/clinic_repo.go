@@ -65,6 +65,19 @@ func (r *ClinicRepository) GetByName(ctx context.Context, name string) (*models.
 	return &clinic, nil
 }
 
+// GetBySlug retrieves a clinic by its public slug
+func (r *ClinicRepository) GetBySlug(ctx context.Context, slug string) (*models.Clinic, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var clinic models.Clinic
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&clinic)
+	if err != nil {
+		return nil, err
+	}
+	return &clinic, nil
+}
+
 func (r *ClinicRepository) List(ctx context.Context, page, pageSize int) ([]models.Clinic, int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
@@ -95,6 +108,24 @@ func (r *ClinicRepository) List(ctx context.Context, page, pageSize int) ([]mode
 	return clinics, total, nil
 }
 
+// ListActive returns all active clinics; used by background jobs
+func (r *ClinicRepository) ListActive(ctx context.Context) ([]models.Clinic, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"is_active": true})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var clinics []models.Clinic
+	if err = cursor.All(ctx, &clinics); err != nil {
+		return nil, err
+	}
+	return clinics, nil
+}
+
 func (r *ClinicRepository) Update(ctx context.Context, clinic *models.Clinic) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
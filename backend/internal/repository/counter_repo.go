@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CounterRepository hands out per-clinic sequence numbers (invoices, receipts)
+type CounterRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewCounterRepository(db *mongo.Database, timeout time.Duration) *CounterRepository {
+	return &CounterRepository{
+		collection: db.Collection("counters"),
+		timeout:    timeout,
+	}
+}
+
+// Next atomically increments and returns the clinic's counter, starting at 1.
+// A number is never handed out twice; numbers taken by failed operations are skipped.
+func (r *CounterRepository) Next(ctx context.Context, clinicID primitive.ObjectID, name string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID, "name": name}
+	update := bson.M{"$inc": bson.M{"value": int64(1)}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var counter struct {
+		Value int64 `bson:"value"`
+	}
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	if mongo.IsDuplicateKeyError(err) {
+		// Two first-ever calls raced to create the counter; the loser retries
+		// against the document the winner inserted
+		err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return counter.Value, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InsurancePayerRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewInsurancePayerRepository(db *mongo.Database, timeout time.Duration) *InsurancePayerRepository {
+	return &InsurancePayerRepository{
+		collection: db.Collection("insurance_payers"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts a payer
+func (r *InsurancePayerRepository) Create(ctx context.Context, payer *models.InsurancePayer) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	payer.CreatedAt = time.Now().UTC()
+	payer.UpdatedAt = payer.CreatedAt
+	payer.IsActive = true
+
+	result, err := r.collection.InsertOne(ctx, payer)
+	if err != nil {
+		return err
+	}
+
+	payer.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves a payer with clinic isolation
+func (r *InsurancePayerRepository) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.InsurancePayer, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var payer models.InsurancePayer
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}).Decode(&payer)
+	if err != nil {
+		return nil, err
+	}
+	return &payer, nil
+}
+
+// List returns a clinic's payers by name
+func (r *InsurancePayerRepository) List(ctx context.Context, clinicID primitive.ObjectID, activeOnly bool) ([]models.InsurancePayer, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID}
+	if activeOnly {
+		filter["is_active"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var payers []models.InsurancePayer
+	if err = cursor.All(ctx, &payers); err != nil {
+		return nil, err
+	}
+	return payers, nil
+}
+
+// Update saves a payer
+func (r *InsurancePayerRepository) Update(ctx context.Context, payer *models.InsurancePayer) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	payer.UpdatedAt = time.Now().UTC()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": payer.ID, "clinic_id": payer.ClinicID}, bson.M{"$set": payer})
+	return err
+}
+
+type ClaimBatchRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewClaimBatchRepository(db *mongo.Database, timeout time.Duration) *ClaimBatchRepository {
+	return &ClaimBatchRepository{
+		collection: db.Collection("claim_batches"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts a batch. The ID may be set beforehand so visits can be
+// tagged with it first.
+func (r *ClaimBatchRepository) Create(ctx context.Context, batch *models.ClaimBatch) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	batch.CreatedAt = time.Now().UTC()
+	batch.UpdatedAt = batch.CreatedAt
+	if batch.Claims == nil {
+		batch.Claims = []models.ClaimLine{}
+	}
+
+	result, err := r.collection.InsertOne(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	batch.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves a batch with clinic isolation
+func (r *ClaimBatchRepository) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.ClaimBatch, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var batch models.ClaimBatch
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}).Decode(&batch)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// List returns a clinic's batches, newest first, optionally of one payer or status
+func (r *ClaimBatchRepository) List(ctx context.Context, clinicID primitive.ObjectID, payerID *primitive.ObjectID, status string) ([]models.ClaimBatch, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID}
+	if payerID != nil {
+		filter["payer_id"] = *payerID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "number", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var batches []models.ClaimBatch
+	if err = cursor.All(ctx, &batches); err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// UpdateStatus moves a batch from one status to another. Returns false when
+// the batch is no longer in the expected status.
+func (r *ClaimBatchRepository) UpdateStatus(ctx context.Context, batch *models.ClaimBatch, from string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	batch.UpdatedAt = time.Now().UTC()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": batch.ID, "clinic_id": batch.ClinicID, "status": from},
+		bson.M{"$set": bson.M{
+			"status":       batch.Status,
+			"note":         batch.Note,
+			"submitted_at": batch.SubmittedAt,
+			"settled_at":   batch.SettledAt,
+			"updated_at":   batch.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// DeletePending deletes a batch that was not submitted yet
+func (r *ClaimBatchRepository) DeletePending(ctx context.Context, id, clinicID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "clinic_id": clinicID, "status": models.ClaimStatusPending})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InventoryItemRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewInventoryItemRepository(db *mongo.Database, timeout time.Duration) *InventoryItemRepository {
+	return &InventoryItemRepository{
+		collection: db.Collection("inventory_items"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts an item with no stock
+func (r *InventoryItemRepository) Create(ctx context.Context, item *models.InventoryItem) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	item.CreatedAt = time.Now().UTC()
+	item.UpdatedAt = item.CreatedAt
+	item.Quantity = 0
+	item.IsActive = true
+
+	result, err := r.collection.InsertOne(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	item.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves an item with clinic isolation
+func (r *InventoryItemRepository) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.InventoryItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var item models.InventoryItem
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}).Decode(&item)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetMultipleByIDs retrieves several items of a clinic
+func (r *InventoryItemRepository) GetMultipleByIDs(ctx context.Context, ids []primitive.ObjectID, clinicID primitive.ObjectID) ([]models.InventoryItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "clinic_id": clinicID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []models.InventoryItem
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// List returns a clinic's items by name
+func (r *InventoryItemRepository) List(ctx context.Context, clinicID primitive.ObjectID, activeOnly bool) ([]models.InventoryItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID}
+	if activeOnly {
+		filter["is_active"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []models.InventoryItem
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListLowStock returns active items at or below their low-stock threshold
+func (r *InventoryItemRepository) ListLowStock(ctx context.Context, clinicID primitive.ObjectID) ([]models.InventoryItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":           clinicID,
+		"is_active":           true,
+		"low_stock_threshold": bson.M{"$gt": 0},
+		"$expr":               bson.M{"$lte": bson.A{"$quantity", "$low_stock_threshold"}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []models.InventoryItem
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves an item's details. The quantity only changes through
+// movements, so it is not written here.
+func (r *InventoryItemRepository) Update(ctx context.Context, item *models.InventoryItem) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	item.UpdatedAt = time.Now().UTC()
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": item.ID, "clinic_id": item.ClinicID},
+		bson.M{"$set": bson.M{
+			"name":                item.Name,
+			"sku":                 item.SKU,
+			"unit":                item.Unit,
+			"low_stock_threshold": item.LowStockThreshold,
+			"unit_cost":           item.UnitCost,
+			"is_active":           item.IsActive,
+			"updated_at":          item.UpdatedAt,
+		}},
+	)
+	return err
+}
+
+// AddStock changes an item's quantity by delta and returns the item after
+// the change. Unless allowNegative is set, a decrease beyond the stock on
+// hand is refused and mongo.ErrNoDocuments returned. A unitCost above zero
+// becomes the item's unit cost.
+func (r *InventoryItemRepository) AddStock(ctx context.Context, id, clinicID primitive.ObjectID, delta float64, allowNegative bool, unitCost float64) (*models.InventoryItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "clinic_id": clinicID}
+	if delta < 0 && !allowNegative {
+		filter["quantity"] = bson.M{"$gte": -delta}
+	}
+	set := bson.M{"updated_at": time.Now().UTC()}
+	if unitCost > 0 {
+		set["unit_cost"] = unitCost
+	}
+	update := bson.M{"$inc": bson.M{"quantity": delta}, "$set": set}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var item models.InventoryItem
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+type StockMovementRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewStockMovementRepository(db *mongo.Database, timeout time.Duration) *StockMovementRepository {
+	return &StockMovementRepository{
+		collection: db.Collection("stock_movements"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts a movement
+func (r *StockMovementRepository) Create(ctx context.Context, movement *models.StockMovement) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	movement.CreatedAt = time.Now().UTC()
+
+	result, err := r.collection.InsertOne(ctx, movement)
+	if err != nil {
+		return err
+	}
+
+	movement.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// List returns movements, newest first, filtered by item, visit, type and
+// date range
+func (r *StockMovementRepository) List(ctx context.Context, clinicID primitive.ObjectID, itemID, visitID *primitive.ObjectID, movementType, fromDate, toDate string) ([]models.StockMovement, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID}
+	if itemID != nil {
+		filter["item_id"] = *itemID
+	}
+	if visitID != nil {
+		filter["visit_id"] = *visitID
+	}
+	if movementType != "" {
+		filter["type"] = movementType
+	}
+	if fromDate != "" || toDate != "" {
+		dateFilter := bson.M{}
+		if fromDate != "" {
+			dateFilter["$gte"] = fromDate
+		}
+		if toDate != "" {
+			dateFilter["$lte"] = toDate
+		}
+		filter["date"] = dateFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var movements []models.StockMovement
+	if err = cursor.All(ctx, &movements); err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
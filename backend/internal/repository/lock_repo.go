@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LockRepository stores named leases used to serialize check-then-write
+// sequences across server instances (e.g. bookings of one doctor)
+type LockRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewLockRepository(db *mongo.Database, timeout time.Duration) *LockRepository {
+	return &LockRepository{
+		collection: db.Collection("locks"),
+		timeout:    timeout,
+	}
+}
+
+// Acquire takes the named lock for ttl and reports whether it was free. A
+// lock whose holder did not release it in time is taken over. The returned
+// token identifies the holder to Release.
+func (r *LockRepository) Acquire(ctx context.Context, name string, ttl time.Duration) (primitive.ObjectID, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	token := primitive.NewObjectID()
+	now := time.Now().UTC()
+
+	// Only a free or expired lock matches; for a held one the upsert collides
+	// with the existing _id
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": name, "expires_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"token": token, "expires_at": now.Add(ttl)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return primitive.NilObjectID, false, nil
+	}
+	if err != nil {
+		return primitive.NilObjectID, false, err
+	}
+	return token, true, nil
+}
+
+// Release frees the named lock if token still holds it
+func (r *LockRepository) Release(ctx context.Context, name string, token primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": name, "token": token})
+	return err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MedicalHistoryRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewMedicalHistoryRepository(db *mongo.Database, timeout time.Duration) *MedicalHistoryRepository {
+	return &MedicalHistoryRepository{
+		collection: db.Collection("medical_history_versions"),
+		timeout:    timeout,
+	}
+}
+
+// Create stores a version of a patient's medical history
+func (r *MedicalHistoryRepository) Create(ctx context.Context, version *models.MedicalHistoryVersion) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	version.ID = primitive.NewObjectID()
+	version.CreatedAt = time.Now().UTC()
+
+	_, err := r.collection.InsertOne(ctx, version)
+	return err
+}
+
+// ListByPatient returns a patient's medical history versions, newest first
+func (r *MedicalHistoryRepository) ListByPatient(ctx context.Context, clinicID, patientID primitive.ObjectID) ([]models.MedicalHistoryVersion, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "history.version", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"clinic_id": clinicID, "patient_id": patientID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var versions []models.MedicalHistoryVersion
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
@@ -122,7 +122,9 @@ func (r *PatientRepository) List(ctx context.Context, clinicID primitive.ObjectI
 	return patients, total, nil
 }
 
-// Update updates a patient with clinic isolation
+// Update updates a patient's contact and demographic fields with clinic
+// isolation. Insurance policies and medical history have their own setters
+// so a concurrent edit here cannot overwrite them.
 func (r *PatientRepository) Update(ctx context.Context, patient *models.Patient) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
@@ -134,7 +136,18 @@ func (r *PatientRepository) Update(ctx context.Context, patient *models.Patient)
 		"clinic_id": patient.ClinicID,
 	}
 
-	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": patient})
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"first_name":       patient.FirstName,
+		"last_name":        patient.LastName,
+		"phone":            patient.Phone,
+		"email":            patient.Email,
+		"telegram_chat_id": patient.Telegram,
+		"dob":              patient.DOB,
+		"gender":           patient.Gender,
+		"address":          patient.Address,
+		"notes":            patient.Notes,
+		"updated_at":       patient.UpdatedAt,
+	}})
 	return err
 }
 
@@ -159,6 +172,17 @@ func (r *PatientRepository) Delete(ctx context.Context, id, clinicID primitive.O
 	return err
 }
 
+// Purge permanently removes a patient record. Only for records that were
+// just created and are referenced nowhere, e.g. when the booking that
+// registered the patient failed.
+func (r *PatientRepository) Purge(ctx context.Context, id, clinicID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "clinic_id": clinicID})
+	return err
+}
+
 // CountByClinic returns total patients for a clinic
 func (r *PatientRepository) CountByClinic(ctx context.Context, clinicID primitive.ObjectID) (int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
@@ -170,12 +194,12 @@ func (r *PatientRepository) CountByClinic(ctx context.Context, clinicID primitiv
 	})
 }
 
-// CountByClinicAndDate returns patients created on a specific date
-func (r *PatientRepository) CountByClinicAndDate(ctx context.Context, clinicID primitive.ObjectID, date string) (int64, error) {
+// CountByClinicAndDate returns patients created on a specific date in loc
+func (r *PatientRepository) CountByClinicAndDate(ctx context.Context, clinicID primitive.ObjectID, date string, loc *time.Location) (int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	startOfDay, _ := time.Parse("2006-01-02", date)
+	startOfDay, _ := time.ParseInLocation("2006-01-02", date, loc)
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
 	return r.collection.CountDocuments(ctx, bson.M{
@@ -184,3 +208,91 @@ func (r *PatientRepository) CountByClinicAndDate(ctx context.Context, clinicID p
 		"created_at": bson.M{"$gte": startOfDay, "$lt": endOfDay},
 	})
 }
+
+// AddPolicy appends an insurance policy to a patient
+func (r *PatientRepository) AddPolicy(ctx context.Context, id, clinicID primitive.ObjectID, policy *models.InsurancePolicy) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "clinic_id": clinicID},
+		bson.M{
+			"$push": bson.M{"insurance_policies": policy},
+			"$set":  bson.M{"updated_at": time.Now().UTC()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdatePolicy replaces one of a patient's insurance policies in place,
+// leaving the others as they are. Returns mongo.ErrNoDocuments when the
+// patient or policy is missing.
+func (r *PatientRepository) UpdatePolicy(ctx context.Context, id, clinicID primitive.ObjectID, policy *models.InsurancePolicy) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "clinic_id": clinicID, "insurance_policies._id": policy.ID},
+		bson.M{"$set": bson.M{"insurance_policies.$": policy, "updated_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeletePolicy removes one of a patient's insurance policies. Returns
+// mongo.ErrNoDocuments when the patient or policy is missing.
+func (r *PatientRepository) DeletePolicy(ctx context.Context, id, clinicID, policyID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "clinic_id": clinicID, "insurance_policies._id": policyID},
+		bson.M{
+			"$pull": bson.M{"insurance_policies": bson.M{"_id": policyID}},
+			"$set":  bson.M{"updated_at": time.Now().UTC()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetMedicalHistory replaces a patient's medical history if its stored
+// version is still prevVersion (0 = never recorded). Returns
+// mongo.ErrNoDocuments when the patient is missing or the version moved on.
+func (r *PatientRepository) SetMedicalHistory(ctx context.Context, id, clinicID primitive.ObjectID, history *models.MedicalHistory, prevVersion int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "clinic_id": clinicID}
+	if prevVersion == 0 {
+		filter["medical_history"] = bson.M{"$exists": false}
+	} else {
+		filter["medical_history.version"] = prevVersion
+	}
+	result, err := r.collection.UpdateOne(ctx, filter,
+		bson.M{"$set": bson.M{"medical_history": history, "updated_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
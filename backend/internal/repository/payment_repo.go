@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PaymentRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewPaymentRepository(db *mongo.Database, timeout time.Duration) *PaymentRepository {
+	return &PaymentRepository{
+		collection: db.Collection("payments"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts a payment document
+func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	payment.CreatedAt = time.Now().UTC()
+
+	result, err := r.collection.InsertOne(ctx, payment)
+	if err != nil {
+		return err
+	}
+
+	payment.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves a payment with clinic isolation
+func (r *PaymentRepository) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.Payment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var payment models.Payment
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}).Decode(&payment)
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// SetReceiptNumber numbers a payment recorded before receipt numbering. It
+// returns false if the payment already has a number.
+func (r *PaymentRepository) SetReceiptNumber(ctx context.Context, id, clinicID primitive.ObjectID, number int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":            id,
+		"clinic_id":      clinicID,
+		"receipt_number": bson.M{"$exists": false},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"receipt_number": number}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// List returns payments of a clinic filtered by visit, patient and/or
+// clinic-local date range; empty filters are ignored. Newest first.
+func (r *PaymentRepository) List(ctx context.Context, clinicID primitive.ObjectID, visitID, patientID *primitive.ObjectID, fromDate, toDate string) ([]models.Payment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID}
+	if visitID != nil {
+		filter["visit_id"] = *visitID
+	}
+	if patientID != nil {
+		filter["patient_id"] = *patientID
+	}
+	if fromDate != "" || toDate != "" {
+		dateFilter := bson.M{}
+		if fromDate != "" {
+			dateFilter["$gte"] = fromDate
+		}
+		if toDate != "" {
+			dateFilter["$lte"] = toDate
+		}
+		filter["date"] = dateFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "paid_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var payments []models.Payment
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// ListByShift returns the payments linked to a cash shift, oldest first
+func (r *PaymentRepository) ListByShift(ctx context.Context, clinicID, shiftID primitive.ObjectID) ([]models.Payment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "paid_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"clinic_id": clinicID, "shift_id": shiftID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var payments []models.Payment
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// SumByMethod totals payments per method for a clinic-local date range
+// (inclusive). Split payments count towards each of their methods.
+func (r *PaymentRepository) SumByMethod(ctx context.Context, clinicID primitive.ObjectID, fromDate, toDate string) (map[string]float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"clinic_id": clinicID,
+			"date":      bson.M{"$gte": fromDate, "$lte": toDate},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"parts": bson.M{"$ifNull": bson.A{"$splits", bson.A{bson.M{"method": "$method", "amount": "$amount"}}}},
+		}}},
+		{{Key: "$unwind", Value: "$parts"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$parts.method",
+			"total": bson.M{"$sum": "$parts.amount"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	result := make(map[string]float64)
+	for cursor.Next(ctx) {
+		var doc struct {
+			Method string  `bson:"_id"`
+			Total  float64 `bson:"total"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		result[doc.Method] = doc.Total
+	}
+	return result, cursor.Err()
+}
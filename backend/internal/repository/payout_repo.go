@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PayoutStatementRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewPayoutStatementRepository(db *mongo.Database, timeout time.Duration) *PayoutStatementRepository {
+	return &PayoutStatementRepository{
+		collection: db.Collection("payout_statements"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts a statement
+func (r *PayoutStatementRepository) Create(ctx context.Context, statement *models.PayoutStatement) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	statement.CreatedAt = time.Now().UTC()
+	statement.UpdatedAt = statement.CreatedAt
+	if statement.Visits == nil {
+		statement.Visits = []models.PayoutVisit{}
+	}
+	if statement.Reversals == nil {
+		statement.Reversals = []models.PayoutReversal{}
+	}
+	if statement.Payments == nil {
+		statement.Payments = []models.PayoutPayment{}
+	}
+
+	result, err := r.collection.InsertOne(ctx, statement)
+	if err != nil {
+		return err
+	}
+
+	statement.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves a statement with clinic isolation
+func (r *PayoutStatementRepository) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.PayoutStatement, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var statement models.PayoutStatement
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}).Decode(&statement)
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+// List returns a clinic's statements, newest period first, optionally of one doctor
+func (r *PayoutStatementRepository) List(ctx context.Context, clinicID primitive.ObjectID, doctorID *primitive.ObjectID) ([]models.PayoutStatement, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID}
+	if doctorID != nil {
+		filter["doctor_id"] = *doctorID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "period_from", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var statements []models.PayoutStatement
+	if err = cursor.All(ctx, &statements); err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
+
+// Overlaps reports whether a doctor already has a statement covering any day
+// of the date range
+func (r *PayoutStatementRepository) Overlaps(ctx context.Context, clinicID, doctorID primitive.ObjectID, fromDate, toDate string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"clinic_id":   clinicID,
+		"doctor_id":   doctorID,
+		"period_from": bson.M{"$lte": toDate},
+		"period_to":   bson.M{"$gte": fromDate},
+	})
+	return count > 0, err
+}
+
+// AddPayment records a payment unless it would pay more than the statement
+// owes. It returns false if the statement changed or would be overpaid.
+func (r *PayoutStatementRepository) AddPayment(ctx context.Context, id, clinicID primitive.ObjectID, payment models.PayoutPayment) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":       id,
+		"clinic_id": clinicID,
+		"$expr": bson.M{"$lte": bson.A{
+			bson.M{"$add": bson.A{"$paid_amount", payment.Amount}},
+			bson.M{"$add": bson.A{"$earning", models.MoneyEpsilon}},
+		}},
+	}
+	update := bson.M{
+		"$inc":  bson.M{"paid_amount": payment.Amount},
+		"$push": bson.M{"payments": payment},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// DeleteUnpaid removes a statement nothing was paid against, reopening its
+// period. It returns false if the statement has payments.
+func (r *PayoutStatementRepository) DeleteUnpaid(ctx context.Context, id, clinicID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{
+		"_id":       id,
+		"clinic_id": clinicID,
+		"payments":  bson.M{"$size": 0},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PhoneVerificationRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewPhoneVerificationRepository(db *mongo.Database, timeout time.Duration) *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{
+		collection: db.Collection("phone_verifications"),
+		timeout:    timeout,
+	}
+}
+
+// Create stores a new code
+func (r *PhoneVerificationRepository) Create(ctx context.Context, v *models.PhoneVerification) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	v.CreatedAt = time.Now().UTC()
+
+	result, err := r.collection.InsertOne(ctx, v)
+	if err != nil {
+		return err
+	}
+
+	v.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetLatest returns the most recent unexpired, unused code for a phone
+func (r *PhoneVerificationRepository) GetLatest(ctx context.Context, clinicID primitive.ObjectID, phone string) (*models.PhoneVerification, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":  clinicID,
+		"phone":      phone,
+		"expires_at": bson.M{"$gt": time.Now().UTC()},
+		"used":       bson.M{"$ne": true},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var v models.PhoneVerification
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// CountSince returns how many codes were sent to a phone since the given time
+func (r *PhoneVerificationRepository) CountSince(ctx context.Context, clinicID primitive.ObjectID, phone string, since time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.collection.CountDocuments(ctx, bson.M{
+		"clinic_id":  clinicID,
+		"phone":      phone,
+		"created_at": bson.M{"$gte": since},
+	})
+}
+
+// RecordAttempt increments the attempt counter while it is below max and
+// reports whether the attempt may proceed
+func (r *PhoneVerificationRepository) RecordAttempt(ctx context.Context, id primitive.ObjectID, max int) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "attempts": bson.M{"$lt": max}},
+		bson.M{"$inc": bson.M{"attempts": 1}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// Consume marks a code used so it cannot be replayed and reports whether
+// this call was the one that used it
+func (r *PhoneVerificationRepository) Consume(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "used": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"used": true}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// Release makes a consumed code usable again after the booking it was used
+// for failed
+func (r *PhoneVerificationRepository) Release(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"used": false}})
+	return err
+}
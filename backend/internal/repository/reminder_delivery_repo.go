@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReminderDeliveryRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewReminderDeliveryRepository(db *mongo.Database, timeout time.Duration) *ReminderDeliveryRepository {
+	return &ReminderDeliveryRepository{
+		collection: db.Collection("reminder_deliveries"),
+		timeout:    timeout,
+	}
+}
+
+// Claim inserts a pending delivery. It returns false when the reminder was
+// already claimed, which keeps reminders single-send across job runs and
+// backend instances (unique on appointment, start time and offset).
+func (r *ReminderDeliveryRepository) Claim(ctx context.Context, delivery *models.ReminderDelivery) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	delivery.CreatedAt = time.Now().UTC()
+	delivery.UpdatedAt = delivery.CreatedAt
+	delivery.Status = models.ReminderStatusPending
+
+	result, err := r.collection.InsertOne(ctx, delivery)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	delivery.ID = result.InsertedID.(primitive.ObjectID)
+	return true, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt. A nil nextAttempt
+// means the delivery is final.
+func (r *ReminderDeliveryRepository) RecordAttempt(ctx context.Context, delivery *models.ReminderDelivery, status, lastError string, nextAttempt *time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	set := bson.M{
+		"channel":         delivery.Channel,
+		"to":              delivery.To,
+		"body":            delivery.Body,
+		"status":          status,
+		"last_error":      lastError,
+		"last_attempt_at": now,
+		"updated_at":      now,
+	}
+	update := bson.M{"$set": set, "$inc": bson.M{"attempts": 1}}
+	if status == models.ReminderStatusSent {
+		set["sent_at"] = now
+	}
+	if nextAttempt != nil {
+		set["next_attempt_at"] = *nextAttempt
+	} else {
+		update["$unset"] = bson.M{"next_attempt_at": ""}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": delivery.ID}, update)
+	return err
+}
+
+// ClaimRetry moves a failed delivery due for retry back to pending, so only
+// one job run retries it
+func (r *ReminderDeliveryRepository) ClaimRetry(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.ReminderStatusFailed},
+		bson.M{"$set": bson.M{"status": models.ReminderStatusPending, "updated_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ListDueRetries returns failed deliveries whose next attempt is due
+func (r *ReminderDeliveryRepository) ListDueRetries(ctx context.Context, clinicID primitive.ObjectID, now time.Time) ([]models.ReminderDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":       clinicID,
+		"status":          models.ReminderStatusFailed,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []models.ReminderDelivery
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// List returns deliveries for appointments on a clinic-local date, optionally filtered by status
+func (r *ReminderDeliveryRepository) List(ctx context.Context, clinicID primitive.ObjectID, date, status string) ([]models.ReminderDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID, "appointment_date": date}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "appointment_start", Value: 1}, {Key: "hours_before", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []models.ReminderDelivery
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Close marks a delivery as finally failed without counting an attempt
+func (r *ReminderDeliveryRepository) Close(ctx context.Context, id primitive.ObjectID, reason string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": models.ReminderStatusFailed, "last_error": reason, "updated_at": time.Now().UTC()},
+		"$unset": bson.M{"next_attempt_at": ""},
+	})
+	return err
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ServicePriceRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewServicePriceRepository(db *mongo.Database, timeout time.Duration) *ServicePriceRepository {
+	return &ServicePriceRepository{
+		collection: db.Collection("service_prices"),
+		timeout:    timeout,
+	}
+}
+
+// Set stores the price of a service from a date, replacing a change already
+// scheduled for the same date
+func (r *ServicePriceRepository) Set(ctx context.Context, price *models.ServicePrice) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	price.CreatedAt = time.Now().UTC()
+
+	filter := bson.M{
+		"clinic_id":      price.ClinicID,
+		"service_id":     price.ServiceID,
+		"effective_from": price.EffectiveFrom,
+	}
+	update := bson.M{"$set": bson.M{
+		"price":      price.Price,
+		"reason":     price.Reason,
+		"created_by": price.CreatedBy,
+		"created_at": price.CreatedAt,
+	}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(price)
+}
+
+// GetByID retrieves a price entry of a service with clinic isolation
+func (r *ServicePriceRepository) GetByID(ctx context.Context, id, serviceID, clinicID primitive.ObjectID) (*models.ServicePrice, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var price models.ServicePrice
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "service_id": serviceID, "clinic_id": clinicID}).Decode(&price)
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// Delete removes a price entry
+func (r *ServicePriceRepository) Delete(ctx context.Context, id, clinicID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "clinic_id": clinicID})
+	return err
+}
+
+// ListByService returns a service's price history, newest first
+func (r *ServicePriceRepository) ListByService(ctx context.Context, serviceID, clinicID primitive.ObjectID) ([]models.ServicePrice, error) {
+	return r.find(ctx, bson.M{"clinic_id": clinicID, "service_id": serviceID}, -1)
+}
+
+// ListByClinic returns the price history of all of a clinic's services,
+// oldest first, optionally limited to entries effective up to a date
+func (r *ServicePriceRepository) ListByClinic(ctx context.Context, clinicID primitive.ObjectID, toDate string) ([]models.ServicePrice, error) {
+	filter := bson.M{"clinic_id": clinicID}
+	if toDate != "" {
+		filter["effective_from"] = bson.M{"$lte": toDate}
+	}
+	return r.find(ctx, filter, 1)
+}
+
+// PricesAt returns the price in effect on a date for each of the services
+// that have a price history
+func (r *ServicePriceRepository) PricesAt(ctx context.Context, clinicID primitive.ObjectID, serviceIDs []primitive.ObjectID, date string) (map[primitive.ObjectID]float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"clinic_id":      clinicID,
+			"service_id":     bson.M{"$in": serviceIDs},
+			"effective_from": bson.M{"$lte": date},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "effective_from", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$service_id",
+			"price": bson.M{"$first": "$price"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	prices := make(map[primitive.ObjectID]float64)
+	for cursor.Next(ctx) {
+		var doc struct {
+			ServiceID primitive.ObjectID `bson:"_id"`
+			Price     float64            `bson:"price"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		prices[doc.ServiceID] = doc.Price
+	}
+	return prices, cursor.Err()
+}
+
+func (r *ServicePriceRepository) find(ctx context.Context, filter bson.M, order int) ([]models.ServicePrice, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "effective_from", Value: order}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var prices []models.ServicePrice
+	if err = cursor.All(ctx, &prices); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
@@ -101,6 +101,21 @@ func (r *ServiceRepository) Update(ctx context.Context, service *models.Service)
 	return err
 }
 
+// SetMaterials replaces a service's bill of materials
+func (r *ServiceRepository) SetMaterials(ctx context.Context, id, clinicID primitive.ObjectID, materials []models.ServiceMaterial) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "clinic_id": clinicID},
+		bson.M{"$set": bson.M{
+			"materials":  materials,
+			"updated_at": time.Now().UTC(),
+		}},
+	)
+	return err
+}
+
 func (r *ServiceRepository) Delete(ctx context.Context, id, clinicID primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TimeOffRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewTimeOffRepository(db *mongo.Database, timeout time.Duration) *TimeOffRepository {
+	return &TimeOffRepository{
+		collection: db.Collection("time_off"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts a new closure or leave entry
+func (r *TimeOffRepository) Create(ctx context.Context, entry *models.TimeOff) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	entry.CreatedAt = time.Now().UTC()
+
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID finds an entry by ID within a clinic
+func (r *TimeOffRepository) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.TimeOff, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var entry models.TimeOff
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}).Decode(&entry)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListByClinic returns entries of the given type (all types if empty) ending after from
+func (r *TimeOffRepository) ListByClinic(ctx context.Context, clinicID primitive.ObjectID, entryType string, from time.Time) ([]models.TimeOff, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id": clinicID,
+		"end_time":  bson.M{"$gt": from},
+	}
+	if entryType != "" {
+		filter["type"] = entryType
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.TimeOff
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListBlocking returns clinic closures and the doctor's leaves intersecting [from, to)
+func (r *TimeOffRepository) ListBlocking(ctx context.Context, clinicID, doctorID primitive.ObjectID, from, to time.Time) ([]models.TimeOff, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":  clinicID,
+		"start_time": bson.M{"$lt": to},
+		"end_time":   bson.M{"$gt": from},
+		"$or": []bson.M{
+			{"type": models.TimeOffTypeClosure},
+			{"type": models.TimeOffTypeLeave, "doctor_id": doctorID},
+		},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.TimeOff
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Delete removes an entry
+func (r *TimeOffRepository) Delete(ctx context.Context, id, clinicID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "clinic_id": clinicID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
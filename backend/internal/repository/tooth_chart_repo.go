@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ToothChartRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewToothChartRepository(db *mongo.Database, timeout time.Duration) *ToothChartRepository {
+	return &ToothChartRepository{
+		collection: db.Collection("tooth_chart_entries"),
+		timeout:    timeout,
+	}
+}
+
+// ReplaceForVisit replaces the chart entries a visit made. The entries keep
+// the creation time of the visit's first save, so re-saving a draft does not
+// move them after other entries of the same date.
+func (r *ToothChartRepository) ReplaceForVisit(ctx context.Context, clinicID, visitID primitive.ObjectID, entries []models.ToothChartEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID, "visit_id": visitID}
+	createdAt := time.Now().UTC()
+	var first models.ToothChartEntry
+	err := r.collection.FindOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "created_at", Value: 1}})).Decode(&first)
+	switch {
+	case err == nil:
+		createdAt = first.CreatedAt
+	case err != mongo.ErrNoDocuments:
+		return err
+	}
+
+	if _, err := r.collection.DeleteMany(ctx, filter); err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(entries))
+	for i := range entries {
+		entries[i].ID = primitive.NewObjectID()
+		entries[i].CreatedAt = createdAt
+		docs = append(docs, entries[i])
+	}
+	_, err = r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+// ListByPatient returns a patient's chart entries up to a date (inclusive),
+// oldest first, optionally of one tooth
+func (r *ToothChartRepository) ListByPatient(ctx context.Context, clinicID, patientID primitive.ObjectID, toDate, tooth string) ([]models.ToothChartEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID, "patient_id": patientID}
+	if toDate != "" {
+		filter["date"] = bson.M{"$lte": toDate}
+	}
+	if tooth != "" {
+		filter["tooth"] = tooth
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "created_at", Value: 1}, {Key: "seq", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.ToothChartEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -181,3 +181,19 @@ func (r *UserRepository) GetByIDWithClinicCheck(ctx context.Context, id, clinicI
 	}
 	return &user, nil
 }
+
+// UpdateWorkSchedule replaces a doctor's weekly schedule with clinic isolation
+func (r *UserRepository) UpdateWorkSchedule(ctx context.Context, id, clinicID primitive.ObjectID, schedule *models.WorkSchedule) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "clinic_id": clinicID},
+		bson.M{"$set": bson.M{
+			"work_schedule": schedule,
+			"updated_at":    time.Now().UTC(),
+		}},
+	)
+	return err
+}
@@ -31,7 +31,9 @@ func (r *VisitRepository) Create(ctx context.Context, visit *models.Visit) error
 	visit.CreatedAt = time.Now().UTC()
 	visit.UpdatedAt = visit.CreatedAt
 	visit.Status = models.VisitStatusStarted
-	visit.Date = visit.CreatedAt.Format("2006-01-02")
+	if visit.Date == "" {
+		visit.Date = visit.CreatedAt.Format("2006-01-02")
+	}
 
 	if visit.Services == nil {
 		visit.Services = []models.VisitService{}
@@ -95,6 +97,212 @@ func (r *VisitRepository) Update(ctx context.Context, visit *models.Visit) error
 	return err
 }
 
+// UpdateIfStatus saves a visit only if it is still in the given status. It
+// returns false if the status changed concurrently. A cleared approval is
+// removed from the document.
+func (r *VisitRepository) UpdateIfStatus(ctx context.Context, visit *models.Visit, status string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	visit.UpdatedAt = time.Now().UTC()
+
+	filter := bson.M{
+		"_id":       visit.ID,
+		"clinic_id": visit.ClinicID,
+		"status":    status,
+	}
+	update := bson.M{"$set": visit}
+	if visit.Approval == nil {
+		update["$unset"] = bson.M{"approval": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ListByStatus returns a clinic's visits in a status, oldest first
+func (r *VisitRepository) ListByStatus(ctx context.Context, clinicID primitive.ObjectID, status string) ([]models.Visit, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"clinic_id": clinicID, "status": status}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var visits []models.Visit
+	if err = cursor.All(ctx, &visits); err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+// AddPayment adds amount to a completed visit's paid total. The write is
+// conditional so concurrent payments can never exceed the patient's share of
+// the net total; it returns false when the visit is missing, not completed or
+// would be overpaid.
+func (r *VisitRepository) AddPayment(ctx context.Context, id, clinicID primitive.ObjectID, amount float64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":       id,
+		"clinic_id": clinicID,
+		"status":    models.VisitStatusCompleted,
+		"$expr": bson.M{"$lte": bson.A{
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$paid_amount", 0}}, amount}},
+			bson.M{"$add": bson.A{patientShareExpr(), models.MoneyEpsilon}},
+		}},
+	}
+	update := bson.M{
+		"$inc": bson.M{"paid_amount": amount},
+		"$set": bson.M{"updated_at": time.Now().UTC()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// patientShareExpr computes Visit.PatientShare in an aggregation expression
+func patientShareExpr() bson.M {
+	net := bson.M{"$subtract": bson.A{"$total", bson.M{"$ifNull": bson.A{"$reversed_amount", 0}}}}
+	payer := bson.M{"$cond": bson.A{
+		bson.M{"$and": bson.A{
+			bson.M{"$gt": bson.A{"$total", 0}},
+			bson.M{"$ne": bson.A{bson.M{"$ifNull": bson.A{"$claim.status", models.ClaimStatusRejected}}, models.ClaimStatusRejected}},
+		}},
+		bson.M{"$divide": bson.A{bson.M{"$multiply": bson.A{"$claim.amount", net}}, "$total"}},
+		0,
+	}}
+	return bson.M{"$subtract": bson.A{net, payer}}
+}
+
+// AddAdjustment applies a reversing entry to a completed visit: it adds to the
+// reversed amount and earning and takes the refunded money off the paid total.
+// The write only succeeds if the reversed and paid totals are still the ones
+// the adjustment was computed from, so concurrent adjustments and payments
+// cannot over-reverse; it returns false otherwise.
+func (r *VisitRepository) AddAdjustment(ctx context.Context, visit *models.Visit, amount, earning, refund float64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":       visit.ID,
+		"clinic_id": visit.ClinicID,
+		"status":    models.VisitStatusCompleted,
+		"$expr": bson.M{"$and": bson.A{
+			bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$reversed_amount", 0}}, visit.ReversedAmount}},
+			bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$paid_amount", 0}}, visit.PaidAmount}},
+		}},
+	}
+	update := bson.M{
+		"$inc": bson.M{
+			"reversed_amount":  amount,
+			"reversed_earning": earning,
+			"paid_amount":      -refund,
+		},
+		"$set": bson.M{"updated_at": time.Now().UTC()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// SetInvoiceNumber numbers a visit completed before invoice numbering. It
+// returns false if the visit already has a number.
+func (r *VisitRepository) SetInvoiceNumber(ctx context.Context, id, clinicID primitive.ObjectID, number int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":            id,
+		"clinic_id":      clinicID,
+		"invoice_number": bson.M{"$exists": false},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"invoice_number": number}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ListCompletedBetween returns completed visits dated in a clinic-local date range (inclusive)
+func (r *VisitRepository) ListCompletedBetween(ctx context.Context, clinicID primitive.ObjectID, fromDate, toDate string) ([]models.Visit, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id": clinicID,
+		"date":      bson.M{"$gte": fromDate, "$lte": toDate},
+		"status":    models.VisitStatusCompleted,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var visits []models.Visit
+	if err = cursor.All(ctx, &visits); err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+// ListCompletedByDoctorBetween returns a doctor's completed visits dated in a
+// clinic-local date range (inclusive), oldest first
+func (r *VisitRepository) ListCompletedByDoctorBetween(ctx context.Context, clinicID, doctorID primitive.ObjectID, fromDate, toDate string) ([]models.Visit, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id": clinicID,
+		"doctor_id": doctorID,
+		"date":      bson.M{"$gte": fromDate, "$lte": toDate},
+		"status":    models.VisitStatusCompleted,
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var visits []models.Visit
+	if err = cursor.All(ctx, &visits); err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+// CountUnfinishedByDoctorBetween counts a doctor's visits dated in a date
+// range that are still in progress or waiting for approval
+func (r *VisitRepository) CountUnfinishedByDoctorBetween(ctx context.Context, clinicID, doctorID primitive.ObjectID, fromDate, toDate string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.collection.CountDocuments(ctx, bson.M{
+		"clinic_id": clinicID,
+		"doctor_id": doctorID,
+		"date":      bson.M{"$gte": fromDate, "$lte": toDate},
+		"status":    bson.M{"$in": bson.A{models.VisitStatusStarted, models.VisitStatusPendingApproval}},
+	})
+}
+
 // ListByDoctor returns visits for a doctor on a specific date
 func (r *VisitRepository) ListByDoctor(ctx context.Context, clinicID, doctorID primitive.ObjectID, date string) ([]models.Visit, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
@@ -147,19 +355,19 @@ func (r *VisitRepository) ListByClinicAndDate(ctx context.Context, clinicID prim
 	return visits, nil
 }
 
-// ListByClinicAndMonth returns visits for a specific month
+// ListByClinicAndMonth returns completed visits whose clinic-local date falls in a month
 func (r *VisitRepository) ListByClinicAndMonth(ctx context.Context, clinicID primitive.ObjectID, year int, month int) ([]models.Visit, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	// Create date range for the month
+	// Date range for the month as YYYY-MM-DD strings
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0)
 
 	filter := bson.M{
-		"clinic_id":  clinicID,
-		"status":     models.VisitStatusCompleted,
-		"created_at": bson.M{"$gte": startDate, "$lt": endDate},
+		"clinic_id": clinicID,
+		"status":    models.VisitStatusCompleted,
+		"date":      bson.M{"$gte": startDate.Format("2006-01-02"), "$lt": endDate.Format("2006-01-02")},
 	}
 
 	cursor, err := r.collection.Find(ctx, filter)
@@ -215,6 +423,44 @@ func (r *VisitRepository) AggregateRevenueByDoctor(ctx context.Context, clinicID
 	return result, nil
 }
 
+// SumEarningsByDoctor sums doctors' earnings on completed visits dated in a
+// date range (inclusive)
+func (r *VisitRepository) SumEarningsByDoctor(ctx context.Context, clinicID primitive.ObjectID, fromDate, toDate string) (map[primitive.ObjectID]float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"clinic_id": clinicID,
+			"status":    models.VisitStatusCompleted,
+			"date":      bson.M{"$gte": fromDate, "$lte": toDate},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$doctor_id",
+			"earning": bson.M{"$sum": "$doctor_earning"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	result := make(map[primitive.ObjectID]float64)
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID      primitive.ObjectID `bson:"_id"`
+			Earning float64            `bson:"earning"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		result[doc.ID] = doc.Earning
+	}
+	return result, cursor.Err()
+}
+
 // CountByDoctorAndDate counts visits for a doctor on a date
 func (r *VisitRepository) CountByDoctorAndDate(ctx context.Context, clinicID, doctorID primitive.ObjectID, date string) (int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
@@ -276,6 +522,32 @@ func (r *VisitRepository) GetOlderIncompleteVisit(ctx context.Context, clinicID,
 	return nil, mongo.ErrNoDocuments
 }
 
+// ListByPatientTooth returns a patient's visits with a service line on the
+// tooth, newest first
+func (r *VisitRepository) ListByPatientTooth(ctx context.Context, clinicID, patientID primitive.ObjectID, tooth string) ([]models.Visit, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":      clinicID,
+		"patient_id":     patientID,
+		"services.teeth": tooth,
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}, {Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var visits []models.Visit
+	if err := cursor.All(ctx, &visits); err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
 // ListByPatient returns all visits for a specific patient
 func (r *VisitRepository) ListByPatient(ctx context.Context, clinicID, patientID primitive.ObjectID) ([]models.Visit, error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
@@ -302,3 +574,144 @@ func (r *VisitRepository) ListByPatient(ctx context.Context, clinicID, patientID
 
 	return visits, nil
 }
+
+// ListClaims returns completed visits with an insurance claim, newest first,
+// optionally of one payer or in the given claim statuses
+func (r *VisitRepository) ListClaims(ctx context.Context, clinicID primitive.ObjectID, payerID *primitive.ObjectID, statuses ...string) ([]models.Visit, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id": clinicID,
+		"status":    models.VisitStatusCompleted,
+		"claim":     bson.M{"$exists": true},
+	}
+	if payerID != nil {
+		filter["claim.payer_id"] = *payerID
+	}
+	if len(statuses) > 0 {
+		filter["claim.status"] = bson.M{"$in": statuses}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}, {Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var visits []models.Visit
+	if err = cursor.All(ctx, &visits); err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+// SumClaimsByPolicy sums the claimed amounts of a policy on visits dated in a
+// date range, leaving out rejected claims
+func (r *VisitRepository) SumClaimsByPolicy(ctx context.Context, clinicID, policyID primitive.ObjectID, fromDate, toDate string) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"clinic_id":       clinicID,
+			"status":          models.VisitStatusCompleted,
+			"date":            bson.M{"$gte": fromDate, "$lte": toDate},
+			"claim.policy_id": policyID,
+			"claim.status":    bson.M{"$ne": models.ClaimStatusRejected},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": "$claim.amount"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var doc struct {
+		Total float64 `bson:"total"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&doc); err != nil {
+			return 0, err
+		}
+	}
+	return doc.Total, cursor.Err()
+}
+
+// AssignClaimBatch tags a payer's pending, unbatched claims on visits up to a
+// date with a batch. Fully reversed visits are left out.
+func (r *VisitRepository) AssignClaimBatch(ctx context.Context, clinicID, payerID, batchID primitive.ObjectID, toDate string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id":      clinicID,
+		"status":         models.VisitStatusCompleted,
+		"date":           bson.M{"$lte": toDate},
+		"claim.payer_id": payerID,
+		"claim.status":   models.ClaimStatusPending,
+		"claim.batch_id": bson.M{"$exists": false},
+		"$expr": bson.M{"$gt": bson.A{
+			bson.M{"$subtract": bson.A{"$total", bson.M{"$ifNull": bson.A{"$reversed_amount", 0}}}},
+			models.MoneyEpsilon,
+		}},
+	}
+	result, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"claim.batch_id": batchID}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ListByClaimBatch returns the visits whose claims are in a batch, oldest first
+func (r *VisitRepository) ListByClaimBatch(ctx context.Context, clinicID, batchID primitive.ObjectID) ([]models.Visit, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"clinic_id": clinicID, "claim.batch_id": batchID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var visits []models.Visit
+	if err = cursor.All(ctx, &visits); err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+// SetClaimStatusByBatch sets the claim status of every visit in a batch
+func (r *VisitRepository) SetClaimStatusByBatch(ctx context.Context, clinicID, batchID primitive.ObjectID, status string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"clinic_id": clinicID, "claim.batch_id": batchID},
+		bson.M{"$set": bson.M{"claim.status": status, "updated_at": time.Now().UTC()}},
+	)
+	return err
+}
+
+// ReleaseClaimBatch returns the claims of a batch to the pending pool
+func (r *VisitRepository) ReleaseClaimBatch(ctx context.Context, clinicID, batchID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"clinic_id": clinicID, "claim.batch_id": batchID},
+		bson.M{
+			"$set":   bson.M{"claim.status": models.ClaimStatusPending},
+			"$unset": bson.M{"claim.batch_id": ""},
+		},
+	)
+	return err
+}
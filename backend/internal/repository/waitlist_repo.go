@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WaitlistRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewWaitlistRepository(db *mongo.Database, timeout time.Duration) *WaitlistRepository {
+	return &WaitlistRepository{
+		collection: db.Collection("waitlist"),
+		timeout:    timeout,
+	}
+}
+
+// Create inserts a new waitlist entry
+func (r *WaitlistRepository) Create(ctx context.Context, entry *models.WaitlistEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	entry.CreatedAt = time.Now().UTC()
+	entry.UpdatedAt = entry.CreatedAt
+	entry.Status = models.WaitlistStatusWaiting
+
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves an entry with clinic isolation
+func (r *WaitlistRepository) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var entry models.WaitlistEntry
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}).Decode(&entry)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List returns entries in queue order, optionally filtered by status
+func (r *WaitlistRepository) List(ctx context.Context, clinicID primitive.ObjectID, status string) ([]models.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"clinic_id": clinicID}
+	if status != "" {
+		filter["status"] = status
+	} else {
+		filter["status"] = bson.M{"$in": []string{models.WaitlistStatusWaiting, models.WaitlistStatusOffered}}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.WaitlistEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListWaitingForDate returns waiting entries whose date range covers date, oldest first
+func (r *WaitlistRepository) ListWaitingForDate(ctx context.Context, clinicID primitive.ObjectID, date string) ([]models.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"clinic_id": clinicID,
+		"status":    models.WaitlistStatusWaiting,
+		"from_date": bson.M{"$lte": date},
+		"to_date":   bson.M{"$gte": date},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.WaitlistEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// MarkOffered attaches an offer to a waiting entry. It returns false when the
+// entry is no longer waiting (e.g. offered a different slot concurrently).
+func (r *WaitlistRepository) MarkOffered(ctx context.Context, id, clinicID primitive.ObjectID, offer models.WaitlistOffer) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "clinic_id": clinicID, "status": models.WaitlistStatusWaiting},
+		bson.M{"$set": bson.M{
+			"status":     models.WaitlistStatusOffered,
+			"offer":      offer,
+			"updated_at": time.Now().UTC(),
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// UpdateStatus sets the status of an entry, clearing the offer unless booked
+func (r *WaitlistRepository) UpdateStatus(ctx context.Context, id, clinicID primitive.ObjectID, status string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"status": status, "updated_at": time.Now().UTC()}}
+	if status != models.WaitlistStatusBooked {
+		update["$unset"] = bson.M{"offer": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "clinic_id": clinicID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ExpireOffers returns entries with lapsed offers to the waiting queue
+func (r *WaitlistRepository) ExpireOffers(ctx context.Context, clinicID primitive.ObjectID, now time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{
+			"clinic_id":        clinicID,
+			"status":           models.WaitlistStatusOffered,
+			"offer.expires_at": bson.M{"$lte": now},
+		},
+		bson.M{
+			"$set":   bson.M{"status": models.WaitlistStatusWaiting, "updated_at": now},
+			"$unset": bson.M{"offer": ""},
+		},
+	)
+	return err
+}
+
+// ReleaseOffers returns the other entries offered the same freed slot to the
+// waiting queue once the slot has been booked
+func (r *WaitlistRepository) ReleaseOffers(ctx context.Context, clinicID, appointmentID, exceptID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{
+			"clinic_id":            clinicID,
+			"_id":                  bson.M{"$ne": exceptID},
+			"status":               models.WaitlistStatusOffered,
+			"offer.appointment_id": appointmentID,
+		},
+		bson.M{
+			"$set":   bson.M{"status": models.WaitlistStatusWaiting, "updated_at": time.Now().UTC()},
+			"$unset": bson.M{"offer": ""},
+		},
+	)
+	return err
+}
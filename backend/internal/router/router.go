@@ -4,18 +4,24 @@ import (
 	"medical-crm/config"
 	"medical-crm/internal/database"
 	"medical-crm/internal/handler"
+	"medical-crm/internal/jobs"
 	"medical-crm/internal/middleware"
+	"medical-crm/internal/models"
 	"medical-crm/internal/repository"
 	"medical-crm/internal/service"
+	"medical-crm/pkg/email"
 	"medical-crm/pkg/logger"
+	"medical-crm/pkg/notify"
 	"path/filepath"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// Setup creates and configures the Gin router
-func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, log *logger.Logger) *gin.Engine {
+// Setup builds the HTTP router and the background job scheduler; the caller
+// starts and stops the scheduler alongside the server
+func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, log *logger.Logger) (*gin.Engine, *jobs.Scheduler) {
 	// Set Gin mode
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -71,14 +77,41 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 	patientRepo := repository.NewPatientRepository(db, cfg.MongoTimeout)
 	appointmentRepo := repository.NewAppointmentRepository(db, cfg.MongoTimeout)
 	serviceRepo := repository.NewServiceRepository(db, cfg.MongoTimeout)
+	servicePriceRepo := repository.NewServicePriceRepository(db, cfg.MongoTimeout)
 	visitRepo := repository.NewVisitRepository(db, cfg.MongoTimeout)
 	contractRepo := repository.NewDoctorContractRepository(db)
 	expenseRepo := repository.NewExpenseRepository(db)
 	salaryRepo := repository.NewStaffSalaryRepository(db)
 	auditRepo := repository.NewAuditLogRepository(db)
 	treatmentPlanRepo := repository.NewTreatmentPlanRepository(db)
+	timeOffRepo := repository.NewTimeOffRepository(db, cfg.MongoTimeout)
+	seriesRepo := repository.NewAppointmentSeriesRepository(db, cfg.MongoTimeout)
+	waitlistRepo := repository.NewWaitlistRepository(db, cfg.MongoTimeout)
+	verificationRepo := repository.NewPhoneVerificationRepository(db, cfg.MongoTimeout)
+	reminderDeliveryRepo := repository.NewReminderDeliveryRepository(db, cfg.MongoTimeout)
+	paymentRepo := repository.NewPaymentRepository(db, cfg.MongoTimeout)
+	counterRepo := repository.NewCounterRepository(db, cfg.MongoTimeout)
+	adjustmentRepo := repository.NewVisitAdjustmentRepository(db, cfg.MongoTimeout)
+	shiftRepo := repository.NewCashShiftRepository(db, cfg.MongoTimeout)
+	payoutRepo := repository.NewPayoutStatementRepository(db, cfg.MongoTimeout)
+	insurancePayerRepo := repository.NewInsurancePayerRepository(db, cfg.MongoTimeout)
+	claimBatchRepo := repository.NewClaimBatchRepository(db, cfg.MongoTimeout)
+	inventoryItemRepo := repository.NewInventoryItemRepository(db, cfg.MongoTimeout)
+	stockMovementRepo := repository.NewStockMovementRepository(db, cfg.MongoTimeout)
+	toothChartRepo := repository.NewToothChartRepository(db, cfg.MongoTimeout)
+	medicalHistoryRepo := repository.NewMedicalHistoryRepository(db, cfg.MongoTimeout)
+	lockRepo := repository.NewLockRepository(db, cfg.MongoTimeout)
+
+	// Outbound notifications
+	notifiers := buildNotifiers(cfg, log)
+	notifier := notifiers[models.ReminderChannelSMS]
+	if notifier == nil {
+		notifier = notify.NewLogNotifier(log)
+	}
 
 	// Initialize services
+	clinicClock := service.NewClinicClock(clinicRepo)
+	locker := service.NewLocker(lockRepo)
 	authService := service.NewAuthService(
 		userRepo,
 		cfg.JWTAccessSecret,
@@ -88,23 +121,53 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 	)
 	clinicService := service.NewClinicService(clinicRepo, invitationRepo)
 	userService := service.NewUserService(userRepo, invitationRepo, authService)
-	patientService := service.NewPatientService(patientRepo)
-	appointmentService := service.NewAppointmentService(appointmentRepo, patientRepo, userRepo)
-	serviceService := service.NewServiceService(serviceRepo)
-	visitService := service.NewVisitService(visitRepo, appointmentRepo, patientRepo, serviceRepo, userRepo, contractRepo)
-	reportService := service.NewReportService(visitRepo, patientRepo, userRepo, expenseRepo, salaryRepo)
-	contractService := service.NewDoctorContractService(contractRepo, userRepo, log)
+	patientService := service.NewPatientService(patientRepo, appointmentRepo)
+	waitlistService := service.NewWaitlistService(waitlistRepo, patientRepo, userRepo, clinicClock, notifier, cfg.WaitlistOfferTTL, log)
+	appointmentService := service.NewAppointmentService(appointmentRepo, patientRepo, userRepo, serviceRepo, clinicClock, timeOffRepo, seriesRepo, waitlistService, locker)
+	serviceService := service.NewServiceService(serviceRepo, servicePriceRepo, clinicClock)
+	inventoryService := service.NewInventoryService(inventoryItemRepo, stockMovementRepo, serviceRepo, expenseRepo, clinicClock, log)
+	visitService := service.NewVisitService(visitRepo, appointmentRepo, patientRepo, serviceRepo, servicePriceRepo, userRepo, clinicRepo, contractRepo, counterRepo, payoutRepo, insurancePayerRepo, inventoryService, toothChartRepo, clinicClock)
+	cashShiftService := service.NewCashShiftService(shiftRepo, paymentRepo, patientRepo, userRepo, clinicClock, locker)
+	reportService := service.NewReportService(visitRepo, patientRepo, userRepo, clinicRepo, expenseRepo, salaryRepo, paymentRepo, adjustmentRepo, serviceRepo, servicePriceRepo, payoutRepo, insurancePayerRepo, cashShiftService, clinicClock)
+	contractService := service.NewDoctorContractService(contractRepo, userRepo, serviceRepo, log)
 	expenseService := service.NewExpenseService(expenseRepo)
 	salaryService := service.NewStaffSalaryService(salaryRepo, userRepo)
-	auditService := service.NewAuditService(auditRepo)
+	auditService := service.NewAuditService(auditRepo, clinicClock)
 	treatmentPlanService := service.NewTreatmentPlanService(treatmentPlanRepo, patientRepo)
+	timeOffService := service.NewTimeOffService(timeOffRepo, appointmentRepo, userRepo, clinicClock)
+	publicBookingService := service.NewPublicBookingService(clinicRepo, serviceRepo, servicePriceRepo, userRepo, patientRepo, verificationRepo, appointmentService, notifier, log)
+	paymentService := service.NewPaymentService(paymentRepo, visitRepo, patientRepo, userRepo, clinicRepo, counterRepo, shiftRepo, clinicClock, locker)
+	adjustmentService := service.NewAdjustmentService(adjustmentRepo, visitRepo, paymentRepo, patientRepo, userRepo, clinicRepo, counterRepo, shiftRepo, payoutRepo, clinicClock, locker)
+	payoutService := service.NewPayoutService(payoutRepo, visitRepo, adjustmentRepo, userRepo, clinicRepo, clinicClock, locker)
+	toothChartService := service.NewToothChartService(toothChartRepo, patientRepo, visitRepo, userRepo, clinicClock)
+	medicalHistoryService := service.NewMedicalHistoryService(patientRepo, medicalHistoryRepo, clinicClock)
+	insuranceService := service.NewInsuranceService(insurancePayerRepo, claimBatchRepo, patientRepo, visitRepo, counterRepo, clinicClock)
+	documentService := service.NewDocumentService(visitRepo, paymentRepo, clinicRepo, patientRepo, userRepo, counterRepo)
+	reminderService := service.NewReminderService(clinicRepo, appointmentRepo, patientRepo, userRepo, reminderDeliveryRepo, clinicClock, notifiers, cfg.ReminderMaxAttempts, log)
+	noShowService := service.NewNoShowService(clinicRepo, appointmentRepo, visitRepo, appointmentService, auditService, cfg.NoShowGracePeriod, log)
+
+	// Background jobs
+	scheduler := jobs.NewScheduler(log)
+	scheduler.Add(jobs.Job{Name: "no-show", Interval: cfg.NoShowCheckInterval, Run: noShowService.Run})
+	scheduler.Add(jobs.Job{Name: "reminders", Interval: cfg.ReminderCheckInterval, Run: reminderService.Run})
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService, userService)
 	superadminHandler := handler.NewSuperadminHandler(clinicService)
-	bossHandler := handler.NewBossHandler(userService, serviceService, reportService, contractService, expenseService, salaryService, auditService, userRepo)
-	receptionistHandler := handler.NewReceptionistHandler(patientService, appointmentService, userService)
+	bossHandler := handler.NewBossHandler(userService, serviceService, reportService, contractService, expenseService, salaryService, auditService, timeOffService, reminderService, userRepo)
+	receptionistHandler := handler.NewReceptionistHandler(patientService, appointmentService, userService, waitlistService, reminderService)
 	doctorHandler := handler.NewDoctorHandler(appointmentService, visitService, serviceService, auditService, treatmentPlanService)
+	publicHandler := handler.NewPublicHandler(publicBookingService)
+	paymentHandler := handler.NewPaymentHandler(paymentService, auditService)
+	documentHandler := handler.NewDocumentHandler(documentService)
+	adjustmentHandler := handler.NewAdjustmentHandler(adjustmentService, auditService)
+	cashShiftHandler := handler.NewCashShiftHandler(cashShiftService, auditService)
+	payoutHandler := handler.NewPayoutHandler(payoutService, auditService)
+	insuranceHandler := handler.NewInsuranceHandler(insuranceService, auditService)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService, auditService)
+	toothChartHandler := handler.NewToothChartHandler(toothChartService)
+	medicalHistoryHandler := handler.NewMedicalHistoryHandler(medicalHistoryService, auditService)
+	visitApprovalHandler := handler.NewVisitApprovalHandler(visitService, auditService)
 	healthHandler := handler.NewHealthHandler(mongoClient)
 
 	// Upload handler - uploads directory
@@ -130,6 +193,18 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 			auth.POST("/accept-invite", authHandler.AcceptInvite)
 		}
 
+		// Public online booking (no auth required, rate limited)
+		public := v1.Group("/public/clinics/:slug")
+		public.Use(middleware.RateLimit(middleware.NewRateLimiter(cfg.PublicRateLimit, time.Minute)))
+		{
+			public.GET("", publicHandler.GetClinic)
+			public.GET("/services", publicHandler.ListServices)
+			public.GET("/doctors", publicHandler.ListDoctors)
+			public.GET("/doctors/:id/slots", publicHandler.GetDoctorSlots)
+			public.POST("/verification-codes", publicHandler.RequestCode)
+			public.POST("/bookings", publicHandler.CreateBooking)
+		}
+
 		// Superadmin routes
 		admin := v1.Group("/admin")
 		admin.Use(middleware.Auth(cfg.JWTAccessSecret))
@@ -154,12 +229,17 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 			boss.GET("/users", bossHandler.ListUsers)
 			boss.DELETE("/users/:id", bossHandler.DeactivateUser)
 			boss.GET("/doctors", bossHandler.ListDoctors)
+			boss.GET("/doctors/:id/schedule", bossHandler.GetDoctorSchedule)
+			boss.PUT("/doctors/:id/schedule", bossHandler.UpdateDoctorSchedule)
 
 			// Service management
 			boss.POST("/services", bossHandler.CreateService)
 			boss.GET("/services", bossHandler.ListServices)
 			boss.PUT("/services/:id", bossHandler.UpdateService)
 			boss.DELETE("/services/:id", bossHandler.DeleteService)
+			boss.GET("/services/:id/prices", bossHandler.ListServicePrices)
+			boss.POST("/services/:id/prices", bossHandler.ScheduleServicePrice)
+			boss.DELETE("/services/:id/prices/:priceId", bossHandler.DeleteServicePrice)
 			boss.POST("/services/import", bossHandler.ImportServices)
 
 			// Doctor contracts
@@ -180,9 +260,71 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 			boss.PUT("/salaries/:id", bossHandler.UpdateSalary)
 			boss.DELETE("/salaries/:id", bossHandler.DeleteSalary)
 
+			// Clinic closures and doctor leaves
+			boss.POST("/closures", bossHandler.CreateClosure)
+			boss.GET("/closures", bossHandler.ListClosures)
+			boss.DELETE("/closures/:id", bossHandler.DeleteClosure)
+			boss.POST("/leaves", bossHandler.CreateLeave)
+			boss.GET("/leaves", bossHandler.ListLeaves)
+			boss.DELETE("/leaves/:id", bossHandler.DeleteLeave)
+
 			// Reports
 			boss.GET("/reports/daily", bossHandler.GetDailyReport)
 			boss.GET("/reports/monthly", bossHandler.GetMonthlyReport)
+			boss.GET("/reports/price-changes", bossHandler.GetPriceChangesReport)
+			boss.GET("/reports/doctor-payouts", bossHandler.GetDoctorPayoutsReport)
+
+			// Doctor payout statements
+			boss.POST("/payouts", payoutHandler.ClosePeriod)
+			boss.GET("/payouts", payoutHandler.ListPayouts)
+			boss.GET("/payouts/:id", payoutHandler.GetPayout)
+			boss.POST("/payouts/:id/payments", payoutHandler.RecordPayment)
+			boss.DELETE("/payouts/:id", payoutHandler.ReopenPayout)
+
+			// Insurance payers and claims
+			boss.POST("/insurance/payers", insuranceHandler.CreatePayer)
+			boss.GET("/insurance/payers", insuranceHandler.ListPayers)
+			boss.PUT("/insurance/payers/:id", insuranceHandler.UpdatePayer)
+			boss.GET("/insurance/claims", insuranceHandler.ListClaims)
+			boss.POST("/insurance/batches", insuranceHandler.CreateBatch)
+			boss.GET("/insurance/batches", insuranceHandler.ListBatches)
+			boss.GET("/insurance/batches/:id", insuranceHandler.GetBatch)
+			boss.GET("/insurance/batches/:id/export.csv", insuranceHandler.ExportBatch)
+			boss.PUT("/insurance/batches/:id/status", insuranceHandler.UpdateBatchStatus)
+			boss.DELETE("/insurance/batches/:id", insuranceHandler.DeleteBatch)
+
+			// Inventory
+			boss.POST("/inventory/items", inventoryHandler.CreateItem)
+			boss.GET("/inventory/items", inventoryHandler.ListItems)
+			boss.GET("/inventory/items/:id", inventoryHandler.GetItem)
+			boss.PUT("/inventory/items/:id", inventoryHandler.UpdateItem)
+			boss.POST("/inventory/items/:id/movements", inventoryHandler.RecordMovement)
+			boss.GET("/inventory/movements", inventoryHandler.ListMovements)
+			boss.GET("/inventory/low-stock", inventoryHandler.LowStock)
+			boss.PUT("/services/:id/materials", inventoryHandler.SetServiceMaterials)
+
+			// Payment methods
+			boss.GET("/payment-methods", paymentHandler.GetPaymentMethods)
+			boss.PUT("/payment-methods", paymentHandler.UpdatePaymentMethods)
+
+			// Cash register shift history
+			boss.GET("/shifts", cashShiftHandler.ListShifts)
+
+			// Doctor discounts above the clinic's limit
+			boss.GET("/discount-policy", visitApprovalHandler.GetDiscountPolicy)
+			boss.PUT("/discount-policy", visitApprovalHandler.UpdateDiscountPolicy)
+			boss.GET("/visits/pending-approval", visitApprovalHandler.ListPendingApproval)
+			boss.PUT("/visits/:id/approve", visitApprovalHandler.ApproveVisit)
+			boss.PUT("/visits/:id/reject", visitApprovalHandler.RejectVisit)
+
+			// Visit voids and refunds
+			boss.POST("/visits/:id/adjustments", adjustmentHandler.CreateAdjustment)
+			boss.GET("/visits/:id/adjustments", adjustmentHandler.ListVisitAdjustments)
+			boss.GET("/adjustments", adjustmentHandler.ListAdjustments)
+
+			// Appointment reminders
+			boss.GET("/reminders/settings", bossHandler.GetReminderSettings)
+			boss.PUT("/reminders/settings", bossHandler.UpdateReminderSettings)
 
 			// Audit Logs
 			boss.GET("/audit-logs", bossHandler.GetAuditLogs)
@@ -200,6 +342,55 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 			patients.GET("/:id", receptionistHandler.GetPatient)
 			patients.PUT("/:id", receptionistHandler.UpdatePatient)
 			patients.DELETE("/:id", receptionistHandler.DeletePatient)
+			patients.GET("/:id/balance", paymentHandler.GetPatientBalance)
+			patients.POST("/:id/policies", insuranceHandler.AddPolicy)
+			patients.PUT("/:id/policies/:policyId", insuranceHandler.UpdatePolicy)
+			patients.DELETE("/:id/policies/:policyId", insuranceHandler.DeletePolicy)
+			patients.GET("/:id/medical-history", medicalHistoryHandler.GetMedicalHistory)
+			patients.PUT("/:id/medical-history", medicalHistoryHandler.UpdateMedicalHistory)
+			patients.GET("/:id/medical-history/versions", medicalHistoryHandler.ListMedicalHistoryVersions)
+		}
+
+		// Insurance payers to pick policies from (all clinic staff)
+		insurance := v1.Group("/insurance")
+		insurance.Use(middleware.Auth(cfg.JWTAccessSecret))
+		insurance.Use(middleware.ClinicStaff())
+		insurance.Use(middleware.TenantIsolation())
+		{
+			insurance.GET("/payers", insuranceHandler.ListActivePayers)
+		}
+
+		// Payments ledger (receptionist and boss)
+		payments := v1.Group("/payments")
+		payments.Use(middleware.Auth(cfg.JWTAccessSecret))
+		payments.Use(middleware.BossOrReceptionist())
+		payments.Use(middleware.TenantIsolation())
+		{
+			payments.POST("", paymentHandler.CreatePayment)
+			payments.GET("", paymentHandler.ListPayments)
+			payments.GET("/methods", paymentHandler.GetPaymentMethods)
+			payments.GET("/:id/receipt.pdf", documentHandler.GetReceiptPDF)
+		}
+
+		// Cash register shifts (receptionist and boss)
+		shifts := v1.Group("/shifts")
+		shifts.Use(middleware.Auth(cfg.JWTAccessSecret))
+		shifts.Use(middleware.BossOrReceptionist())
+		shifts.Use(middleware.TenantIsolation())
+		{
+			shifts.POST("", cashShiftHandler.OpenShift)
+			shifts.GET("/current", cashShiftHandler.GetCurrentShift)
+			shifts.GET("/:id", cashShiftHandler.GetShift)
+			shifts.PUT("/:id/close", cashShiftHandler.CloseShift)
+		}
+
+		// Printable visit documents (all clinic staff)
+		visits := v1.Group("/visits")
+		visits.Use(middleware.Auth(cfg.JWTAccessSecret))
+		visits.Use(middleware.ClinicStaff())
+		visits.Use(middleware.TenantIsolation())
+		{
+			visits.GET("/:id/invoice.pdf", documentHandler.GetInvoicePDF)
 		}
 
 		// Appointment routes (all clinic staff can access)
@@ -210,9 +401,18 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 		{
 			appointments.POST("", receptionistHandler.CreateAppointment)
 			appointments.GET("", receptionistHandler.ListAppointments)
+			appointments.POST("/series", receptionistHandler.CreateAppointmentSeries)
+			appointments.GET("/series/:id", receptionistHandler.GetAppointmentSeries)
+			appointments.POST("/waitlist", receptionistHandler.CreateWaitlistEntry)
+			appointments.GET("/waitlist", receptionistHandler.ListWaitlist)
+			appointments.POST("/waitlist/:id/book", receptionistHandler.BookWaitlistOffer)
+			appointments.DELETE("/waitlist/:id", receptionistHandler.DeleteWaitlistEntry)
+			appointments.GET("/reminders", receptionistHandler.ListReminders)
 			appointments.GET("/:id", receptionistHandler.GetAppointment)
 			appointments.PUT("/:id/reschedule", receptionistHandler.RescheduleAppointment)
 			appointments.PUT("/:id/cancel", receptionistHandler.CancelAppointment)
+			appointments.PUT("/:id/reopen", receptionistHandler.ReopenAppointment)
+			appointments.PUT("/:id/approve", receptionistHandler.ApproveAppointment)
 		}
 
 		// Doctors list (accessible by receptionist for appointment creation)
@@ -222,6 +422,7 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 		doctors.Use(middleware.TenantIsolation())
 		{
 			doctors.GET("", receptionistHandler.ListDoctors)
+			doctors.GET("/:id/availability", receptionistHandler.GetDoctorAvailability)
 		}
 
 		// Doctor routes
@@ -237,6 +438,8 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 			doctor.PUT("/visits/:id/complete", doctorHandler.CompleteVisit)
 			doctor.PUT("/visits/:id/draft", doctorHandler.SaveVisitDraft)
 			doctor.GET("/services", doctorHandler.ListServices)
+			doctor.GET("/payouts", payoutHandler.ListMyPayouts)
+			doctor.GET("/payouts/:id", payoutHandler.GetPayout)
 			doctor.PUT("/appointments/:id/status", doctorHandler.UpdateAppointmentStatus)
 			// Treatment plans
 			doctor.POST("/treatment-plans", doctorHandler.CreateTreatmentPlan)
@@ -245,13 +448,46 @@ func Setup(cfg *config.Config, db *mongo.Database, mongoClient *mongo.Client, lo
 			doctor.PUT("/treatment-plans/:id/steps/:step", doctorHandler.UpdateTreatmentPlanStep)
 			// Patient visits history
 			doctor.GET("/patients/:id/visits", doctorHandler.GetPatientVisits)
+			// Tooth chart (odontogram)
+			doctor.GET("/patients/:id/chart", toothChartHandler.GetChart)
+			doctor.GET("/patients/:id/chart/history", toothChartHandler.GetHistory)
+			doctor.GET("/patients/:id/teeth/:tooth/history", toothChartHandler.GetToothHistory)
 			// X-ray image uploads
 			doctor.POST("/uploads/image", uploadHandler.UploadImage)
 			doctor.DELETE("/uploads/image", uploadHandler.DeleteImage)
 		}
 	}
 
-	return r
+	return r, scheduler
+}
+
+// buildNotifiers returns a notifier per reminder channel. Channels without
+// credentials log messages instead outside production and are unavailable in
+// production, so reminders there fail visibly rather than vanish.
+func buildNotifiers(cfg *config.Config, log *logger.Logger) map[string]notify.Notifier {
+	notifiers := make(map[string]notify.Notifier)
+	fallback := func(channel string) {
+		if cfg.Environment != "production" {
+			notifiers[channel] = notify.NewLogNotifier(log)
+		}
+	}
+
+	if cfg.SMSGatewayURL != "" {
+		notifiers[models.ReminderChannelSMS] = notify.NewSMSNotifier(cfg.SMSGatewayURL, cfg.SMSGatewayToken, cfg.SMSSender)
+	} else {
+		fallback(models.ReminderChannelSMS)
+	}
+	if cfg.TelegramBotToken != "" {
+		notifiers[models.ReminderChannelTelegram] = notify.NewTelegramNotifier(cfg.TelegramBotToken)
+	} else {
+		fallback(models.ReminderChannelTelegram)
+	}
+	if cfg.ResendAPIKey != "" {
+		notifiers[models.ReminderChannelEmail] = notify.NewEmailNotifier(email.NewResendClient())
+	} else {
+		fallback(models.ReminderChannelEmail)
+	}
+	return notifiers
 }
 
 // CreateIndexes creates all MongoDB indexes
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AdjustmentService voids and refunds completed visits with reversing
+// entries. The visit's billed lines are kept as completed; reversals, the
+// doctor earning claw-back and refunds paid back are recorded alongside.
+type AdjustmentService struct {
+	adjustmentRepo *repository.VisitAdjustmentRepository
+	visitRepo      *repository.VisitRepository
+	paymentRepo    *repository.PaymentRepository
+	patientRepo    *repository.PatientRepository
+	userRepo       *repository.UserRepository
+	clinicRepo     *repository.ClinicRepository
+	counterRepo    *repository.CounterRepository
+	shiftRepo      *repository.CashShiftRepository
+	payoutRepo     *repository.PayoutStatementRepository
+	clock          *ClinicClock
+	locker         *Locker
+}
+
+func NewAdjustmentService(
+	adjustmentRepo *repository.VisitAdjustmentRepository,
+	visitRepo *repository.VisitRepository,
+	paymentRepo *repository.PaymentRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	clinicRepo *repository.ClinicRepository,
+	counterRepo *repository.CounterRepository,
+	shiftRepo *repository.CashShiftRepository,
+	payoutRepo *repository.PayoutStatementRepository,
+	clock *ClinicClock,
+	locker *Locker,
+) *AdjustmentService {
+	return &AdjustmentService{
+		adjustmentRepo: adjustmentRepo,
+		visitRepo:      visitRepo,
+		paymentRepo:    paymentRepo,
+		patientRepo:    patientRepo,
+		userRepo:       userRepo,
+		clinicRepo:     clinicRepo,
+		counterRepo:    counterRepo,
+		shiftRepo:      shiftRepo,
+		payoutRepo:     payoutRepo,
+		clock:          clock,
+		locker:         locker,
+	}
+}
+
+// Create voids a visit or refunds some of its service lines. Money the
+// patient paid beyond their share of the new net total is paid back as a
+// negative ledger entry; an insurer's share shrinks in proportion.
+func (s *AdjustmentService) Create(ctx context.Context, visitID, clinicID, actorID primitive.ObjectID, dto models.CreateAdjustmentDTO) (*models.VisitAdjustment, error) {
+	visit, err := s.visitRepo.GetByID(ctx, visitID, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Visit")
+	}
+	if visit.Status != models.VisitStatusCompleted {
+		return nil, apperrors.BadRequest("Only completed visits can be voided or refunded")
+	}
+	if visit.NetTotal() == 0 && visit.ReversedAmount > 0 {
+		return nil, apperrors.BadRequest("Visit is already fully reversed")
+	}
+	// The earning claw-back is posted today, so today's period must be open
+	now := time.Now().UTC()
+	if err := ensurePeriodOpen(ctx, s.payoutRepo, clinicID, visit.DoctorID, s.clock.DateOf(ctx, clinicID, now)); err != nil {
+		return nil, err
+	}
+
+	prior, err := s.adjustmentRepo.ListByVisit(ctx, visit.ID, clinicID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to load visit adjustments", err)
+	}
+	remaining := remainingLines(visit, prior)
+
+	var lines []models.AdjustmentLine
+	switch dto.Type {
+	case models.AdjustmentTypeVoid:
+		lines = remaining
+	case models.AdjustmentTypeRefund:
+		if len(dto.Lines) == 0 {
+			return nil, apperrors.BadRequest("Select the service lines to refund")
+		}
+		lines, err = takeLines(remaining, dto.Lines)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, apperrors.BadRequest("Invalid adjustment type")
+	}
+
+	// Lines carry their share of the visit discount; reversing everything
+	// that is left reverses exactly the net total so no cents linger
+	factor := 1.0
+	if visit.Subtotal > 0 {
+		factor = visit.Total / visit.Subtotal
+	}
+	amount := 0.0
+	for i := range lines {
+		lines[i].Amount = roundMoney(lines[i].Price * float64(lines[i].Quantity) * factor)
+		amount += lines[i].Amount
+	}
+	netTotal := visit.NetTotal()
+	if dto.Type == models.AdjustmentTypeVoid || coversAll(remaining, lines) || amount > netTotal {
+		amount = netTotal
+	}
+	amount = roundMoney(amount)
+	if amount <= 0 {
+		return nil, apperrors.BadRequest("Nothing left to reverse on this visit")
+	}
+
+	// Claw back the doctor's earning in proportion to the reversed amount
+	earning := visit.NetEarning()
+	if amount < netTotal && netTotal > 0 {
+		earning = roundMoney(visit.NetEarning() * amount / netTotal)
+	}
+
+	// The patient is refunded what they paid beyond their share of what is left
+	refund := roundMoney(visit.PaidAmount - visit.PatientShareOf(netTotal-amount))
+	if refund < models.MoneyEpsilon {
+		refund = 0
+	}
+
+	adjustment := &models.VisitAdjustment{
+		ClinicID:        clinicID,
+		VisitID:         visit.ID,
+		PatientID:       visit.PatientID,
+		DoctorID:        visit.DoctorID,
+		VisitDate:       visit.Date,
+		Type:            dto.Type,
+		Lines:           lines,
+		Amount:          amount,
+		EarningReversal: earning,
+		RefundAmount:    refund,
+		Reason:          dto.Reason,
+		Date:            s.clock.DateOf(ctx, clinicID, now),
+		CreatedBy:       actorID,
+	}
+
+	var refundPayment *models.Payment
+	var shift *models.CashShift
+	if refund > 0 {
+		method := dto.RefundMethod
+		if method == "" {
+			method = models.PaymentTypeCash
+		}
+		clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+		if err != nil {
+			return nil, apperrors.NotFound("Clinic")
+		}
+		if !clinic.AcceptsPaymentMethod(method) {
+			return nil, apperrors.BadRequest("Payment method not accepted by the clinic: " + method)
+		}
+		shift, err = s.refundShift(ctx, clinicID, actorID, dto.RefundShift, method == models.PaymentTypeCash)
+		if err != nil {
+			return nil, err
+		}
+		receiptNumber, err := s.counterRepo.Next(ctx, clinicID, models.CounterReceipt)
+		if err != nil {
+			return nil, apperrors.InternalWithErr("Failed to number refund receipt", err)
+		}
+		refundPayment = &models.Payment{
+			ID:            primitive.NewObjectID(),
+			ClinicID:      clinicID,
+			VisitID:       visit.ID,
+			PatientID:     visit.PatientID,
+			Amount:        -refund,
+			Method:        method,
+			CashierID:     actorID,
+			PaidAt:        now,
+			Date:          adjustment.Date,
+			Notes:         "Refund: " + dto.Reason,
+			ReceiptNumber: receiptNumber,
+		}
+		if shift != nil {
+			refundPayment.ShiftID = &shift.ID
+		}
+		adjustment.RefundPaymentID = &refundPayment.ID
+	}
+
+	err = intoShift(ctx, s.locker, s.shiftRepo, shift, func() error {
+		ok, err := s.visitRepo.AddAdjustment(ctx, visit, amount, earning, refund)
+		if err != nil {
+			return apperrors.InternalWithErr("Failed to adjust visit", err)
+		}
+		if !ok {
+			return apperrors.Conflict("Visit balance changed, please reload and try again")
+		}
+
+		if err := s.adjustmentRepo.Create(ctx, adjustment); err != nil {
+			s.undo(ctx, visit, amount, earning, refund)
+			return apperrors.InternalWithErr("Failed to record adjustment", err)
+		}
+
+		if refundPayment != nil {
+			refundPayment.AdjustmentID = &adjustment.ID
+			if err := s.paymentRepo.Create(ctx, refundPayment); err != nil {
+				_ = s.adjustmentRepo.Delete(ctx, adjustment.ID, clinicID)
+				s.undo(ctx, visit, amount, earning, refund)
+				return apperrors.InternalWithErr("Failed to record refund", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustment, nil
+}
+
+// refundShift returns the open shift a refund is paid from: the named one,
+// or else the actor's own. Cash refunds must come out of an open shift so
+// the drawer is reconciled at close; other refunds may have none.
+func (s *AdjustmentService) refundShift(ctx context.Context, clinicID, actorID primitive.ObjectID, shiftID string, cash bool) (*models.CashShift, error) {
+	if shiftID != "" {
+		id, err := primitive.ObjectIDFromHex(shiftID)
+		if err != nil {
+			return nil, apperrors.BadRequest("Invalid refund shift ID")
+		}
+		shift, err := s.shiftRepo.GetByID(ctx, id, clinicID)
+		if err != nil {
+			return nil, apperrors.NotFound("Cash shift")
+		}
+		if shift.Status != models.CashShiftStatusOpen {
+			return nil, apperrors.BadRequest("Refund shift is closed")
+		}
+		return shift, nil
+	}
+
+	shift, err := s.shiftRepo.GetOpen(ctx, clinicID, actorID)
+	if err == nil {
+		return shift, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, apperrors.InternalWithErr("Failed to find cash shift", err)
+	}
+	if cash {
+		return nil, apperrors.BadRequest("Cash refunds must be paid from an open shift; pass refund_shift_id or open one")
+	}
+	return nil, nil
+}
+
+// undo reverts AddAdjustment when the adjustment could not be recorded
+func (s *AdjustmentService) undo(ctx context.Context, visit *models.Visit, amount, earning, refund float64) {
+	applied := *visit
+	applied.ReversedAmount += amount
+	applied.PaidAmount -= refund
+	_, _ = s.visitRepo.AddAdjustment(ctx, &applied, -amount, -earning, -refund)
+}
+
+// ListByVisit returns the adjustments of one visit, oldest first
+func (s *AdjustmentService) ListByVisit(ctx context.Context, visitID, clinicID primitive.ObjectID) ([]models.VisitAdjustmentResponse, error) {
+	if _, err := s.visitRepo.GetByID(ctx, visitID, clinicID); err != nil {
+		return nil, apperrors.NotFound("Visit")
+	}
+	adjustments, err := s.adjustmentRepo.ListByVisit(ctx, visitID, clinicID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list adjustments", err)
+	}
+	return s.toResponses(ctx, clinicID, adjustments), nil
+}
+
+// List returns adjustments posted in a clinic-local date range
+func (s *AdjustmentService) List(ctx context.Context, clinicID primitive.ObjectID, fromDate, toDate string) ([]models.VisitAdjustmentResponse, error) {
+	if fromDate == "" || toDate == "" {
+		today := s.clock.Today(ctx, clinicID)
+		if fromDate == "" {
+			fromDate = today
+		}
+		if toDate == "" {
+			toDate = today
+		}
+	}
+	for _, d := range []string{fromDate, toDate} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, apperrors.BadRequest("Invalid date format, expected YYYY-MM-DD")
+		}
+	}
+
+	adjustments, err := s.adjustmentRepo.ListByDateRange(ctx, clinicID, fromDate, toDate)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list adjustments", err)
+	}
+	return s.toResponses(ctx, clinicID, adjustments), nil
+}
+
+func (s *AdjustmentService) toResponses(ctx context.Context, clinicID primitive.ObjectID, adjustments []models.VisitAdjustment) []models.VisitAdjustmentResponse {
+	names := make(map[primitive.ObjectID]string)
+	responses := make([]models.VisitAdjustmentResponse, 0, len(adjustments))
+	for _, a := range adjustments {
+		resp := a.ToResponse()
+
+		if name, ok := names[a.PatientID]; ok {
+			resp.PatientName = name
+		} else if patient, err := s.patientRepo.GetByID(ctx, a.PatientID, clinicID); err == nil {
+			resp.PatientName = patient.FirstName + " " + patient.LastName
+			names[a.PatientID] = resp.PatientName
+		}
+		if name, ok := names[a.DoctorID]; ok {
+			resp.DoctorName = name
+		} else if doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, a.DoctorID, clinicID); err == nil {
+			resp.DoctorName = doctor.FirstName + " " + doctor.LastName
+			names[a.DoctorID] = resp.DoctorName
+		}
+
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+// remainingLines returns, per visit line, the quantity not reversed yet.
+// Earlier reversals are matched to lines of the same service in order.
+func remainingLines(visit *models.Visit, prior []models.VisitAdjustment) []models.AdjustmentLine {
+	reversed := make(map[primitive.ObjectID]int)
+	for _, a := range prior {
+		for _, l := range a.Lines {
+			reversed[l.ServiceID] += l.Quantity
+		}
+	}
+
+	lines := make([]models.AdjustmentLine, 0, len(visit.Services))
+	for _, svc := range visit.Services {
+		qty := svc.Quantity
+		taken := reversed[svc.ServiceID]
+		if taken > qty {
+			taken = qty
+		}
+		reversed[svc.ServiceID] -= taken
+		if qty-taken == 0 {
+			continue
+		}
+		lines = append(lines, models.AdjustmentLine{
+			ServiceID:   svc.ServiceID,
+			ServiceName: svc.ServiceName,
+			Price:       svc.NetUnitPrice(),
+			Quantity:    qty - taken,
+		})
+	}
+	return lines
+}
+
+// takeLines picks the requested quantities out of the remaining lines
+func takeLines(remaining []models.AdjustmentLine, requested []models.AdjustmentLineDTO) ([]models.AdjustmentLine, error) {
+	left := make([]int, len(remaining))
+	for i, l := range remaining {
+		left[i] = l.Quantity
+	}
+	taken := make([]int, len(remaining))
+
+	for _, req := range requested {
+		serviceID, err := primitive.ObjectIDFromHex(req.ServiceID)
+		if err != nil {
+			return nil, apperrors.BadRequest("Invalid service ID")
+		}
+		want := req.Quantity
+		for i, l := range remaining {
+			if want == 0 {
+				break
+			}
+			if l.ServiceID != serviceID || left[i] == 0 {
+				continue
+			}
+			n := want
+			if n > left[i] {
+				n = left[i]
+			}
+			left[i] -= n
+			taken[i] += n
+			want -= n
+		}
+		if want > 0 {
+			return nil, apperrors.BadRequest(fmt.Sprintf("Service %s has fewer unrefunded units than requested", req.ServiceID))
+		}
+	}
+
+	lines := make([]models.AdjustmentLine, 0, len(remaining))
+	for i, l := range remaining {
+		if taken[i] == 0 {
+			continue
+		}
+		l.Quantity = taken[i]
+		lines = append(lines, l)
+	}
+	return lines, nil
+}
+
+// coversAll reports whether lines take every remaining unit
+func coversAll(remaining, lines []models.AdjustmentLine) bool {
+	left := 0
+	for _, l := range remaining {
+		left += l.Quantity
+	}
+	for _, l := range lines {
+		left -= l.Quantity
+	}
+	return left == 0
+}
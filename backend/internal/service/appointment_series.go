@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+	apperrors "medical-crm/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CreateSeries expands a recurrence rule into individual appointments linked
+// by a series ID. Occurrences that cannot be booked are reported per date in
+// Conflicts instead of failing the whole request.
+func (s *AppointmentService) CreateSeries(ctx context.Context, dto models.CreateAppointmentSeriesDTO, clinicID, creatorID primitive.ObjectID) (*models.AppointmentSeriesResponse, error) {
+	rule, err := models.ParseRecurrenceRule(dto.RRule)
+	if err != nil {
+		return nil, apperrors.Validation("Invalid rrule: " + err.Error())
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(dto.PatientID)
+	if err != nil {
+		return nil, apperrors.BadRequest("Invalid patient ID")
+	}
+
+	doctorID, err := primitive.ObjectIDFromHex(dto.DoctorID)
+	if err != nil {
+		return nil, apperrors.BadRequest("Invalid doctor ID")
+	}
+
+	if _, err := s.patientRepo.GetByID(ctx, patientID, clinicID); err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID)
+	if err != nil || doctor.Role != models.RoleDoctor {
+		return nil, apperrors.NotFound("Doctor")
+	}
+
+	serviceIDs, duration, err := s.plannedDuration(ctx, clinicID, dto.ServiceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := s.clock.Location(ctx, clinicID)
+	startTime := normalizeStartTime(dto.StartTime)
+	occurrences, err := rule.Expand(startTime, loc)
+	if err != nil {
+		return nil, apperrors.Validation("Invalid rrule: " + err.Error())
+	}
+
+	series := &models.AppointmentSeries{
+		ClinicID:   clinicID,
+		PatientID:  patientID,
+		DoctorID:   doctorID,
+		RRule:      dto.RRule,
+		StartTime:  startTime,
+		Duration:   int(duration / time.Minute),
+		ServiceIDs: serviceIDs,
+		Notes:      dto.Notes,
+		CreatedBy:  creatorID,
+	}
+	if err := s.seriesRepo.Create(ctx, series); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to create appointment series", err)
+	}
+
+	resp := series.ToResponse()
+	now := time.Now().UTC()
+	// The doctor's booking lock is held for the whole series so no other
+	// booking lands between an occurrence's check and its insert
+	err = s.locker.Do(ctx, doctorLock(doctor.ID), func() error {
+		return s.bookOccurrences(ctx, &resp, series, doctor, occurrences, duration, now, loc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// bookOccurrences creates the series' future occurrences whose slots are
+// free and reports the others as conflicts. The caller holds the doctor's
+// booking lock.
+func (s *AppointmentService) bookOccurrences(ctx context.Context, resp *models.AppointmentSeriesResponse, series *models.AppointmentSeries, doctor *models.User, occurrences []time.Time, duration time.Duration, now time.Time, loc *time.Location) error {
+	for _, start := range occurrences {
+		start = start.UTC()
+		end := start.Add(duration)
+
+		if start.Before(now) {
+			resp.Conflicts = append(resp.Conflicts, occurrenceConflict(start, loc, apperrors.BadRequest("Cannot create appointment in the past")))
+			continue
+		}
+		if err := s.checkSlot(ctx, series.ClinicID, doctor, start, end, nil); err != nil {
+			appErr, ok := err.(*apperrors.AppError)
+			if !ok || appErr.Code == apperrors.CodeInternal {
+				return err
+			}
+			resp.Conflicts = append(resp.Conflicts, occurrenceConflict(start, loc, appErr))
+			continue
+		}
+
+		appointment := &models.Appointment{
+			ClinicID:   series.ClinicID,
+			PatientID:  series.PatientID,
+			DoctorID:   series.DoctorID,
+			Date:       models.LocalDate(start, loc),
+			StartTime:  start,
+			EndTime:    end,
+			ServiceIDs: series.ServiceIDs,
+			SeriesID:   &series.ID,
+			Notes:      series.Notes,
+			CreatedBy:  series.CreatedBy,
+		}
+		if err := s.appointmentRepo.Create(ctx, appointment); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				resp.Conflicts = append(resp.Conflicts, occurrenceConflict(start, loc, apperrors.AppointmentConflict()))
+				continue
+			}
+			return apperrors.InternalWithErr("Failed to create appointment", err)
+		}
+		resp.Appointments = append(resp.Appointments, appointment.ToResponse())
+	}
+	return nil
+}
+
+// GetSeries returns a series with all of its occurrences
+func (s *AppointmentService) GetSeries(ctx context.Context, id, clinicID primitive.ObjectID) (*models.AppointmentSeriesResponse, error) {
+	series, err := s.seriesRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Appointment series")
+	}
+
+	appointments, err := s.appointmentRepo.ListBySeries(ctx, clinicID, id)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list series appointments", err)
+	}
+
+	resp := series.ToResponse()
+	for _, a := range appointments {
+		resp.Appointments = append(resp.Appointments, a.ToResponse())
+	}
+	return &resp, nil
+}
+
+// RescheduleFollowing moves an occurrence and every later scheduled occurrence
+// of its series. Later occurrences keep their relative day offset and take the
+// new clinic-local time of day. Occurrences that cannot be moved stay in place
+// and are reported per date.
+func (s *AppointmentService) RescheduleFollowing(ctx context.Context, id, clinicID primitive.ObjectID, newStartTime time.Time) (int, []models.OccurrenceConflict, error) {
+	appointment, err := s.appointmentRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return 0, nil, apperrors.NotFound("Appointment")
+	}
+	if appointment.SeriesID == nil {
+		return 0, nil, apperrors.BadRequest("Appointment is not part of a series")
+	}
+
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, appointment.DoctorID, clinicID)
+	if err != nil {
+		return 0, nil, apperrors.NotFound("Doctor")
+	}
+
+	occurrences, err := s.appointmentRepo.ListUpcomingInSeries(ctx, clinicID, *appointment.SeriesID, appointment.StartTime)
+	if err != nil {
+		return 0, nil, apperrors.InternalWithErr("Failed to list series appointments", err)
+	}
+
+	loc := s.clock.Location(ctx, clinicID)
+	oldLocal := appointment.StartTime.In(loc)
+	newLocal := normalizeStartTime(newStartTime).In(loc)
+	dayShift := daysBetween(oldLocal, newLocal)
+
+	// Move the furthest occurrence first when shifting later (and the nearest
+	// first when shifting earlier) so occurrences don't collide with each other
+	if newLocal.After(oldLocal) {
+		for i, j := 0, len(occurrences)-1; i < j; i, j = i+1, j-1 {
+			occurrences[i], occurrences[j] = occurrences[j], occurrences[i]
+		}
+	}
+
+	var moved int
+	var conflicts []models.OccurrenceConflict
+	err = s.locker.Do(ctx, doctorLock(doctor.ID), func() error {
+		var moveErr error
+		moved, conflicts, moveErr = s.moveOccurrences(ctx, clinicID, doctor, occurrences, dayShift, newLocal, loc)
+		return moveErr
+	})
+	return moved, conflicts, err
+}
+
+// moveOccurrences moves each occurrence by dayShift days to newLocal's time
+// of day where the slot is free and reports the others as conflicts. The
+// caller holds the doctor's booking lock.
+func (s *AppointmentService) moveOccurrences(ctx context.Context, clinicID primitive.ObjectID, doctor *models.User, occurrences []models.Appointment, dayShift int, newLocal time.Time, loc *time.Location) (int, []models.OccurrenceConflict, error) {
+	moved := 0
+	var conflicts []models.OccurrenceConflict
+	for i := range occurrences {
+		occ := &occurrences[i]
+		local := occ.StartTime.In(loc)
+		start := time.Date(local.Year(), local.Month(), local.Day()+dayShift, newLocal.Hour(), newLocal.Minute(), 0, 0, loc).UTC()
+		end := start.Add(occ.Duration())
+
+		if err := s.checkSlot(ctx, clinicID, doctor, start, end, &occ.ID); err != nil {
+			appErr, ok := err.(*apperrors.AppError)
+			if !ok || appErr.Code == apperrors.CodeInternal {
+				return moved, conflicts, err
+			}
+			conflicts = append(conflicts, occurrenceConflict(start, loc, appErr))
+			continue
+		}
+
+		occ.StartTime = start
+		occ.EndTime = end
+		occ.Date = models.LocalDate(start, loc)
+		if err := s.appointmentRepo.Update(ctx, occ); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				conflicts = append(conflicts, occurrenceConflict(start, loc, apperrors.AppointmentConflict()))
+				continue
+			}
+			if err == mongo.ErrNoDocuments {
+				conflicts = append(conflicts, occurrenceConflict(start, loc, apperrors.InvalidTransition("Appointment status was changed concurrently")))
+				continue
+			}
+			return moved, conflicts, apperrors.InternalWithErr("Failed to reschedule appointment", err)
+		}
+		moved++
+	}
+
+	return moved, conflicts, nil
+}
+
+// CancelFollowing cancels an occurrence and every later scheduled occurrence of its series
+func (s *AppointmentService) CancelFollowing(ctx context.Context, id, clinicID, actorID primitive.ObjectID) (int, error) {
+	appointment, err := s.appointmentRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return 0, apperrors.NotFound("Appointment")
+	}
+	if appointment.SeriesID == nil {
+		return 0, apperrors.BadRequest("Appointment is not part of a series")
+	}
+
+	occurrences, err := s.appointmentRepo.ListUpcomingInSeries(ctx, clinicID, *appointment.SeriesID, appointment.StartTime)
+	if err != nil {
+		return 0, apperrors.InternalWithErr("Failed to list series appointments", err)
+	}
+
+	cancelled := 0
+	for i := range occurrences {
+		occ := &occurrences[i]
+		if err := s.appointmentRepo.TransitionStatus(ctx, occ.ID, clinicID, occ.Status, models.AppointmentStatusCancelled, actorID); err != nil {
+			if err == mongo.ErrNoDocuments {
+				// Changed since it was listed (e.g. the visit started); leave it
+				continue
+			}
+			return cancelled, apperrors.InternalWithErr("Failed to cancel appointment", err)
+		}
+		cancelled++
+		s.waitlist.OfferFreedSlot(ctx, occ)
+	}
+	return cancelled, nil
+}
+
+// occurrenceConflict describes a failed occurrence for the API response
+func occurrenceConflict(start time.Time, loc *time.Location, err *apperrors.AppError) models.OccurrenceConflict {
+	return models.OccurrenceConflict{
+		Date:      models.LocalDate(start, loc),
+		StartTime: start,
+		Code:      err.Code,
+		Message:   err.Message,
+	}
+}
+
+// daysBetween returns the number of calendar days from a to b (both in the same location)
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"medical-crm/internal/models"
@@ -16,30 +17,71 @@ type AppointmentService struct {
 	appointmentRepo *repository.AppointmentRepository
 	patientRepo     *repository.PatientRepository
 	userRepo        *repository.UserRepository
+	serviceRepo     *repository.ServiceRepository
+	clock           *ClinicClock
+	timeOffRepo     *repository.TimeOffRepository
+	seriesRepo      *repository.AppointmentSeriesRepository
+	waitlist        *WaitlistService
+	locker          *Locker
 }
 
 func NewAppointmentService(
 	appointmentRepo *repository.AppointmentRepository,
 	patientRepo *repository.PatientRepository,
 	userRepo *repository.UserRepository,
+	serviceRepo *repository.ServiceRepository,
+	clock *ClinicClock,
+	timeOffRepo *repository.TimeOffRepository,
+	seriesRepo *repository.AppointmentSeriesRepository,
+	waitlist *WaitlistService,
+	locker *Locker,
 ) *AppointmentService {
 	return &AppointmentService{
 		appointmentRepo: appointmentRepo,
 		patientRepo:     patientRepo,
 		userRepo:        userRepo,
+		serviceRepo:     serviceRepo,
+		clock:           clock,
+		timeOffRepo:     timeOffRepo,
+		seriesRepo:      seriesRepo,
+		waitlist:        waitlist,
+		locker:          locker,
 	}
 }
 
+// availabilityStep is the granularity of offered start times
+const availabilityStep = 15 * time.Minute
+
+// maxAvailabilityDays caps the range of a single availability query
+const maxAvailabilityDays = 31
+
 // Create creates a new appointment with overlap prevention
 func (s *AppointmentService) Create(ctx context.Context, dto models.CreateAppointmentDTO, clinicID, creatorID primitive.ObjectID) (*models.Appointment, error) {
-	patientID, err := primitive.ObjectIDFromHex(dto.PatientID)
+	return s.create(ctx, dto, clinicID, creatorID, models.AppointmentStatusScheduled, false)
+}
+
+// CreatePending books an online request in the pending status. Unlike staff
+// bookings, the doctor must have a working schedule so the request falls
+// within published availability.
+func (s *AppointmentService) CreatePending(ctx context.Context, dto models.CreateAppointmentDTO, clinicID primitive.ObjectID) (*models.Appointment, error) {
+	return s.create(ctx, dto, clinicID, models.SystemActorID, models.AppointmentStatusPending, true)
+}
+
+// CheckPending runs the checks CreatePending makes on the doctor and slot
+// without booking, so callers can validate a request before acting on it.
+// PatientID is not checked.
+func (s *AppointmentService) CheckPending(ctx context.Context, dto models.CreateAppointmentDTO, clinicID primitive.ObjectID) error {
+	appointment, doctor, err := s.prepare(ctx, dto, clinicID, true)
 	if err != nil {
-		return nil, apperrors.BadRequest("Invalid patient ID")
+		return err
 	}
+	return s.checkSlot(ctx, clinicID, doctor, appointment.StartTime, appointment.EndTime, nil)
+}
 
-	doctorID, err := primitive.ObjectIDFromHex(dto.DoctorID)
+func (s *AppointmentService) create(ctx context.Context, dto models.CreateAppointmentDTO, clinicID, creatorID primitive.ObjectID, status string, requireSchedule bool) (*models.Appointment, error) {
+	patientID, err := primitive.ObjectIDFromHex(dto.PatientID)
 	if err != nil {
-		return nil, apperrors.BadRequest("Invalid doctor ID")
+		return nil, apperrors.BadRequest("Invalid patient ID")
 	}
 
 	// Verify patient exists in this clinic
@@ -48,38 +90,74 @@ func (s *AppointmentService) Create(ctx context.Context, dto models.CreateAppoin
 		return nil, apperrors.NotFound("Patient")
 	}
 
+	appointment, doctor, err := s.prepare(ctx, dto, clinicID, requireSchedule)
+	if err != nil {
+		return nil, err
+	}
+	appointment.PatientID = patientID
+	appointment.Status = status
+	appointment.CreatedBy = creatorID
+
+	err = s.reserve(ctx, clinicID, doctor, appointment.StartTime, appointment.EndTime, nil, func() error {
+		if err := s.appointmentRepo.Create(ctx, appointment); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return apperrors.AppointmentConflict()
+			}
+			return apperrors.InternalWithErr("Failed to create appointment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
+
+// prepare validates the doctor and time of a new appointment. The slot is
+// checked by the caller.
+func (s *AppointmentService) prepare(ctx context.Context, dto models.CreateAppointmentDTO, clinicID primitive.ObjectID, requireSchedule bool) (*models.Appointment, *models.User, error) {
+	doctorID, err := primitive.ObjectIDFromHex(dto.DoctorID)
+	if err != nil {
+		return nil, nil, apperrors.BadRequest("Invalid doctor ID")
+	}
+
 	// Verify doctor exists in this clinic
 	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID)
 	if err != nil || doctor.Role != models.RoleDoctor {
-		return nil, apperrors.NotFound("Doctor")
+		return nil, nil, apperrors.NotFound("Doctor")
+	}
+	if requireSchedule && doctor.WorkSchedule == nil {
+		return nil, nil, apperrors.OutsideWorkingHours()
 	}
 
-	// Normalize start time to 30-minute slot boundary
-	startTime := normalizeToSlot(dto.StartTime)
+	startTime := normalizeStartTime(dto.StartTime)
 
 	// Validate appointment time (not in the past)
 	if startTime.Before(time.Now().UTC()) {
-		return nil, apperrors.BadRequest("Cannot create appointment in the past")
-	}
-
-	appointment := &models.Appointment{
-		ClinicID:  clinicID,
-		PatientID: patientID,
-		DoctorID:  doctorID,
-		StartTime: startTime,
-		Notes:     dto.Notes,
-		CreatedBy: creatorID,
+		return nil, nil, apperrors.BadRequest("Cannot create appointment in the past")
 	}
 
-	// The unique index on (clinic_id, doctor_id, start_time) will prevent duplicates
-	if err := s.appointmentRepo.Create(ctx, appointment); err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			return nil, apperrors.AppointmentConflict()
-		}
-		return nil, apperrors.InternalWithErr("Failed to create appointment", err)
+	// Length is driven by the planned services
+	serviceIDs, duration, err := s.plannedDuration(ctx, clinicID, dto.ServiceIDs)
+	if err != nil {
+		return nil, nil, err
 	}
+	endTime := startTime.Add(duration)
+
+	return &models.Appointment{
+		ClinicID:   clinicID,
+		DoctorID:   doctorID,
+		Date:       s.clock.DateOf(ctx, clinicID, startTime),
+		StartTime:  startTime,
+		EndTime:    endTime,
+		ServiceIDs: serviceIDs,
+		Notes:      dto.Notes,
+	}, doctor, nil
+}
 
-	return appointment, nil
+// Today returns the current date in the clinic's time zone
+func (s *AppointmentService) Today(ctx context.Context, clinicID primitive.ObjectID) string {
+	return s.clock.Today(ctx, clinicID)
 }
 
 // GetByID retrieves an appointment by ID
@@ -217,15 +295,80 @@ func (s *AppointmentService) ListByDateRange(ctx context.Context, clinicID primi
 	return responses, total, nil
 }
 
-// UpdateStatus updates only the appointment status
-func (s *AppointmentService) UpdateStatus(ctx context.Context, id, clinicID primitive.ObjectID, status string) error {
+// UpdateStatus moves an appointment to a new status along the allowed
+// transitions and records who made the change. A cancelled or no-show
+// appointment frees its slot, which is offered to the waitlist.
+func (s *AppointmentService) UpdateStatus(ctx context.Context, id, clinicID primitive.ObjectID, status string, actorID primitive.ObjectID) error {
 	// Verify appointment exists
-	_, err := s.appointmentRepo.GetByID(ctx, id, clinicID)
+	appointment, err := s.appointmentRepo.GetByID(ctx, id, clinicID)
 	if err != nil {
 		return apperrors.NotFound("Appointment")
 	}
 
-	return s.appointmentRepo.UpdateStatus(ctx, id, clinicID, status)
+	if err := s.transition(ctx, appointment, status, actorID); err != nil {
+		return err
+	}
+
+	if status == models.AppointmentStatusCancelled || status == models.AppointmentStatusNoShow {
+		s.waitlist.OfferFreedSlot(ctx, appointment)
+	}
+	return nil
+}
+
+// Reopen brings a cancelled or no-show appointment back to scheduled,
+// provided its slot is still free
+func (s *AppointmentService) Reopen(ctx context.Context, id, clinicID, actorID primitive.ObjectID) (*models.Appointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Appointment")
+	}
+	if !models.CanReopenAppointment(appointment.Status) {
+		return nil, apperrors.InvalidTransition("Only cancelled or no-show appointments can be reopened")
+	}
+
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, appointment.DoctorID, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Doctor")
+	}
+	err = s.reserve(ctx, clinicID, doctor, appointment.StartTime, appointment.EndTime, &appointment.ID, func() error {
+		if err := s.appointmentRepo.TransitionStatus(ctx, id, clinicID, appointment.Status, models.AppointmentStatusScheduled, actorID); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return apperrors.AppointmentConflict()
+			}
+			return transitionError(err, "Failed to reopen appointment")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.waitlist.WithdrawOffers(ctx, appointment)
+
+	appointment, err = s.appointmentRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to load appointment", err)
+	}
+	return appointment, nil
+}
+
+// transition applies a status change after checking it against the transition table
+func (s *AppointmentService) transition(ctx context.Context, appointment *models.Appointment, to string, actorID primitive.ObjectID) error {
+	if !models.CanTransitionAppointment(appointment.Status, to) {
+		return apperrors.InvalidTransition(fmt.Sprintf("Cannot change appointment status from %s to %s", appointment.Status, to))
+	}
+	if err := s.appointmentRepo.TransitionStatus(ctx, appointment.ID, appointment.ClinicID, appointment.Status, to, actorID); err != nil {
+		return transitionError(err, "Failed to update appointment status")
+	}
+	return nil
+}
+
+// transitionError maps a failed conditional write to an API error; a missing
+// match means the status was changed by someone else in the meantime
+func transitionError(err error, message string) error {
+	if err == mongo.ErrNoDocuments {
+		return apperrors.InvalidTransition("Appointment status was changed concurrently, please reload")
+	}
+	return apperrors.InternalWithErr(message, err)
 }
 
 // Reschedule changes the appointment time with overlap prevention
@@ -234,40 +377,302 @@ func (s *AppointmentService) Reschedule(ctx context.Context, id, clinicID primit
 	if err != nil {
 		return apperrors.NotFound("Appointment")
 	}
+	if !models.CanRescheduleAppointment(appointment.Status) {
+		return apperrors.InvalidTransition(fmt.Sprintf("Cannot reschedule a %s appointment", appointment.Status))
+	}
+
+	newStartTime = normalizeStartTime(newStartTime)
+	newEndTime := newStartTime.Add(appointment.Duration())
+
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, appointment.DoctorID, clinicID)
+	if err != nil {
+		return apperrors.NotFound("Doctor")
+	}
+	return s.reserve(ctx, clinicID, doctor, newStartTime, newEndTime, &appointment.ID, func() error {
+		appointment.StartTime = newStartTime
+		appointment.EndTime = newEndTime
+		appointment.Date = s.clock.DateOf(ctx, clinicID, newStartTime)
+
+		if err := s.appointmentRepo.Update(ctx, appointment); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return apperrors.AppointmentConflict()
+			}
+			return transitionError(err, "Failed to reschedule appointment")
+		}
+		return nil
+	})
+}
+
+// BookWaitlistOffer books the slot offered to a waitlist entry and closes the entry
+func (s *AppointmentService) BookWaitlistOffer(ctx context.Context, entryID, clinicID, creatorID primitive.ObjectID) (*models.Appointment, error) {
+	entry, err := s.waitlist.GetOffer(ctx, entryID, clinicID)
+	if err != nil {
+		return nil, err
+	}
+	offer := entry.Offer
+
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, offer.DoctorID, clinicID)
+	if err != nil || doctor.Role != models.RoleDoctor {
+		return nil, apperrors.NotFound("Doctor")
+	}
+
+	appointment := &models.Appointment{
+		ClinicID:  clinicID,
+		PatientID: entry.PatientID,
+		DoctorID:  offer.DoctorID,
+		Date:      s.clock.DateOf(ctx, clinicID, offer.StartTime),
+		StartTime: offer.StartTime,
+		EndTime:   offer.EndTime,
+		Notes:     entry.Notes,
+		CreatedBy: creatorID,
+	}
+	err = s.locker.Do(ctx, doctorLock(doctor.ID), func() error {
+		if err := s.checkSlot(ctx, clinicID, doctor, offer.StartTime, offer.EndTime, nil); err != nil {
+			if appErr, ok := err.(*apperrors.AppError); ok && appErr.Code != apperrors.CodeInternal {
+				s.waitlist.Release(ctx, entry)
+			}
+			return err
+		}
+		if err := s.appointmentRepo.Create(ctx, appointment); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				s.waitlist.Release(ctx, entry)
+				return apperrors.AppointmentConflict()
+			}
+			return apperrors.InternalWithErr("Failed to create appointment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.waitlist.MarkBooked(ctx, entry); err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
+
+// Cancel cancels an appointment
+func (s *AppointmentService) Cancel(ctx context.Context, id, clinicID, actorID primitive.ObjectID) error {
+	return s.UpdateStatus(ctx, id, clinicID, models.AppointmentStatusCancelled, actorID)
+}
+
+// GetAvailability returns free slots of the requested length for a doctor
+// between two clinic-local dates (inclusive). The slot length comes from the
+// planned services if given, otherwise from minutes, otherwise SlotDuration.
+// Doctors without a configured schedule have no bookable slots.
+func (s *AppointmentService) GetAvailability(ctx context.Context, clinicID, doctorID primitive.ObjectID, from, to string, serviceIDs []string, minutes int) (*models.DoctorAvailabilityResponse, error) {
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID)
+	if err != nil || doctor.Role != models.RoleDoctor {
+		return nil, apperrors.NotFound("Doctor")
+	}
+
+	duration := models.SlotDuration
+	if len(serviceIDs) > 0 {
+		_, duration, err = s.plannedDuration(ctx, clinicID, serviceIDs)
+		if err != nil {
+			return nil, err
+		}
+	} else if minutes > 0 {
+		duration = time.Duration(minutes) * time.Minute
+	}
+
+	loc := s.clock.Location(ctx, clinicID)
+	fromDay, err := time.ParseInLocation("2006-01-02", from, loc)
+	if err != nil {
+		return nil, apperrors.BadRequest("Invalid from date, expected YYYY-MM-DD")
+	}
+	toDay, err := time.ParseInLocation("2006-01-02", to, loc)
+	if err != nil {
+		return nil, apperrors.BadRequest("Invalid to date, expected YYYY-MM-DD")
+	}
+	if toDay.Before(fromDay) {
+		return nil, apperrors.BadRequest("to must not be before from")
+	}
+	if toDay.Sub(fromDay) > maxAvailabilityDays*24*time.Hour {
+		return nil, apperrors.BadRequest("Date range is too long")
+	}
+	rangeEnd := toDay.AddDate(0, 0, 1)
+
+	appointments, err := s.appointmentRepo.ListByInterval(ctx, clinicID, &doctorID, fromDay, rangeEnd)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list appointments", err)
+	}
+
+	blocked, err := s.timeOffRepo.ListBlocking(ctx, clinicID, doctorID, fromDay, rangeEnd)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list closures and leaves", err)
+	}
+
+	now := time.Now().UTC()
+	resp := &models.DoctorAvailabilityResponse{
+		DoctorID: doctorID.Hex(),
+		From:     from,
+		To:       to,
+		Duration: int(duration / time.Minute),
+		Days:     []models.DayAvailability{},
+	}
+
+	for day := fromDay; day.Before(rangeEnd); day = day.AddDate(0, 0, 1) {
+		free := []models.Interval{}
+		if doctor.WorkSchedule != nil {
+			free = doctor.WorkSchedule.WorkingIntervals(day, loc)
+		}
+		for _, a := range appointments {
+			free = models.SubtractInterval(free, models.Interval{Start: a.StartTime, End: a.EndTime})
+		}
+		for _, b := range blocked {
+			free = models.SubtractInterval(free, models.Interval{Start: b.StartTime, End: b.EndTime})
+		}
+		free = models.SubtractInterval(free, models.Interval{Start: day, End: now})
+
+		resp.Days = append(resp.Days, models.DayAvailability{
+			Date:  day.Format("2006-01-02"),
+			Slots: splitIntoSlots(free, duration, loc),
+		})
+	}
+
+	return resp, nil
+}
 
-	// Normalize to slot boundary
-	newStartTime = normalizeToSlot(newStartTime)
+// reserve checks a slot and runs write while holding the doctor's booking
+// lock, so two overlapping bookings with different start times cannot both
+// pass the overlap check. Every write that places an appointment on the
+// calendar goes through it.
+func (s *AppointmentService) reserve(ctx context.Context, clinicID primitive.ObjectID, doctor *models.User, start, end time.Time, excludeID *primitive.ObjectID, write func() error) error {
+	return s.locker.Do(ctx, doctorLock(doctor.ID), func() error {
+		if err := s.checkSlot(ctx, clinicID, doctor, start, end, excludeID); err != nil {
+			return err
+		}
+		return write()
+	})
+}
 
-	// Check for conflicts at the new time
-	hasConflict, err := s.appointmentRepo.CheckOverlap(ctx, clinicID, appointment.DoctorID, newStartTime, &appointment.ID)
+// doctorLock names the lock serializing bookings of one doctor
+func doctorLock(doctorID primitive.ObjectID) string {
+	return "appointments:" + doctorID.Hex()
+}
+
+// checkSlot applies all booking rules to [start, end) for a doctor: working
+// hours, closures and leave, and overlap with the doctor's other appointments
+func (s *AppointmentService) checkSlot(ctx context.Context, clinicID primitive.ObjectID, doctor *models.User, start, end time.Time, excludeID *primitive.ObjectID) error {
+	if err := s.checkWorkingHours(ctx, clinicID, doctor, start, end); err != nil {
+		return err
+	}
+	if err := s.checkTimeOff(ctx, clinicID, doctor.ID, start, end); err != nil {
+		return err
+	}
+
+	// Check the whole interval against the doctor's other appointments
+	hasConflict, err := s.appointmentRepo.CheckOverlap(ctx, clinicID, doctor.ID, start, end, excludeID)
 	if err != nil {
 		return apperrors.InternalWithErr("Failed to check for conflicts", err)
 	}
 	if hasConflict {
 		return apperrors.AppointmentConflict()
 	}
+	return nil
+}
 
-	// Update the appointment
-	appointment.StartTime = newStartTime
-	appointment.EndTime = newStartTime.Add(models.SlotDuration)
-	appointment.Date = newStartTime.UTC().Format("2006-01-02")
+// checkWorkingHours rejects bookings outside the doctor's weekly schedule.
+// Doctors without a configured schedule are not restricted.
+func (s *AppointmentService) checkWorkingHours(ctx context.Context, clinicID primitive.ObjectID, doctor *models.User, start, end time.Time) error {
+	if doctor.WorkSchedule == nil {
+		return nil
+	}
 
-	return s.appointmentRepo.Update(ctx, appointment)
+	loc := s.clock.Location(ctx, clinicID)
+	working := doctor.WorkSchedule.WorkingIntervals(start, loc)
+	if !models.ContainsInterval(working, models.Interval{Start: start, End: end}) {
+		return apperrors.OutsideWorkingHours()
+	}
+	return nil
 }
 
-// Cancel cancels an appointment
-func (s *AppointmentService) Cancel(ctx context.Context, id, clinicID primitive.ObjectID) error {
-	return s.UpdateStatus(ctx, id, clinicID, models.AppointmentStatusCancelled)
+// checkTimeOff rejects bookings that fall into a clinic closure or the doctor's leave
+func (s *AppointmentService) checkTimeOff(ctx context.Context, clinicID, doctorID primitive.ObjectID, start, end time.Time) error {
+	blocked, err := s.timeOffRepo.ListBlocking(ctx, clinicID, doctorID, start, end)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to check closures and leaves", err)
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+
+	if blocked[0].Type == models.TimeOffTypeClosure {
+		return apperrors.TimeOff("Clinic is closed at this time")
+	}
+	return apperrors.TimeOff("Doctor is on leave at this time")
 }
 
-// normalizeToSlot rounds time down to nearest 30-minute boundary
-func normalizeToSlot(t time.Time) time.Time {
-	t = t.UTC()
-	minute := t.Minute()
-	if minute >= 30 {
-		minute = 30
-	} else {
-		minute = 0
+// splitIntoSlots cuts free intervals into bookable slots of the given length,
+// starting on availabilityStep boundaries of the clinic-local clock
+func splitIntoSlots(free []models.Interval, duration time.Duration, loc *time.Location) []models.Interval {
+	slots := []models.Interval{}
+	for _, iv := range free {
+		start := alignToStep(iv.Start, loc)
+		for !start.Add(duration).After(iv.End) {
+			slots = append(slots, models.Interval{Start: start.UTC(), End: start.Add(duration).UTC()})
+			start = start.Add(availabilityStep)
+		}
 	}
-	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), minute, 0, 0, time.UTC)
+	return slots
+}
+
+// alignToStep rounds t up to the next availabilityStep boundary of the local clock
+func alignToStep(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	offset := local.Sub(midnight)
+	if rem := offset % availabilityStep; rem != 0 {
+		offset += availabilityStep - rem
+	}
+	return midnight.Add(offset)
+}
+
+// plannedDuration resolves the planned services and sums their durations.
+// Without services the appointment falls back to models.SlotDuration.
+func (s *AppointmentService) plannedDuration(ctx context.Context, clinicID primitive.ObjectID, ids []string) ([]primitive.ObjectID, time.Duration, error) {
+	if len(ids) == 0 {
+		return nil, models.SlotDuration, nil
+	}
+
+	serviceIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		serviceID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, 0, apperrors.BadRequest("Invalid service ID: " + id)
+		}
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+
+	services, err := s.serviceRepo.GetMultipleByIDs(ctx, serviceIDs, clinicID)
+	if err != nil {
+		return nil, 0, apperrors.InternalWithErr("Failed to fetch services", err)
+	}
+
+	durations := make(map[primitive.ObjectID]int, len(services))
+	for _, svc := range services {
+		durations[svc.ID] = svc.Duration
+	}
+
+	// The same service may be planned more than once (e.g. two fillings)
+	total := time.Duration(0)
+	for _, id := range serviceIDs {
+		minutes, ok := durations[id]
+		if !ok {
+			return nil, 0, apperrors.BadRequest("One or more services not found")
+		}
+		total += time.Duration(minutes) * time.Minute
+	}
+	if total <= 0 {
+		total = models.SlotDuration
+	}
+
+	return serviceIDs, total, nil
+}
+
+// normalizeStartTime drops seconds and sub-second precision
+func normalizeStartTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Minute)
 }
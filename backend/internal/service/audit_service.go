@@ -9,11 +9,12 @@ import (
 )
 
 type AuditService struct {
-	repo *repository.AuditLogRepository
+	repo  *repository.AuditLogRepository
+	clock *ClinicClock
 }
 
-func NewAuditService(repo *repository.AuditLogRepository) *AuditService {
-	return &AuditService{repo: repo}
+func NewAuditService(repo *repository.AuditLogRepository, clock *ClinicClock) *AuditService {
+	return &AuditService{repo: repo, clock: clock}
 }
 
 // LogAsync logs an audit event asynchronously (fire and forget)
@@ -36,7 +37,7 @@ func (s *AuditService) LogAsync(
 	s.repo.CreateAsync(log)
 }
 
-// Query retrieves audit logs with filters
+// Query retrieves audit logs with filters; dates are clinic-local days
 func (s *AuditService) Query(
 	ctx context.Context,
 	clinicID primitive.ObjectID,
@@ -44,5 +45,5 @@ func (s *AuditService) Query(
 	startDate, endDate string,
 	limit int64,
 ) ([]models.AuditLog, error) {
-	return s.repo.FindByClinicAndFilters(ctx, clinicID, doctorID, startDate, endDate, limit)
+	return s.repo.FindByClinicAndFilters(ctx, clinicID, doctorID, startDate, endDate, s.clock.Location(ctx, clinicID), limit)
 }
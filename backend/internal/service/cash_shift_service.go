@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CashShiftService opens and closes cashiers' register shifts and reconciles
+// the counted cash against cash payments taken during the shift
+type CashShiftService struct {
+	shiftRepo   *repository.CashShiftRepository
+	paymentRepo *repository.PaymentRepository
+	patientRepo *repository.PatientRepository
+	userRepo    *repository.UserRepository
+	clock       *ClinicClock
+	locker      *Locker
+}
+
+func NewCashShiftService(
+	shiftRepo *repository.CashShiftRepository,
+	paymentRepo *repository.PaymentRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	clock *ClinicClock,
+	locker *Locker,
+) *CashShiftService {
+	return &CashShiftService{
+		shiftRepo:   shiftRepo,
+		paymentRepo: paymentRepo,
+		patientRepo: patientRepo,
+		userRepo:    userRepo,
+		clock:       clock,
+		locker:      locker,
+	}
+}
+
+// Open starts a shift for the cashier with the cash already in the drawer
+func (s *CashShiftService) Open(ctx context.Context, clinicID, cashierID primitive.ObjectID, dto models.OpenCashShiftDTO) (*models.CashShift, error) {
+	if _, err := s.shiftRepo.GetOpen(ctx, clinicID, cashierID); err == nil {
+		return nil, apperrors.Conflict("You already have an open shift")
+	}
+
+	now := time.Now().UTC()
+	shift := &models.CashShift{
+		ClinicID:     clinicID,
+		CashierID:    cashierID,
+		Date:         s.clock.DateOf(ctx, clinicID, now),
+		OpenedAt:     now,
+		OpeningFloat: roundMoney(dto.OpeningFloat),
+		OpeningNotes: dto.Notes,
+	}
+	if err := s.shiftRepo.Create(ctx, shift); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, apperrors.Conflict("You already have an open shift")
+		}
+		return nil, apperrors.InternalWithErr("Failed to open shift", err)
+	}
+	return shift, nil
+}
+
+// Current returns the cashier's open shift with live totals and its cash payments
+func (s *CashShiftService) Current(ctx context.Context, clinicID, cashierID primitive.ObjectID) (*models.CashShiftResponse, error) {
+	shift, err := s.shiftRepo.GetOpen(ctx, clinicID, cashierID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperrors.NotFound("Open shift")
+		}
+		return nil, apperrors.InternalWithErr("Failed to get shift", err)
+	}
+	return s.detail(ctx, shift)
+}
+
+// Get returns a shift with its cash payments. Receptionists only see their own shifts.
+func (s *CashShiftService) Get(ctx context.Context, id, clinicID, userID primitive.ObjectID, role string) (*models.CashShiftResponse, error) {
+	shift, err := s.shiftRepo.GetByID(ctx, id, clinicID)
+	if err != nil || (role != models.RoleBoss && shift.CashierID != userID) {
+		return nil, apperrors.NotFound("Shift")
+	}
+	return s.detail(ctx, shift)
+}
+
+// Close reconciles the counted cash and closes the shift. Cashiers close
+// their own shifts; the boss can close any, e.g. one left open overnight.
+func (s *CashShiftService) Close(ctx context.Context, id, clinicID, userID primitive.ObjectID, role string, dto models.CloseCashShiftDTO) (*models.CashShift, error) {
+	shift, err := s.shiftRepo.GetByID(ctx, id, clinicID)
+	if err != nil || (role != models.RoleBoss && shift.CashierID != userID) {
+		return nil, apperrors.NotFound("Shift")
+	}
+	if shift.Status != models.CashShiftStatusOpen {
+		return nil, apperrors.BadRequest("Shift is already closed")
+	}
+
+	// Payments and refunds into the shift hold its lock too, so none lands
+	// between totalling the payments and closing
+	err = s.locker.Do(ctx, shiftLock(shift.ID), func() error {
+		payments, err := s.paymentRepo.ListByShift(ctx, clinicID, shift.ID)
+		if err != nil {
+			return apperrors.InternalWithErr("Failed to get shift payments", err)
+		}
+
+		now := time.Now().UTC()
+		shift.CashIn, shift.CashOut = cashTotals(payments)
+		shift.ExpectedCash = roundMoney(shift.OpeningFloat + shift.CashIn - shift.CashOut)
+		shift.CountedCash = roundMoney(*dto.CountedCash)
+		shift.Discrepancy = roundMoney(shift.CountedCash - shift.ExpectedCash)
+		shift.ClosingNotes = dto.Notes
+		shift.ClosedAt = &now
+
+		ok, err := s.shiftRepo.Close(ctx, shift)
+		if err != nil {
+			return apperrors.InternalWithErr("Failed to close shift", err)
+		}
+		if !ok {
+			return apperrors.Conflict("Shift was closed concurrently")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	shift.Status = models.CashShiftStatusClosed
+	return shift, nil
+}
+
+// shiftLock names the lock held while money goes into or out of a shift and
+// while it is closed
+func shiftLock(id primitive.ObjectID) string {
+	return "cash_shift:" + id.Hex()
+}
+
+// intoShift runs fn, which takes money into or out of shift, holding the
+// shift's lock and only while the shift is still open. With no shift fn
+// just runs.
+func intoShift(ctx context.Context, locker *Locker, shiftRepo *repository.CashShiftRepository, shift *models.CashShift, fn func() error) error {
+	if shift == nil {
+		return fn()
+	}
+	return locker.Do(ctx, shiftLock(shift.ID), func() error {
+		current, err := shiftRepo.GetByID(ctx, shift.ID, shift.ClinicID)
+		if err != nil {
+			return apperrors.InternalWithErr("Failed to get cash shift", err)
+		}
+		if current.Status != models.CashShiftStatusOpen {
+			return apperrors.Conflict("Cash shift was closed meanwhile, please try again")
+		}
+		return fn()
+	})
+}
+
+// List returns shifts opened in a clinic-local date range, optionally of one
+// cashier. Open shifts carry live totals.
+func (s *CashShiftService) List(ctx context.Context, clinicID primitive.ObjectID, cashierID *primitive.ObjectID, fromDate, toDate string) ([]models.CashShiftResponse, error) {
+	if fromDate == "" || toDate == "" {
+		today := s.clock.Today(ctx, clinicID)
+		if fromDate == "" {
+			fromDate = today
+		}
+		if toDate == "" {
+			toDate = today
+		}
+	}
+	for _, d := range []string{fromDate, toDate} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, apperrors.BadRequest("Invalid date format, expected YYYY-MM-DD")
+		}
+	}
+
+	shifts, err := s.shiftRepo.List(ctx, clinicID, cashierID, fromDate, toDate)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list shifts", err)
+	}
+
+	names := make(map[primitive.ObjectID]string)
+	responses := make([]models.CashShiftResponse, 0, len(shifts))
+	for i := range shifts {
+		shift := &shifts[i]
+		if err := s.liveTotals(ctx, shift); err != nil {
+			return nil, err
+		}
+		resp := shift.ToResponse()
+		resp.CashierName = s.userName(ctx, clinicID, shift.CashierID, names)
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// Summaries returns the shifts opened on a clinic-local date for the daily report
+func (s *CashShiftService) Summaries(ctx context.Context, clinicID primitive.ObjectID, date string) ([]models.CashShiftSummary, float64, error) {
+	shifts, err := s.List(ctx, clinicID, nil, date, date)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	summaries := make([]models.CashShiftSummary, 0, len(shifts))
+	discrepancy := 0.0
+	for _, sh := range shifts {
+		summaries = append(summaries, models.CashShiftSummary{
+			ID:           sh.ID,
+			CashierID:    sh.CashierID,
+			CashierName:  sh.CashierName,
+			Status:       sh.Status,
+			ExpectedCash: sh.ExpectedCash,
+			CountedCash:  sh.CountedCash,
+			Discrepancy:  sh.Discrepancy,
+		})
+		if sh.Discrepancy != nil {
+			discrepancy += *sh.Discrepancy
+		}
+	}
+	return summaries, roundMoney(discrepancy), nil
+}
+
+// detail builds the response of one shift with its cash payments
+func (s *CashShiftService) detail(ctx context.Context, shift *models.CashShift) (*models.CashShiftResponse, error) {
+	payments, err := s.paymentRepo.ListByShift(ctx, shift.ClinicID, shift.ID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to get shift payments", err)
+	}
+	if shift.Status == models.CashShiftStatusOpen {
+		shift.CashIn, shift.CashOut = cashTotals(payments)
+		shift.ExpectedCash = roundMoney(shift.OpeningFloat + shift.CashIn - shift.CashOut)
+	}
+
+	names := make(map[primitive.ObjectID]string)
+	resp := shift.ToResponse()
+	resp.CashierName = s.userName(ctx, shift.ClinicID, shift.CashierID, names)
+	resp.Payments = []models.PaymentResponse{}
+	for _, p := range payments {
+		if cashAmount(&p) == 0 {
+			continue
+		}
+		pr := p.ToResponse()
+		pr.CashierName = resp.CashierName
+		if patient, err := s.patientRepo.GetByID(ctx, p.PatientID, shift.ClinicID); err == nil {
+			pr.PatientName = patient.FirstName + " " + patient.LastName
+		}
+		resp.Payments = append(resp.Payments, pr)
+	}
+	return &resp, nil
+}
+
+// liveTotals fills the cash totals of an open shift from its payments
+func (s *CashShiftService) liveTotals(ctx context.Context, shift *models.CashShift) error {
+	if shift.Status != models.CashShiftStatusOpen {
+		return nil
+	}
+	payments, err := s.paymentRepo.ListByShift(ctx, shift.ClinicID, shift.ID)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to get shift payments", err)
+	}
+	shift.CashIn, shift.CashOut = cashTotals(payments)
+	shift.ExpectedCash = roundMoney(shift.OpeningFloat + shift.CashIn - shift.CashOut)
+	return nil
+}
+
+func (s *CashShiftService) userName(ctx context.Context, clinicID, userID primitive.ObjectID, cache map[primitive.ObjectID]string) string {
+	if name, ok := cache[userID]; ok {
+		return name
+	}
+	name := ""
+	if user, err := s.userRepo.GetByIDWithClinicCheck(ctx, userID, clinicID); err == nil {
+		name = user.FirstName + " " + user.LastName
+	}
+	cache[userID] = name
+	return name
+}
+
+// cashTotals sums the cash taken and the cash refunded over payments
+func cashTotals(payments []models.Payment) (float64, float64) {
+	in, out := 0.0, 0.0
+	for i := range payments {
+		amount := cashAmount(&payments[i])
+		if amount > 0 {
+			in += amount
+		} else {
+			out -= amount
+		}
+	}
+	return roundMoney(in), roundMoney(out)
+}
+
+// cashAmount returns the cash part of a payment, negative for refunds
+func cashAmount(p *models.Payment) float64 {
+	amount := 0.0
+	for _, part := range p.MethodAmounts() {
+		if part.Method == models.PaymentTypeCash {
+			amount += part.Amount
+		}
+	}
+	return amount
+}
@@ -33,8 +33,18 @@ func (s *ClinicService) Create(ctx context.Context, dto models.CreateClinicDTO)
 		return nil, apperrors.Conflict("Clinic with this name already exists")
 	}
 
+	if _, err := time.LoadLocation(dto.Timezone); err != nil {
+		return nil, apperrors.Validation("Invalid timezone")
+	}
+
+	slug, err := s.resolveSlug(ctx, dto.Slug, dto.Name, primitive.NilObjectID)
+	if err != nil {
+		return nil, err
+	}
+
 	clinic := &models.Clinic{
 		Name:     dto.Name,
+		Slug:     slug,
 		Timezone: dto.Timezone,
 		Address:  dto.Address,
 		Phone:    dto.Phone,
@@ -120,7 +130,17 @@ func (s *ClinicService) Update(ctx context.Context, id primitive.ObjectID, dto m
 		}
 		clinic.Name = *dto.Name
 	}
+	if dto.Slug != nil {
+		slug, err := s.resolveSlug(ctx, *dto.Slug, clinic.Name, id)
+		if err != nil {
+			return nil, err
+		}
+		clinic.Slug = slug
+	}
 	if dto.Timezone != nil {
+		if _, err := time.LoadLocation(*dto.Timezone); err != nil {
+			return nil, apperrors.Validation("Invalid timezone")
+		}
 		clinic.Timezone = *dto.Timezone
 	}
 	if dto.Address != nil {
@@ -140,6 +160,30 @@ func (s *ClinicService) Update(ctx context.Context, id primitive.ObjectID, dto m
 	return clinic, nil
 }
 
+// resolveSlug validates a requested slug, or derives one from the name when
+// none is given, and makes sure no other clinic uses it
+func (s *ClinicService) resolveSlug(ctx context.Context, requested, name string, clinicID primitive.ObjectID) (string, error) {
+	slug := requested
+	if slug == "" {
+		slug = models.Slugify(name)
+		if len(slug) < 3 {
+			suffix, err := generateSecureToken(3)
+			if err != nil {
+				return "", apperrors.InternalWithErr("Failed to generate slug", err)
+			}
+			slug = "clinic-" + suffix
+		}
+	} else if !models.ValidSlug(slug) {
+		return "", apperrors.Validation("Slug may only contain lowercase letters, digits and single hyphens")
+	}
+
+	existing, _ := s.clinicRepo.GetBySlug(ctx, slug)
+	if existing != nil && existing.ID != clinicID {
+		return "", apperrors.Conflict("Clinic with this slug already exists")
+	}
+	return slug, nil
+}
+
 // Delete deletes a clinic (superadmin only)
 func (s *ClinicService) Delete(ctx context.Context, id primitive.ObjectID) error {
 	_, err := s.clinicRepo.GetByID(ctx, id)
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClinicClock resolves clinic-local time. All day bucketing (appointment and
+// visit dates, reports, "today" in views) goes through it so that a visit
+// late in the evening lands on the clinic's calendar day, not the UTC one.
+type ClinicClock struct {
+	clinicRepo *repository.ClinicRepository
+}
+
+func NewClinicClock(clinicRepo *repository.ClinicRepository) *ClinicClock {
+	return &ClinicClock{clinicRepo: clinicRepo}
+}
+
+// Location returns the clinic's configured time zone, falling back to UTC
+func (c *ClinicClock) Location(ctx context.Context, clinicID primitive.ObjectID) *time.Location {
+	clinic, err := c.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return time.UTC
+	}
+	return clinic.Location()
+}
+
+// DateOf returns the clinic-local calendar date (YYYY-MM-DD) of t
+func (c *ClinicClock) DateOf(ctx context.Context, clinicID primitive.ObjectID, t time.Time) string {
+	return models.LocalDate(t, c.Location(ctx, clinicID))
+}
+
+// Today returns the current clinic-local date (YYYY-MM-DD)
+func (c *ClinicClock) Today(ctx context.Context, clinicID primitive.ObjectID) string {
+	return c.DateOf(ctx, clinicID, time.Now())
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,16 +17,18 @@ import (
 )
 
 type DoctorContractService struct {
-	repo     *repository.DoctorContractRepository
-	userRepo *repository.UserRepository
-	log      *logger.Logger
+	repo        *repository.DoctorContractRepository
+	userRepo    *repository.UserRepository
+	serviceRepo *repository.ServiceRepository
+	log         *logger.Logger
 }
 
-func NewDoctorContractService(repo *repository.DoctorContractRepository, userRepo *repository.UserRepository, log *logger.Logger) *DoctorContractService {
+func NewDoctorContractService(repo *repository.DoctorContractRepository, userRepo *repository.UserRepository, serviceRepo *repository.ServiceRepository, log *logger.Logger) *DoctorContractService {
 	return &DoctorContractService{
-		repo:     repo,
-		userRepo: userRepo,
-		log:      log,
+		repo:        repo,
+		userRepo:    userRepo,
+		serviceRepo: serviceRepo,
+		log:         log,
 	}
 }
 
@@ -46,10 +51,17 @@ func (s *DoctorContractService) Create(ctx context.Context, clinicID, createdBy
 		return nil, apperrors.BadRequest("user is not a doctor")
 	}
 
+	rules, err := s.buildRules(ctx, clinicID, dto.Rules)
+	if err != nil {
+		return nil, err
+	}
+
 	contract := &models.DoctorContract{
 		ClinicID:        clinicID,
 		DoctorID:        doctorID,
 		SharePercentage: dto.SharePercentage,
+		Rules:           rules,
+		DeductCosts:     dto.DeductCosts,
 		StartDate:       dto.StartDate,
 		EndDate:         dto.EndDate,
 		IsActive:        true,
@@ -112,6 +124,16 @@ func (s *DoctorContractService) Update(ctx context.Context, id, clinicID primiti
 	if dto.SharePercentage != nil {
 		contract.SharePercentage = *dto.SharePercentage
 	}
+	if dto.Rules != nil {
+		rules, err := s.buildRules(ctx, clinicID, dto.Rules)
+		if err != nil {
+			return nil, err
+		}
+		contract.Rules = rules
+	}
+	if dto.DeductCosts != nil {
+		contract.DeductCosts = *dto.DeductCosts
+	}
 	if dto.EndDate != nil {
 		contract.EndDate = *dto.EndDate
 	}
@@ -148,3 +170,67 @@ func (s *DoctorContractService) Delete(ctx context.Context, id, clinicID primiti
 	s.log.Infof("doctor contract deleted: contract_id=%s", id.Hex())
 	return nil
 }
+
+// buildRules validates commission rules and resolves their services
+func (s *DoctorContractService) buildRules(ctx context.Context, clinicID primitive.ObjectID, dtos []models.CommissionRuleDTO) ([]models.CommissionRule, error) {
+	rules := make([]models.CommissionRule, 0, len(dtos))
+	for i, dto := range dtos {
+		label := strings.TrimSpace(dto.Name)
+		if label == "" {
+			label = fmt.Sprintf("rule %d", i+1)
+		}
+
+		if (dto.SharePercentage == nil) == (dto.FixedAmount == nil) {
+			return nil, apperrors.BadRequest(label + ": exactly one of share_percentage and fixed_amount is required")
+		}
+		if len(dto.Tiers) > 0 && dto.SharePercentage == nil {
+			return nil, apperrors.BadRequest(label + ": tiers require share_percentage")
+		}
+
+		rule := models.CommissionRule{
+			Name:            strings.TrimSpace(dto.Name),
+			Category:        strings.TrimSpace(dto.Category),
+			SharePercentage: dto.SharePercentage,
+			FixedAmount:     dto.FixedAmount,
+			Tiers:           append([]models.CommissionTier(nil), dto.Tiers...),
+		}
+		sort.Slice(rule.Tiers, func(a, b int) bool { return rule.Tiers[a].MinMonthlyRevenue < rule.Tiers[b].MinMonthlyRevenue })
+		for t := 1; t < len(rule.Tiers); t++ {
+			if rule.Tiers[t].MinMonthlyRevenue == rule.Tiers[t-1].MinMonthlyRevenue {
+				return nil, apperrors.BadRequest(label + ": tier thresholds must be distinct")
+			}
+		}
+
+		for _, hex := range dto.ServiceIDs {
+			id, err := primitive.ObjectIDFromHex(hex)
+			if err != nil {
+				return nil, apperrors.BadRequest(label + ": invalid service id " + hex)
+			}
+			if !containsObjectID(rule.ServiceIDs, id) {
+				rule.ServiceIDs = append(rule.ServiceIDs, id)
+			}
+		}
+		if len(rule.ServiceIDs) > 0 {
+			services, err := s.serviceRepo.GetMultipleByIDs(ctx, rule.ServiceIDs, clinicID)
+			if err != nil {
+				s.log.Error("failed to find services", err)
+				return nil, apperrors.Internal("failed to verify services")
+			}
+			if len(services) != len(rule.ServiceIDs) {
+				return nil, apperrors.BadRequest(label + ": one or more services not found")
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
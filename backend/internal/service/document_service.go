@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+	"medical-crm/pkg/pdf"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Page layout of printed documents, in points
+const (
+	docLeft   = 50.0
+	docRight  = pdf.PageWidth - 50
+	docBottom = pdf.PageHeight - 60
+)
+
+// DocumentService renders printable invoices and receipts as PDF
+type DocumentService struct {
+	visitRepo   *repository.VisitRepository
+	paymentRepo *repository.PaymentRepository
+	clinicRepo  *repository.ClinicRepository
+	patientRepo *repository.PatientRepository
+	userRepo    *repository.UserRepository
+	counterRepo *repository.CounterRepository
+}
+
+func NewDocumentService(
+	visitRepo *repository.VisitRepository,
+	paymentRepo *repository.PaymentRepository,
+	clinicRepo *repository.ClinicRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	counterRepo *repository.CounterRepository,
+) *DocumentService {
+	return &DocumentService{
+		visitRepo:   visitRepo,
+		paymentRepo: paymentRepo,
+		clinicRepo:  clinicRepo,
+		patientRepo: patientRepo,
+		userRepo:    userRepo,
+		counterRepo: counterRepo,
+	}
+}
+
+// InvoicePDF renders the invoice of a completed visit and returns it with its file name
+func (s *DocumentService) InvoicePDF(ctx context.Context, visitID, clinicID primitive.ObjectID) ([]byte, string, error) {
+	visit, err := s.visitRepo.GetByID(ctx, visitID, clinicID)
+	if err != nil {
+		return nil, "", apperrors.NotFound("Visit")
+	}
+	if visit.Status != models.VisitStatusCompleted {
+		return nil, "", apperrors.BadRequest("Invoices are only available for completed visits")
+	}
+	if err := s.ensureInvoiceNumber(ctx, visit); err != nil {
+		return nil, "", err
+	}
+
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, "", apperrors.NotFound("Clinic")
+	}
+	number := models.FormatDocumentNumber(models.InvoicePrefix, visit.InvoiceNumber)
+
+	doc := pdf.New("Invoice " + number)
+	y := s.header(doc, clinic, "INVOICE", number, "Date: "+visit.Date)
+
+	y = s.parties(ctx, doc, y, clinicID, visit.PatientID, visit.DoctorID)
+
+	// Itemized services
+	cols := struct{ num, name, qty, price, amount float64 }{docLeft, docLeft + 25, docRight - 190, docRight - 95, docRight}
+	tableHeader := func(y float64) float64 {
+		doc.Text(cols.num, y, 9, true, "#")
+		doc.Text(cols.name, y, 9, true, "Service")
+		doc.TextRight(cols.qty, y, 9, true, "Qty")
+		doc.TextRight(cols.price, y, 9, true, "Price")
+		doc.TextRight(cols.amount, y, 9, true, "Amount")
+		doc.Line(docLeft, y+5, docRight, y+5)
+		return y + 20
+	}
+	y = tableHeader(y)
+	for i, line := range visit.Services {
+		if y > docBottom {
+			doc.AddPage()
+			y = tableHeader(60)
+		}
+		doc.Text(cols.num, y, 9, false, strconv.Itoa(i+1))
+		doc.Text(cols.name, y, 9, false, pdf.Truncate(line.ServiceName, cols.qty-cols.name-40, 9, false))
+		doc.TextRight(cols.qty, y, 9, false, strconv.Itoa(line.Quantity))
+		doc.TextRight(cols.price, y, 9, false, formatMoney(line.UnitPrice()))
+		doc.TextRight(cols.amount, y, 9, false, formatMoney(line.UnitPrice()*float64(line.Quantity)))
+		y += 16
+		if line.DiscountAmount > 0 {
+			doc.Text(cols.name, y-4, 8, false, "Discount")
+			doc.TextRight(cols.amount, y-4, 8, false, "-"+formatMoney(line.DiscountAmount))
+			y += 12
+		}
+	}
+	doc.Line(docLeft, y-8, docRight, y-8)
+
+	// Totals
+	if y+110 > docBottom {
+		doc.AddPage()
+		y = 60
+	}
+	y += 8
+	total := func(label, value string, bold bool) {
+		doc.TextRight(cols.price, y, 10, bold, label)
+		doc.TextRight(cols.amount, y, 10, bold, value)
+		y += 16
+	}
+	total("Subtotal", formatMoney(visit.Subtotal), false)
+	if visit.DiscountAmount > 0 {
+		label := "Discount"
+		if visit.DiscountType == "percentage" {
+			label = fmt.Sprintf("Discount (%s%%)", strconv.FormatFloat(visit.DiscountValue, 'f', -1, 64))
+		}
+		total(label, "-"+formatMoney(visit.DiscountAmount), false)
+	}
+	total("Total", formatMoney(visit.Total), true)
+	if visit.ReversedAmount > 0 {
+		total("Reversed", "-"+formatMoney(visit.ReversedAmount), false)
+		total("Net total", formatMoney(visit.NetTotal()), true)
+	}
+	if payer := visit.PayerShare(); payer > 0 {
+		total("Insurance", "-"+formatMoney(payer), false)
+	}
+	total("Paid", formatMoney(visit.PaidAmount), false)
+	total("Balance due", formatMoney(visit.Outstanding()), true)
+
+	return doc.Bytes(), number + ".pdf", nil
+}
+
+// ReceiptPDF renders the receipt of a payment and returns it with its file name
+func (s *DocumentService) ReceiptPDF(ctx context.Context, paymentID, clinicID primitive.ObjectID) ([]byte, string, error) {
+	payment, err := s.paymentRepo.GetByID(ctx, paymentID, clinicID)
+	if err != nil {
+		return nil, "", apperrors.NotFound("Payment")
+	}
+	if err := s.ensureReceiptNumber(ctx, payment); err != nil {
+		return nil, "", err
+	}
+
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, "", apperrors.NotFound("Clinic")
+	}
+	visit, err := s.visitRepo.GetByID(ctx, payment.VisitID, clinicID)
+	if err != nil {
+		return nil, "", apperrors.NotFound("Visit")
+	}
+	if err := s.ensureInvoiceNumber(ctx, visit); err != nil {
+		return nil, "", err
+	}
+
+	number := models.FormatDocumentNumber(models.ReceiptPrefix, payment.ReceiptNumber)
+	paidAt := payment.PaidAt.In(clinic.Location())
+
+	title, amountLabel, amount := "RECEIPT", "Amount received", payment.Amount
+	if payment.IsRefund() {
+		title, amountLabel, amount = "REFUND", "Amount refunded", -payment.Amount
+	}
+
+	doc := pdf.New("Receipt " + number)
+	y := s.header(doc, clinic, title, number, "Date: "+paidAt.Format("2006-01-02 15:04"))
+
+	y = s.parties(ctx, doc, y, clinicID, payment.PatientID, visit.DoctorID)
+
+	row := func(label, value string, bold bool) {
+		doc.Text(docLeft, y, 10, false, label)
+		doc.TextRight(docRight, y, 10, bold, value)
+		y += 18
+	}
+	row("Invoice", models.FormatDocumentNumber(models.InvoicePrefix, visit.InvoiceNumber)+" of "+visit.Date, false)
+	parts := payment.MethodAmounts()
+	if len(parts) == 1 {
+		row("Payment method", clinic.PaymentMethodName(payment.Method), false)
+	} else {
+		for _, part := range parts {
+			row(clinic.PaymentMethodName(part.Method), formatMoney(part.Amount), false)
+		}
+	}
+	if cashier, err := s.userRepo.GetByIDWithClinicCheck(ctx, payment.CashierID, clinicID); err == nil {
+		row("Processed by", cashier.FirstName+" "+cashier.LastName, false)
+	}
+	doc.Line(docLeft, y-8, docRight, y-8)
+	y += 6
+	row(amountLabel, formatMoney(amount), true)
+	row("Invoice total", formatMoney(visit.NetTotal()), false)
+	row("Balance due", formatMoney(visit.Outstanding()), false)
+	if payment.Notes != "" {
+		y += 6
+		doc.Text(docLeft, y, 9, false, pdf.Truncate("Note: "+payment.Notes, docRight-docLeft, 9, false))
+	}
+
+	return doc.Bytes(), number + ".pdf", nil
+}
+
+// header draws the clinic block and the document title, returning the next free y
+func (s *DocumentService) header(doc *pdf.Document, clinic *models.Clinic, title, number, date string) float64 {
+	doc.Text(docLeft, 70, 16, true, pdf.Truncate(clinic.Name, 300, 16, true))
+	y := 88.0
+	for _, line := range []string{clinic.Address, clinic.Phone} {
+		if line != "" {
+			doc.Text(docLeft, y, 9, false, pdf.Truncate(line, 300, 9, false))
+			y += 13
+		}
+	}
+
+	doc.TextRight(docRight, 70, 16, true, title)
+	doc.TextRight(docRight, 88, 10, false, "No. "+number)
+	doc.TextRight(docRight, 101, 10, false, date)
+
+	if y < 115 {
+		y = 115
+	}
+	doc.Line(docLeft, y, docRight, y)
+	return y + 25
+}
+
+// parties draws the patient and doctor block, returning the next free y
+func (s *DocumentService) parties(ctx context.Context, doc *pdf.Document, y float64, clinicID, patientID, doctorID primitive.ObjectID) float64 {
+	doc.Text(docLeft, y, 9, true, "Patient")
+	doc.Text(docLeft+260, y, 9, true, "Doctor")
+	y += 14
+	if patient, err := s.patientRepo.GetByID(ctx, patientID, clinicID); err == nil {
+		doc.Text(docLeft, y, 10, false, pdf.Truncate(patient.FirstName+" "+patient.LastName, 240, 10, false))
+		doc.Text(docLeft, y+13, 9, false, patient.Phone)
+	}
+	if doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID); err == nil {
+		doc.Text(docLeft+260, y, 10, false, pdf.Truncate(doctor.FirstName+" "+doctor.LastName, 230, 10, false))
+	}
+	return y + 45
+}
+
+// ensureInvoiceNumber numbers visits completed before invoice numbering existed
+func (s *DocumentService) ensureInvoiceNumber(ctx context.Context, visit *models.Visit) error {
+	if visit.InvoiceNumber > 0 {
+		return nil
+	}
+	number, err := s.counterRepo.Next(ctx, visit.ClinicID, models.CounterInvoice)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to number invoice", err)
+	}
+	if _, err := s.visitRepo.SetInvoiceNumber(ctx, visit.ID, visit.ClinicID, number); err != nil {
+		return apperrors.InternalWithErr("Failed to number invoice", err)
+	}
+	// Re-read: a concurrent request may have numbered it first
+	current, err := s.visitRepo.GetByID(ctx, visit.ID, visit.ClinicID)
+	if err != nil {
+		return apperrors.NotFound("Visit")
+	}
+	visit.InvoiceNumber = current.InvoiceNumber
+	return nil
+}
+
+// ensureReceiptNumber numbers payments recorded before receipt numbering existed
+func (s *DocumentService) ensureReceiptNumber(ctx context.Context, payment *models.Payment) error {
+	if payment.ReceiptNumber > 0 {
+		return nil
+	}
+	number, err := s.counterRepo.Next(ctx, payment.ClinicID, models.CounterReceipt)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to number receipt", err)
+	}
+	if _, err := s.paymentRepo.SetReceiptNumber(ctx, payment.ID, payment.ClinicID, number); err != nil {
+		return apperrors.InternalWithErr("Failed to number receipt", err)
+	}
+	current, err := s.paymentRepo.GetByID(ctx, payment.ID, payment.ClinicID)
+	if err != nil {
+		return apperrors.NotFound("Payment")
+	}
+	payment.ReceiptNumber = current.ReceiptNumber
+	return nil
+}
+
+// formatMoney prints an amount with thousands separators, e.g. 1 250 000.00
+func formatMoney(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String() + frac
+}
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InsuranceService manages insurance payers, patients' policies and the
+// claims for the payers' share of completed visits. Claims are split off at
+// completion (see VisitService) and billed to the payer in batches.
+type InsuranceService struct {
+	payerRepo   *repository.InsurancePayerRepository
+	batchRepo   *repository.ClaimBatchRepository
+	patientRepo *repository.PatientRepository
+	visitRepo   *repository.VisitRepository
+	counterRepo *repository.CounterRepository
+	clock       *ClinicClock
+}
+
+func NewInsuranceService(
+	payerRepo *repository.InsurancePayerRepository,
+	batchRepo *repository.ClaimBatchRepository,
+	patientRepo *repository.PatientRepository,
+	visitRepo *repository.VisitRepository,
+	counterRepo *repository.CounterRepository,
+	clock *ClinicClock,
+) *InsuranceService {
+	return &InsuranceService{
+		payerRepo:   payerRepo,
+		batchRepo:   batchRepo,
+		patientRepo: patientRepo,
+		visitRepo:   visitRepo,
+		counterRepo: counterRepo,
+		clock:       clock,
+	}
+}
+
+// CreatePayer adds an insurance company
+func (s *InsuranceService) CreatePayer(ctx context.Context, dto models.CreateInsurancePayerDTO, clinicID primitive.ObjectID) (*models.InsurancePayer, error) {
+	payer := &models.InsurancePayer{
+		ClinicID: clinicID,
+		Name:     strings.TrimSpace(dto.Name),
+		Code:     strings.TrimSpace(dto.Code),
+		Phone:    dto.Phone,
+		Email:    dto.Email,
+		Notes:    dto.Notes,
+	}
+	if err := s.payerRepo.Create(ctx, payer); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, apperrors.Conflict("Payer with this name already exists")
+		}
+		return nil, apperrors.InternalWithErr("Failed to create payer", err)
+	}
+	return payer, nil
+}
+
+// ListPayers returns the clinic's payers
+func (s *InsuranceService) ListPayers(ctx context.Context, clinicID primitive.ObjectID, activeOnly bool) ([]models.InsurancePayer, error) {
+	payers, err := s.payerRepo.List(ctx, clinicID, activeOnly)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list payers", err)
+	}
+	if payers == nil {
+		payers = []models.InsurancePayer{}
+	}
+	return payers, nil
+}
+
+// UpdatePayer updates a payer; deactivated payers take no new claims
+func (s *InsuranceService) UpdatePayer(ctx context.Context, id, clinicID primitive.ObjectID, dto models.UpdateInsurancePayerDTO) (*models.InsurancePayer, error) {
+	payer, err := s.payerRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Payer")
+	}
+
+	if dto.Name != nil {
+		payer.Name = strings.TrimSpace(*dto.Name)
+	}
+	if dto.Code != nil {
+		payer.Code = strings.TrimSpace(*dto.Code)
+	}
+	if dto.Phone != nil {
+		payer.Phone = *dto.Phone
+	}
+	if dto.Email != nil {
+		payer.Email = *dto.Email
+	}
+	if dto.Notes != nil {
+		payer.Notes = *dto.Notes
+	}
+	if dto.IsActive != nil {
+		payer.IsActive = *dto.IsActive
+	}
+
+	if err := s.payerRepo.Update(ctx, payer); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, apperrors.Conflict("Payer with this name already exists")
+		}
+		return nil, apperrors.InternalWithErr("Failed to update payer", err)
+	}
+	return payer, nil
+}
+
+// AddPolicy adds an insurance policy to a patient
+func (s *InsuranceService) AddPolicy(ctx context.Context, patientID, clinicID primitive.ObjectID, dto models.InsurancePolicyDTO) (*models.InsurancePolicy, error) {
+	patient, err := s.patientRepo.GetByID(ctx, patientID, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+
+	policy := models.InsurancePolicy{
+		ID:        primitive.NewObjectID(),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.fillPolicy(ctx, clinicID, &policy, dto); err != nil {
+		return nil, err
+	}
+
+	if err := s.patientRepo.AddPolicy(ctx, patient.ID, clinicID, &policy); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperrors.NotFound("Patient")
+		}
+		return nil, apperrors.InternalWithErr("Failed to save policy", err)
+	}
+	return &policy, nil
+}
+
+// UpdatePolicy replaces the terms of a patient's policy. Visits already
+// completed keep the split they were billed with.
+func (s *InsuranceService) UpdatePolicy(ctx context.Context, patientID, policyID, clinicID primitive.ObjectID, dto models.InsurancePolicyDTO) (*models.InsurancePolicy, error) {
+	patient, err := s.patientRepo.GetByID(ctx, patientID, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+
+	for i := range patient.Policies {
+		if patient.Policies[i].ID != policyID {
+			continue
+		}
+		policy := patient.Policies[i]
+		if err := s.fillPolicy(ctx, clinicID, &policy, dto); err != nil {
+			return nil, err
+		}
+		// Only this policy is written, so concurrent edits to the patient's
+		// other policies are kept
+		if err := s.patientRepo.UpdatePolicy(ctx, patientID, clinicID, &policy); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, apperrors.NotFound("Policy")
+			}
+			return nil, apperrors.InternalWithErr("Failed to save policy", err)
+		}
+		return &policy, nil
+	}
+	return nil, apperrors.NotFound("Policy")
+}
+
+// DeletePolicy removes a patient's policy
+func (s *InsuranceService) DeletePolicy(ctx context.Context, patientID, policyID, clinicID primitive.ObjectID) error {
+	if _, err := s.patientRepo.GetByID(ctx, patientID, clinicID); err != nil {
+		return apperrors.NotFound("Patient")
+	}
+
+	if err := s.patientRepo.DeletePolicy(ctx, patientID, clinicID, policyID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return apperrors.NotFound("Policy")
+		}
+		return apperrors.InternalWithErr("Failed to delete policy", err)
+	}
+	return nil
+}
+
+// fillPolicy validates the request and copies it onto the policy
+func (s *InsuranceService) fillPolicy(ctx context.Context, clinicID primitive.ObjectID, policy *models.InsurancePolicy, dto models.InsurancePolicyDTO) error {
+	payerID, err := primitive.ObjectIDFromHex(dto.PayerID)
+	if err != nil {
+		return apperrors.BadRequest("Invalid payer ID")
+	}
+	payer, err := s.payerRepo.GetByID(ctx, payerID, clinicID)
+	if err != nil {
+		return apperrors.NotFound("Payer")
+	}
+	if !payer.IsActive && payer.ID != policy.PayerID {
+		return apperrors.BadRequest("Payer is inactive")
+	}
+
+	if _, err := time.Parse("2006-01-02", dto.ValidFrom); err != nil {
+		return apperrors.BadRequest("Invalid valid_from, expected YYYY-MM-DD")
+	}
+	if dto.ValidTo != "" {
+		if _, err := time.Parse("2006-01-02", dto.ValidTo); err != nil {
+			return apperrors.BadRequest("Invalid valid_to, expected YYYY-MM-DD")
+		}
+		if dto.ValidTo < dto.ValidFrom {
+			return apperrors.BadRequest("valid_to must not be before valid_from")
+		}
+	}
+
+	policy.PayerID = payerID
+	policy.PolicyNumber = strings.TrimSpace(dto.PolicyNumber)
+	policy.CoveragePercent = dto.CoveragePercent
+	policy.MaxPerVisit = dto.MaxPerVisit
+	policy.AnnualLimit = dto.AnnualLimit
+	policy.ValidFrom = dto.ValidFrom
+	policy.ValidTo = dto.ValidTo
+	policy.IsActive = dto.IsActive == nil || *dto.IsActive
+	return nil
+}
+
+// ListClaims returns visit claims, optionally of one payer or status
+func (s *InsuranceService) ListClaims(ctx context.Context, clinicID primitive.ObjectID, payerID *primitive.ObjectID, status string) ([]models.ClaimResponse, error) {
+	var statuses []string
+	if status != "" {
+		statuses = append(statuses, status)
+	}
+	visits, err := s.visitRepo.ListClaims(ctx, clinicID, payerID, statuses...)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list claims", err)
+	}
+
+	names := make(map[primitive.ObjectID]string)
+	responses := make([]models.ClaimResponse, 0, len(visits))
+	for _, v := range visits {
+		resp := models.ClaimResponse{
+			VisitID:      v.ID.Hex(),
+			PatientID:    v.PatientID.Hex(),
+			PatientName:  s.patientName(ctx, clinicID, v.PatientID, names),
+			PayerID:      v.Claim.PayerID.Hex(),
+			PolicyNumber: v.Claim.PolicyNumber,
+			Date:         v.Date,
+			Total:        v.NetTotal(),
+			Amount:       roundMoney(v.PayerShare()),
+			Status:       v.Claim.Status,
+		}
+		if v.InvoiceNumber > 0 {
+			resp.InvoiceNumber = models.FormatDocumentNumber(models.InvoicePrefix, v.InvoiceNumber)
+		}
+		if v.Claim.BatchID != nil {
+			resp.BatchID = v.Claim.BatchID.Hex()
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// CreateBatch collects a payer's pending claims on visits up to a date into
+// a numbered batch for submission
+func (s *InsuranceService) CreateBatch(ctx context.Context, dto models.CreateClaimBatchDTO, clinicID, actorID primitive.ObjectID) (*models.ClaimBatch, error) {
+	payerID, err := primitive.ObjectIDFromHex(dto.PayerID)
+	if err != nil {
+		return nil, apperrors.BadRequest("Invalid payer ID")
+	}
+	if _, err := s.payerRepo.GetByID(ctx, payerID, clinicID); err != nil {
+		return nil, apperrors.NotFound("Payer")
+	}
+
+	toDate := dto.ToDate
+	if toDate == "" {
+		toDate = s.clock.Today(ctx, clinicID)
+	} else if _, err := time.Parse("2006-01-02", toDate); err != nil {
+		return nil, apperrors.BadRequest("Invalid to_date, expected YYYY-MM-DD")
+	}
+
+	// Tagging the visits first keeps concurrent batches from claiming a
+	// visit twice
+	batch := &models.ClaimBatch{
+		ID:        primitive.NewObjectID(),
+		ClinicID:  clinicID,
+		PayerID:   payerID,
+		ToDate:    toDate,
+		Status:    models.ClaimStatusPending,
+		Note:      dto.Note,
+		CreatedBy: actorID,
+	}
+	if _, err := s.visitRepo.AssignClaimBatch(ctx, clinicID, payerID, batch.ID, toDate); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to collect claims", err)
+	}
+	visits, err := s.visitRepo.ListByClaimBatch(ctx, clinicID, batch.ID)
+	if err != nil {
+		s.release(ctx, clinicID, batch.ID)
+		return nil, apperrors.InternalWithErr("Failed to collect claims", err)
+	}
+	if len(visits) == 0 {
+		return nil, apperrors.BadRequest("No pending claims for this payer up to " + toDate)
+	}
+
+	names := make(map[primitive.ObjectID]string)
+	for _, v := range visits {
+		line := models.ClaimLine{
+			VisitID:       v.ID,
+			PatientID:     v.PatientID,
+			PatientName:   s.patientName(ctx, clinicID, v.PatientID, names),
+			PolicyNumber:  v.Claim.PolicyNumber,
+			Date:          v.Date,
+			InvoiceNumber: v.InvoiceNumber,
+			Total:         roundMoney(v.NetTotal()),
+			Amount:        roundMoney(v.PayerShare()),
+		}
+		batch.Claims = append(batch.Claims, line)
+		batch.Amount += line.Amount
+	}
+	batch.Amount = roundMoney(batch.Amount)
+
+	number, err := s.counterRepo.Next(ctx, clinicID, models.CounterClaimBatch)
+	if err != nil {
+		s.release(ctx, clinicID, batch.ID)
+		return nil, apperrors.InternalWithErr("Failed to number claim batch", err)
+	}
+	batch.Number = number
+
+	if err := s.batchRepo.Create(ctx, batch); err != nil {
+		s.release(ctx, clinicID, batch.ID)
+		return nil, apperrors.InternalWithErr("Failed to create claim batch", err)
+	}
+	return batch, nil
+}
+
+// release returns a batch's claims to the pending pool after a failed write
+func (s *InsuranceService) release(ctx context.Context, clinicID, batchID primitive.ObjectID) {
+	_ = s.visitRepo.ReleaseClaimBatch(ctx, clinicID, batchID)
+}
+
+// ListBatches returns claim batches, optionally of one payer or status
+func (s *InsuranceService) ListBatches(ctx context.Context, clinicID primitive.ObjectID, payerID *primitive.ObjectID, status string) ([]models.ClaimBatchResponse, error) {
+	batches, err := s.batchRepo.List(ctx, clinicID, payerID, status)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list claim batches", err)
+	}
+
+	payers := s.payerNames(ctx, clinicID)
+	responses := make([]models.ClaimBatchResponse, 0, len(batches))
+	for _, b := range batches {
+		resp := b.ToResponse()
+		resp.PayerName = payers[b.PayerID]
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// GetBatch returns a batch with its claims
+func (s *InsuranceService) GetBatch(ctx context.Context, id, clinicID primitive.ObjectID) (*models.ClaimBatchResponse, error) {
+	batch, err := s.batchRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Claim batch")
+	}
+	resp := batch.ToResponse()
+	resp.Claims = batch.Claims
+	if payer, err := s.payerRepo.GetByID(ctx, batch.PayerID, clinicID); err == nil {
+		resp.PayerName = payer.Name
+	}
+	return &resp, nil
+}
+
+// UpdateBatchStatus submits a pending batch, or settles a submitted one as
+// paid or rejected. Rejected claims fall back to the patients.
+func (s *InsuranceService) UpdateBatchStatus(ctx context.Context, id, clinicID primitive.ObjectID, dto models.UpdateClaimBatchStatusDTO) (*models.ClaimBatch, error) {
+	batch, err := s.batchRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Claim batch")
+	}
+
+	from := batch.Status
+	now := time.Now().UTC()
+	switch {
+	case from == models.ClaimStatusPending && dto.Status == models.ClaimStatusSubmitted:
+		batch.SubmittedAt = &now
+	case from == models.ClaimStatusSubmitted && (dto.Status == models.ClaimStatusPaid || dto.Status == models.ClaimStatusRejected):
+		batch.SettledAt = &now
+	default:
+		return nil, apperrors.InvalidTransition(fmt.Sprintf("Cannot change claim batch from %s to %s", from, dto.Status))
+	}
+	batch.Status = dto.Status
+	if dto.Note != "" {
+		batch.Note = dto.Note
+	}
+
+	ok, err := s.batchRepo.UpdateStatus(ctx, batch, from)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to update claim batch", err)
+	}
+	if !ok {
+		return nil, apperrors.Conflict("Claim batch was changed concurrently, please reload")
+	}
+	if err := s.visitRepo.SetClaimStatusByBatch(ctx, clinicID, batch.ID, batch.Status); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to update claims", err)
+	}
+	return batch, nil
+}
+
+// DeleteBatch discards a batch that was not submitted; its claims become
+// pending again
+func (s *InsuranceService) DeleteBatch(ctx context.Context, id, clinicID primitive.ObjectID) error {
+	deleted, err := s.batchRepo.DeletePending(ctx, id, clinicID)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to delete claim batch", err)
+	}
+	if !deleted {
+		if _, err := s.batchRepo.GetByID(ctx, id, clinicID); err != nil {
+			return apperrors.NotFound("Claim batch")
+		}
+		return apperrors.BadRequest("Only batches not yet submitted can be deleted")
+	}
+	if err := s.visitRepo.ReleaseClaimBatch(ctx, clinicID, id); err != nil {
+		return apperrors.InternalWithErr("Failed to release claims", err)
+	}
+	return nil
+}
+
+// ExportBatch renders a batch as CSV for submission to the payer
+func (s *InsuranceService) ExportBatch(ctx context.Context, id, clinicID primitive.ObjectID) ([]byte, string, error) {
+	batch, err := s.batchRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, "", apperrors.NotFound("Claim batch")
+	}
+	payer, err := s.payerRepo.GetByID(ctx, batch.PayerID, clinicID)
+	if err != nil {
+		return nil, "", apperrors.NotFound("Payer")
+	}
+	number := models.FormatDocumentNumber(models.ClaimBatchPrefix, batch.Number)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"batch", "payer", "payer_code", "visit_date", "invoice", "patient", "policy_number", "visit_total", "claimed"})
+	for _, line := range batch.Claims {
+		invoice := ""
+		if line.InvoiceNumber > 0 {
+			invoice = models.FormatDocumentNumber(models.InvoicePrefix, line.InvoiceNumber)
+		}
+		_ = w.Write([]string{
+			number,
+			payer.Name,
+			payer.Code,
+			line.Date,
+			invoice,
+			line.PatientName,
+			line.PolicyNumber,
+			fmt.Sprintf("%.2f", line.Total),
+			fmt.Sprintf("%.2f", line.Amount),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, "", apperrors.InternalWithErr("Failed to export claim batch", err)
+	}
+	return buf.Bytes(), number + ".csv", nil
+}
+
+// patientName resolves a patient's name, caching lookups in names
+func (s *InsuranceService) patientName(ctx context.Context, clinicID, patientID primitive.ObjectID, names map[primitive.ObjectID]string) string {
+	if name, ok := names[patientID]; ok {
+		return name
+	}
+	name := ""
+	if patient, err := s.patientRepo.GetByID(ctx, patientID, clinicID); err == nil {
+		name = patient.FirstName + " " + patient.LastName
+	}
+	names[patientID] = name
+	return name
+}
+
+// payerNames maps the clinic's payer IDs to their names
+func (s *InsuranceService) payerNames(ctx context.Context, clinicID primitive.ObjectID) map[primitive.ObjectID]string {
+	names := make(map[primitive.ObjectID]string)
+	payers, err := s.payerRepo.List(ctx, clinicID, false)
+	if err != nil {
+		return names
+	}
+	for _, p := range payers {
+		names[p.ID] = p.Name
+	}
+	return names
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+	"medical-crm/pkg/logger"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InventoryService keeps stock of consumables. Every change to an item's
+// quantity is recorded as a movement; completed visits consume the bill of
+// materials of their services.
+type InventoryService struct {
+	itemRepo     *repository.InventoryItemRepository
+	movementRepo *repository.StockMovementRepository
+	serviceRepo  *repository.ServiceRepository
+	expenseRepo  *repository.ExpenseRepository
+	clock        *ClinicClock
+	log          *logger.Logger
+}
+
+func NewInventoryService(
+	itemRepo *repository.InventoryItemRepository,
+	movementRepo *repository.StockMovementRepository,
+	serviceRepo *repository.ServiceRepository,
+	expenseRepo *repository.ExpenseRepository,
+	clock *ClinicClock,
+	log *logger.Logger,
+) *InventoryService {
+	return &InventoryService{
+		itemRepo:     itemRepo,
+		movementRepo: movementRepo,
+		serviceRepo:  serviceRepo,
+		expenseRepo:  expenseRepo,
+		clock:        clock,
+		log:          log,
+	}
+}
+
+// CreateItem adds an item with no stock
+func (s *InventoryService) CreateItem(ctx context.Context, dto models.CreateInventoryItemDTO, clinicID primitive.ObjectID) (*models.InventoryItem, error) {
+	item := &models.InventoryItem{
+		ClinicID:          clinicID,
+		Name:              strings.TrimSpace(dto.Name),
+		SKU:               strings.TrimSpace(dto.SKU),
+		Unit:              strings.TrimSpace(dto.Unit),
+		LowStockThreshold: dto.LowStockThreshold,
+		UnitCost:          dto.UnitCost,
+	}
+	if err := s.itemRepo.Create(ctx, item); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, apperrors.Conflict("Item with this name already exists")
+		}
+		return nil, apperrors.InternalWithErr("Failed to create item", err)
+	}
+	return item, nil
+}
+
+// ListItems returns the clinic's items
+func (s *InventoryService) ListItems(ctx context.Context, clinicID primitive.ObjectID, activeOnly bool) ([]models.InventoryItem, error) {
+	items, err := s.itemRepo.List(ctx, clinicID, activeOnly)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list items", err)
+	}
+	if items == nil {
+		items = []models.InventoryItem{}
+	}
+	return items, nil
+}
+
+// GetItem returns an item
+func (s *InventoryService) GetItem(ctx context.Context, id, clinicID primitive.ObjectID) (*models.InventoryItem, error) {
+	item, err := s.itemRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Item")
+	}
+	return item, nil
+}
+
+// UpdateItem updates an item's details; stock changes go through movements
+func (s *InventoryService) UpdateItem(ctx context.Context, id, clinicID primitive.ObjectID, dto models.UpdateInventoryItemDTO) (*models.InventoryItem, error) {
+	item, err := s.itemRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Item")
+	}
+
+	if dto.Name != nil {
+		item.Name = strings.TrimSpace(*dto.Name)
+	}
+	if dto.SKU != nil {
+		item.SKU = strings.TrimSpace(*dto.SKU)
+	}
+	if dto.Unit != nil {
+		item.Unit = strings.TrimSpace(*dto.Unit)
+	}
+	if dto.LowStockThreshold != nil {
+		item.LowStockThreshold = *dto.LowStockThreshold
+	}
+	if dto.UnitCost != nil {
+		item.UnitCost = *dto.UnitCost
+	}
+	if dto.IsActive != nil {
+		item.IsActive = *dto.IsActive
+	}
+
+	if err := s.itemRepo.Update(ctx, item); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, apperrors.Conflict("Item with this name already exists")
+		}
+		return nil, apperrors.InternalWithErr("Failed to update item", err)
+	}
+	return item, nil
+}
+
+// LowStock returns active items at or below their low-stock threshold
+func (s *InventoryService) LowStock(ctx context.Context, clinicID primitive.ObjectID) ([]models.InventoryItem, error) {
+	items, err := s.itemRepo.ListLowStock(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list low stock items", err)
+	}
+	if items == nil {
+		items = []models.InventoryItem{}
+	}
+	return items, nil
+}
+
+// RecordMovement records a purchase, adjustment or write-off. Decreases
+// cannot take the stock below zero. A purchase can be booked as a supplies
+// expense at the same time.
+func (s *InventoryService) RecordMovement(ctx context.Context, itemID, clinicID, actorID primitive.ObjectID, dto models.CreateStockMovementDTO) (*models.StockMovement, error) {
+	item, err := s.itemRepo.GetByID(ctx, itemID, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Item")
+	}
+
+	delta := dto.Quantity
+	switch dto.Type {
+	case models.StockMovementPurchase, models.StockMovementWriteOff:
+		if dto.Quantity <= 0 {
+			return nil, apperrors.BadRequest("Quantity must be positive")
+		}
+		if dto.Type == models.StockMovementWriteOff {
+			delta = -dto.Quantity
+		}
+	case models.StockMovementAdjustment:
+		if dto.Quantity == 0 {
+			return nil, apperrors.BadRequest("Quantity must not be zero")
+		}
+	}
+	if dto.Type != models.StockMovementPurchase && (dto.UnitCost > 0 || dto.CreateExpense) {
+		return nil, apperrors.BadRequest("unit_cost and create_expense apply to purchases only")
+	}
+	if dto.CreateExpense && dto.UnitCost <= 0 {
+		return nil, apperrors.BadRequest("unit_cost is required to record the purchase as an expense")
+	}
+
+	date := dto.Date
+	if date == "" {
+		date = s.clock.Today(ctx, clinicID)
+	} else if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, apperrors.BadRequest("Invalid date, expected YYYY-MM-DD")
+	}
+
+	movement := &models.StockMovement{
+		ClinicID:  clinicID,
+		ItemID:    item.ID,
+		Type:      dto.Type,
+		Quantity:  delta,
+		UnitCost:  dto.UnitCost,
+		Date:      date,
+		Note:      dto.Note,
+		CreatedBy: actorID,
+	}
+
+	var expense *models.Expense
+	if dto.CreateExpense {
+		expense = &models.Expense{
+			ClinicID:  clinicID,
+			Category:  "supplies",
+			Amount:    roundMoney(dto.Quantity * dto.UnitCost),
+			Date:      date,
+			Note:      fmt.Sprintf("Purchase: %s, %g %s", item.Name, dto.Quantity, item.Unit),
+			CreatedBy: actorID,
+		}
+		if err := s.expenseRepo.Create(ctx, expense); err != nil {
+			return nil, apperrors.InternalWithErr("Failed to record expense", err)
+		}
+		movement.ExpenseID = &expense.ID
+	}
+
+	updated, err := s.itemRepo.AddStock(ctx, item.ID, clinicID, delta, false, dto.UnitCost)
+	if err != nil {
+		if expense != nil {
+			_ = s.expenseRepo.Delete(ctx, expense.ID, clinicID)
+		}
+		if err == mongo.ErrNoDocuments {
+			return nil, apperrors.BadRequest(fmt.Sprintf("Not enough stock: %g %s on hand", item.Quantity, item.Unit))
+		}
+		return nil, apperrors.InternalWithErr("Failed to update stock", err)
+	}
+	movement.Balance = updated.Quantity
+
+	if err := s.movementRepo.Create(ctx, movement); err != nil {
+		// Undo the stock change and the expense so nothing is left unrecorded
+		if _, undoErr := s.itemRepo.AddStock(ctx, item.ID, clinicID, -delta, true, item.UnitCost); undoErr != nil {
+			s.log.Error(fmt.Sprintf("failed to undo stock change: item_id=%s", item.ID.Hex()), undoErr)
+		}
+		if expense != nil {
+			_ = s.expenseRepo.Delete(ctx, expense.ID, clinicID)
+		}
+		return nil, apperrors.InternalWithErr("Failed to record stock movement", err)
+	}
+	return movement, nil
+}
+
+// ListMovements returns stock movements filtered by item, visit, type and
+// date range
+func (s *InventoryService) ListMovements(ctx context.Context, clinicID primitive.ObjectID, itemID, visitID *primitive.ObjectID, movementType, from, to string) ([]models.StockMovement, error) {
+	movements, err := s.movementRepo.List(ctx, clinicID, itemID, visitID, movementType, from, to)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list stock movements", err)
+	}
+	if movements == nil {
+		movements = []models.StockMovement{}
+	}
+	return movements, nil
+}
+
+// SetServiceMaterials replaces the items a service uses up per unit
+func (s *InventoryService) SetServiceMaterials(ctx context.Context, serviceID, clinicID primitive.ObjectID, dto models.SetServiceMaterialsDTO) (*models.Service, error) {
+	svc, err := s.serviceRepo.GetByID(ctx, serviceID, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Service")
+	}
+
+	materials := make([]models.ServiceMaterial, 0, len(dto.Materials))
+	ids := make([]primitive.ObjectID, 0, len(dto.Materials))
+	for _, m := range dto.Materials {
+		itemID, err := primitive.ObjectIDFromHex(m.ItemID)
+		if err != nil {
+			return nil, apperrors.BadRequest("Invalid item ID: " + m.ItemID)
+		}
+		if containsObjectID(ids, itemID) {
+			return nil, apperrors.BadRequest("Item listed more than once: " + m.ItemID)
+		}
+		ids = append(ids, itemID)
+		materials = append(materials, models.ServiceMaterial{ItemID: itemID, Quantity: m.Quantity})
+	}
+	if len(ids) > 0 {
+		items, err := s.itemRepo.GetMultipleByIDs(ctx, ids, clinicID)
+		if err != nil {
+			return nil, apperrors.InternalWithErr("Failed to fetch items", err)
+		}
+		if len(items) != len(ids) {
+			return nil, apperrors.BadRequest("One or more items not found")
+		}
+	}
+
+	if err := s.serviceRepo.SetMaterials(ctx, serviceID, clinicID, materials); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to update service materials", err)
+	}
+	svc.Materials = materials
+	return svc, nil
+}
+
+// ConsumeForVisit takes the materials of a completed visit's services out of
+// stock. The visit is already completed, so failures are logged and left to
+// be corrected with adjustments; stock may go below zero.
+func (s *InventoryService) ConsumeForVisit(ctx context.Context, visit *models.Visit) {
+	ids := make([]primitive.ObjectID, 0, len(visit.Services))
+	for _, line := range visit.Services {
+		ids = append(ids, line.ServiceID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	services, err := s.serviceRepo.GetMultipleByIDs(ctx, ids, visit.ClinicID)
+	if err != nil {
+		s.log.Error("failed to fetch services for stock consumption", err)
+		return
+	}
+	byID := make(map[primitive.ObjectID]models.Service, len(services))
+	for _, svc := range services {
+		byID[svc.ID] = svc
+	}
+
+	// Total per item, in bill of materials order
+	var order []primitive.ObjectID
+	used := make(map[primitive.ObjectID]float64)
+	for _, line := range visit.Services {
+		for _, m := range byID[line.ServiceID].Materials {
+			if _, ok := used[m.ItemID]; !ok {
+				order = append(order, m.ItemID)
+			}
+			used[m.ItemID] += m.Quantity * float64(line.Quantity)
+		}
+	}
+
+	note := "Visit"
+	if visit.InvoiceNumber > 0 {
+		note = "Visit " + models.FormatDocumentNumber(models.InvoicePrefix, visit.InvoiceNumber)
+	}
+	for _, itemID := range order {
+		item, err := s.itemRepo.AddStock(ctx, itemID, visit.ClinicID, -used[itemID], true, 0)
+		if err != nil {
+			s.log.Error(fmt.Sprintf("failed to consume stock: visit_id=%s item_id=%s", visit.ID.Hex(), itemID.Hex()), err)
+			continue
+		}
+		visitID := visit.ID
+		movement := &models.StockMovement{
+			ClinicID:  visit.ClinicID,
+			ItemID:    itemID,
+			Type:      models.StockMovementConsumption,
+			Quantity:  -used[itemID],
+			Balance:   item.Quantity,
+			VisitID:   &visitID,
+			Date:      visit.Date,
+			Note:      note,
+			CreatedBy: visit.DoctorID,
+		}
+		if err := s.movementRepo.Create(ctx, movement); err != nil {
+			s.log.Error(fmt.Sprintf("failed to record stock consumption: visit_id=%s item_id=%s", visit.ID.Hex(), itemID.Hex()), err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+)
+
+const (
+	// lockTTL bounds how long a crashed holder can block others
+	lockTTL = 30 * time.Second
+	// lockWait is how long a request waits for a busy lock before giving up
+	lockWait = 5 * time.Second
+	// lockRetry is the pause between attempts on a busy lock
+	lockRetry = 50 * time.Millisecond
+)
+
+// Locker serializes check-then-write sequences that a unique index cannot
+// guard, such as overlap checks before booking, across server instances
+type Locker struct {
+	lockRepo *repository.LockRepository
+}
+
+func NewLocker(lockRepo *repository.LockRepository) *Locker {
+	return &Locker{lockRepo: lockRepo}
+}
+
+// Do runs fn holding the named lock, waiting briefly if another request
+// holds it
+func (l *Locker) Do(ctx context.Context, name string, fn func() error) error {
+	deadline := time.Now().Add(lockWait)
+	for {
+		token, ok, err := l.lockRepo.Acquire(ctx, name, lockTTL)
+		if err != nil {
+			return apperrors.InternalWithErr("Failed to acquire lock", err)
+		}
+		if ok {
+			// Release even if the request was cancelled meanwhile
+			defer l.lockRepo.Release(context.WithoutCancel(ctx), name, token)
+			return fn()
+		}
+		if time.Now().After(deadline) {
+			return apperrors.Conflict("Another change to the same records is in progress, please try again")
+		}
+		select {
+		case <-ctx.Done():
+			return apperrors.InternalWithErr("Failed to acquire lock", ctx.Err())
+		case <-time.After(lockRetry):
+		}
+	}
+}
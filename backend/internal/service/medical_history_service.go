@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MedicalHistoryService manages patients' structured medical history.
+// The current history lives on the patient; every saved change is also
+// kept as a version with its author and time.
+type MedicalHistoryService struct {
+	patientRepo *repository.PatientRepository
+	versionRepo *repository.MedicalHistoryRepository
+	clock       *ClinicClock
+}
+
+func NewMedicalHistoryService(
+	patientRepo *repository.PatientRepository,
+	versionRepo *repository.MedicalHistoryRepository,
+	clock *ClinicClock,
+) *MedicalHistoryService {
+	return &MedicalHistoryService{
+		patientRepo: patientRepo,
+		versionRepo: versionRepo,
+		clock:       clock,
+	}
+}
+
+// Get returns the patient's current medical history and its alerts
+func (s *MedicalHistoryService) Get(ctx context.Context, patientID, clinicID primitive.ObjectID) (*models.MedicalHistoryResponse, error) {
+	patient, err := s.patientRepo.GetByID(ctx, patientID, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+	return medicalHistoryResponse(patient), nil
+}
+
+// Update replaces the patient's medical history, marks it reviewed today and
+// stores the new version
+func (s *MedicalHistoryService) Update(ctx context.Context, patientID, clinicID, userID primitive.ObjectID, dto models.UpdateMedicalHistoryDTO) (*models.MedicalHistoryResponse, error) {
+	patient, err := s.patientRepo.GetByID(ctx, patientID, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+
+	prevVersion := 0
+	if patient.MedicalHistory != nil {
+		prevVersion = patient.MedicalHistory.Version
+	}
+	if dto.Version != 0 && dto.Version != prevVersion {
+		return nil, apperrors.Conflict("Medical history was changed by someone else, please reload and try again")
+	}
+
+	history := &models.MedicalHistory{
+		Conditions:     trimmedList(dto.Conditions),
+		Medications:    trimmedList(dto.Medications),
+		Pregnant:       dto.Pregnant,
+		BloodThinners:  dto.BloodThinners,
+		Alerts:         trimmedList(dto.Alerts),
+		Notes:          strings.TrimSpace(dto.Notes),
+		LastReviewedAt: s.clock.Today(ctx, clinicID),
+		Version:        prevVersion + 1,
+		UpdatedBy:      userID,
+		UpdatedAt:      time.Now().UTC(),
+	}
+	for _, a := range dto.Allergies {
+		history.Allergies = append(history.Allergies, models.Allergy{
+			Substance: strings.TrimSpace(a.Substance),
+			Reaction:  strings.TrimSpace(a.Reaction),
+			Severity:  a.Severity,
+		})
+	}
+
+	if err := s.patientRepo.SetMedicalHistory(ctx, patientID, clinicID, history, prevVersion); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperrors.Conflict("Medical history was changed by someone else, please reload and try again")
+		}
+		return nil, apperrors.InternalWithErr("Failed to update medical history", err)
+	}
+
+	// The patient already holds this version, so a failed insert only
+	// leaves a gap in the version list
+	if err := s.versionRepo.Create(ctx, &models.MedicalHistoryVersion{
+		ClinicID:  clinicID,
+		PatientID: patientID,
+		History:   *history,
+	}); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to save medical history version", err)
+	}
+
+	patient.MedicalHistory = history
+	return medicalHistoryResponse(patient), nil
+}
+
+// ListVersions returns every saved version of the patient's medical history,
+// newest first
+func (s *MedicalHistoryService) ListVersions(ctx context.Context, patientID, clinicID primitive.ObjectID) ([]models.MedicalHistoryVersion, error) {
+	if _, err := s.patientRepo.GetByID(ctx, patientID, clinicID); err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+
+	versions, err := s.versionRepo.ListByPatient(ctx, clinicID, patientID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list medical history versions", err)
+	}
+	if versions == nil {
+		versions = []models.MedicalHistoryVersion{}
+	}
+	return versions, nil
+}
+
+func medicalHistoryResponse(patient *models.Patient) *models.MedicalHistoryResponse {
+	alerts := patient.MedicalHistory.CriticalAlerts()
+	if alerts == nil {
+		alerts = []models.MedicalAlert{}
+	}
+	return &models.MedicalHistoryResponse{
+		PatientID: patient.ID.Hex(),
+		History:   patient.MedicalHistory,
+		Alerts:    alerts,
+	}
+}
+
+// trimmedList trims entries, dropping blank ones and duplicates
+func trimmedList(raw []string) []string {
+	var list []string
+	seen := make(map[string]bool)
+	for _, v := range raw {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+		seen[strings.ToLower(v)] = true
+		list = append(list, v)
+	}
+	return list
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	"medical-crm/pkg/logger"
+)
+
+// noShowRequestID tags audit entries written by the no-show job
+const noShowRequestID = "job:no-show"
+
+// NoShowService marks appointments as no-show when the patient never arrived
+type NoShowService struct {
+	clinicRepo         *repository.ClinicRepository
+	appointmentRepo    *repository.AppointmentRepository
+	visitRepo          *repository.VisitRepository
+	appointmentService *AppointmentService
+	auditService       *AuditService
+	grace              time.Duration
+	log                *logger.Logger
+}
+
+func NewNoShowService(
+	clinicRepo *repository.ClinicRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	visitRepo *repository.VisitRepository,
+	appointmentService *AppointmentService,
+	auditService *AuditService,
+	grace time.Duration,
+	log *logger.Logger,
+) *NoShowService {
+	return &NoShowService{
+		clinicRepo:         clinicRepo,
+		appointmentRepo:    appointmentRepo,
+		visitRepo:          visitRepo,
+		appointmentService: appointmentService,
+		auditService:       auditService,
+		grace:              grace,
+		log:                log,
+	}
+}
+
+// Run sweeps every active clinic once. A failing clinic is logged and does
+// not stop the others.
+func (s *NoShowService) Run(ctx context.Context) error {
+	clinics, err := s.clinicRepo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().UTC().Add(-s.grace)
+	for i := range clinics {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		marked, err := s.markClinic(ctx, &clinics[i], cutoff)
+		if err != nil {
+			s.log.ErrorWithFields("No-show sweep failed", err, map[string]interface{}{"clinic_id": clinics[i].ID.Hex()})
+			continue
+		}
+		if marked > 0 {
+			s.log.InfoWithFields("Marked appointments as no-show", map[string]interface{}{
+				"clinic_id": clinics[i].ID.Hex(),
+				"count":     marked,
+			})
+		}
+	}
+	return nil
+}
+
+// markClinic marks the clinic's scheduled or confirmed appointments that
+// ended before cutoff and have no visit. Dates in the audit entries are
+// clinic-local.
+func (s *NoShowService) markClinic(ctx context.Context, clinic *models.Clinic, cutoff time.Time) (int, error) {
+	overdue, err := s.appointmentRepo.ListOverdue(ctx, clinic.ID, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	loc := clinic.Location()
+	marked := 0
+	for i := range overdue {
+		appointment := &overdue[i]
+
+		// A visit started without moving the appointment along (older data)
+		// means the patient did come
+		if _, err := s.visitRepo.GetByAppointment(ctx, appointment.ID, clinic.ID); err == nil {
+			continue
+		}
+
+		if err := s.appointmentService.UpdateStatus(ctx, appointment.ID, clinic.ID, models.AppointmentStatusNoShow, models.SystemActorID); err != nil {
+			// Changed concurrently, e.g. the visit started just now
+			s.log.Debugf("Skipped no-show for appointment %s: %v", appointment.ID.Hex(), err)
+			continue
+		}
+		marked++
+
+		s.auditService.LogAsync(clinic.ID, models.SystemActorID, appointment.ID, models.AuditActionAppointmentNoShow, "appointment", noShowRequestID, map[string]interface{}{
+			"old_status":    appointment.Status,
+			"new_status":    models.AppointmentStatusNoShow,
+			"patient_id":    appointment.PatientID.Hex(),
+			"doctor_id":     appointment.DoctorID.Hex(),
+			"date":          models.LocalDate(appointment.StartTime, loc),
+			"end_time":      appointment.EndTime.In(loc).Format("15:04"),
+			"grace_minutes": int(s.grace / time.Minute),
+		})
+	}
+	return marked, nil
+}
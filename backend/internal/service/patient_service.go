@@ -12,12 +12,14 @@ import (
 )
 
 type PatientService struct {
-	patientRepo *repository.PatientRepository
+	patientRepo     *repository.PatientRepository
+	appointmentRepo *repository.AppointmentRepository
 }
 
-func NewPatientService(patientRepo *repository.PatientRepository) *PatientService {
+func NewPatientService(patientRepo *repository.PatientRepository, appointmentRepo *repository.AppointmentRepository) *PatientService {
 	return &PatientService{
-		patientRepo: patientRepo,
+		patientRepo:     patientRepo,
+		appointmentRepo: appointmentRepo,
 	}
 }
 
@@ -34,6 +36,8 @@ func (s *PatientService) Create(ctx context.Context, dto models.CreatePatientDTO
 		FirstName: dto.FirstName,
 		LastName:  dto.LastName,
 		Phone:     dto.Phone,
+		Email:     dto.Email,
+		Telegram:  dto.Telegram,
 		DOB:       dto.DOB,
 		Gender:    dto.Gender,
 		Address:   dto.Address,
@@ -60,6 +64,17 @@ func (s *PatientService) GetByID(ctx context.Context, id, clinicID primitive.Obj
 	return patient, nil
 }
 
+// Response converts a patient to its API response including the no-show count
+func (s *PatientService) Response(ctx context.Context, patient *models.Patient) (*models.PatientResponse, error) {
+	counts, err := s.appointmentRepo.CountNoShowsByPatients(ctx, patient.ClinicID, []primitive.ObjectID{patient.ID})
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to count no-shows", err)
+	}
+	resp := patient.ToResponse()
+	resp.NoShowCount = counts[patient.ID]
+	return &resp, nil
+}
+
 // List returns paginated patients
 func (s *PatientService) List(ctx context.Context, clinicID primitive.ObjectID, page, pageSize int, search string) (*models.PaginatedPatientsResponse, error) {
 	if page < 1 {
@@ -74,10 +89,20 @@ func (s *PatientService) List(ctx context.Context, clinicID primitive.ObjectID,
 		return nil, apperrors.InternalWithErr("Failed to list patients", err)
 	}
 
+	ids := make([]primitive.ObjectID, len(patients))
+	for i, p := range patients {
+		ids[i] = p.ID
+	}
+	noShows, err := s.appointmentRepo.CountNoShowsByPatients(ctx, clinicID, ids)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to count no-shows", err)
+	}
+
 	// Convert to response
 	responses := make([]models.PatientResponse, len(patients))
 	for i, p := range patients {
 		responses[i] = p.ToResponse()
+		responses[i].NoShowCount = noShows[p.ID]
 	}
 
 	totalPages := int(total) / pageSize
@@ -116,6 +141,12 @@ func (s *PatientService) Update(ctx context.Context, id, clinicID primitive.Obje
 		}
 		patient.Phone = dto.Phone
 	}
+	if dto.Email != "" {
+		patient.Email = dto.Email
+	}
+	if dto.Telegram != "" {
+		patient.Telegram = dto.Telegram
+	}
 	if dto.DOB != nil {
 		patient.DOB = dto.DOB
 	}
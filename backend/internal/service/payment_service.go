@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PaymentService records patient payments against completed visits and
+// derives what visits and patients still owe
+type PaymentService struct {
+	paymentRepo *repository.PaymentRepository
+	visitRepo   *repository.VisitRepository
+	patientRepo *repository.PatientRepository
+	userRepo    *repository.UserRepository
+	clinicRepo  *repository.ClinicRepository
+	counterRepo *repository.CounterRepository
+	shiftRepo   *repository.CashShiftRepository
+	clock       *ClinicClock
+	locker      *Locker
+}
+
+func NewPaymentService(
+	paymentRepo *repository.PaymentRepository,
+	visitRepo *repository.VisitRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	clinicRepo *repository.ClinicRepository,
+	counterRepo *repository.CounterRepository,
+	shiftRepo *repository.CashShiftRepository,
+	clock *ClinicClock,
+	locker *Locker,
+) *PaymentService {
+	return &PaymentService{
+		paymentRepo: paymentRepo,
+		visitRepo:   visitRepo,
+		patientRepo: patientRepo,
+		userRepo:    userRepo,
+		clinicRepo:  clinicRepo,
+		counterRepo: counterRepo,
+		shiftRepo:   shiftRepo,
+		clock:       clock,
+		locker:      locker,
+	}
+}
+
+// GetMethods returns the clinic's payment methods, including inactive ones
+func (s *PaymentService) GetMethods(ctx context.Context, clinicID primitive.ObjectID) ([]models.PaymentMethod, error) {
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Clinic")
+	}
+	return clinic.PaymentMethods(), nil
+}
+
+// UpdateMethods validates and stores the clinic's payment methods
+func (s *PaymentService) UpdateMethods(ctx context.Context, clinicID primitive.ObjectID, dto models.UpdatePaymentMethodsDTO) ([]models.PaymentMethod, error) {
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Clinic")
+	}
+
+	methods := make([]models.PaymentMethod, 0, len(dto.Methods))
+	seen := make(map[string]bool)
+	active := 0
+	for _, m := range dto.Methods {
+		code := strings.ToLower(strings.TrimSpace(m.Code))
+		if !models.ValidPaymentMethodCode(code) {
+			return nil, apperrors.BadRequest("Invalid payment method code: " + m.Code + " (use lowercase letters, digits and underscores)")
+		}
+		if seen[code] {
+			return nil, apperrors.BadRequest("Duplicate payment method: " + code)
+		}
+		seen[code] = true
+
+		isActive := m.IsActive == nil || *m.IsActive
+		if isActive {
+			active++
+		}
+		methods = append(methods, models.PaymentMethod{
+			Code:     code,
+			Name:     strings.TrimSpace(m.Name),
+			IsActive: isActive,
+		})
+	}
+	if active == 0 {
+		return nil, apperrors.BadRequest("At least one payment method must be active")
+	}
+
+	clinic.Payments = methods
+	if err := s.clinicRepo.Update(ctx, clinic); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to update payment methods", err)
+	}
+	return methods, nil
+}
+
+// Record adds a full or partial payment for a completed visit, paid with one
+// method or split over several
+func (s *PaymentService) Record(ctx context.Context, dto models.CreatePaymentDTO, clinicID, cashierID primitive.ObjectID) (*models.Payment, error) {
+	visitID, err := primitive.ObjectIDFromHex(dto.VisitID)
+	if err != nil {
+		return nil, apperrors.BadRequest("Invalid visit ID")
+	}
+
+	visit, err := s.visitRepo.GetByID(ctx, visitID, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Visit")
+	}
+	if visit.Status != models.VisitStatusCompleted {
+		return nil, apperrors.BadRequest("Payments can only be recorded for completed visits")
+	}
+
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Clinic")
+	}
+	method, splits, amount, err := paymentSplits(clinic, dto)
+	if err != nil {
+		return nil, err
+	}
+	if amount > visit.Outstanding()+models.MoneyEpsilon {
+		return nil, apperrors.BadRequest(fmt.Sprintf("Payment exceeds the outstanding amount of %.2f", visit.Outstanding()))
+	}
+
+	now := time.Now().UTC()
+	payment := &models.Payment{
+		ClinicID:  clinicID,
+		VisitID:   visit.ID,
+		PatientID: visit.PatientID,
+		Amount:    amount,
+		Method:    method,
+		Splits:    splits,
+		CashierID: cashierID,
+		PaidAt:    now,
+		Date:      s.clock.DateOf(ctx, clinicID, now),
+		Notes:     dto.Notes,
+	}
+
+	// Link the payment to the drawer it went into. Cash must go into an open
+	// shift so it is reconciled at close.
+	shift, err := s.shiftRepo.GetOpen(ctx, clinicID, cashierID)
+	switch {
+	case err == nil:
+		payment.ShiftID = &shift.ID
+	case err != mongo.ErrNoDocuments:
+		return nil, apperrors.InternalWithErr("Failed to find cash shift", err)
+	case cashAmount(payment) > 0:
+		return nil, apperrors.BadRequest("Open a cash shift before taking cash payments")
+	default:
+		shift = nil
+	}
+
+	err = intoShift(ctx, s.locker, s.shiftRepo, shift, func() error {
+		receiptNumber, err := s.counterRepo.Next(ctx, clinicID, models.CounterReceipt)
+		if err != nil {
+			return apperrors.InternalWithErr("Failed to number receipt", err)
+		}
+		payment.ReceiptNumber = receiptNumber
+
+		// The conditional increment guards against concurrent overpayment
+		ok, err := s.visitRepo.AddPayment(ctx, visit.ID, clinicID, amount)
+		if err != nil {
+			return apperrors.InternalWithErr("Failed to record payment", err)
+		}
+		if !ok {
+			return apperrors.Conflict("Visit balance changed, please reload and try again")
+		}
+
+		if err := s.paymentRepo.Create(ctx, payment); err != nil {
+			// Undo the increment so the visit matches the ledger
+			_, _ = s.visitRepo.AddPayment(ctx, visit.ID, clinicID, -amount)
+			return apperrors.InternalWithErr("Failed to record payment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// List returns payments filtered by visit, patient or clinic-local date range
+func (s *PaymentService) List(ctx context.Context, clinicID primitive.ObjectID, visitID, patientID *primitive.ObjectID, fromDate, toDate string) ([]models.PaymentResponse, error) {
+	for _, d := range []string{fromDate, toDate} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, apperrors.BadRequest("Invalid date format, expected YYYY-MM-DD")
+		}
+	}
+
+	payments, err := s.paymentRepo.List(ctx, clinicID, visitID, patientID, fromDate, toDate)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list payments", err)
+	}
+
+	patientNames := make(map[primitive.ObjectID]string)
+	cashierNames := make(map[primitive.ObjectID]string)
+	responses := make([]models.PaymentResponse, 0, len(payments))
+	for _, p := range payments {
+		resp := p.ToResponse()
+
+		if name, ok := patientNames[p.PatientID]; ok {
+			resp.PatientName = name
+		} else if patient, err := s.patientRepo.GetByID(ctx, p.PatientID, clinicID); err == nil {
+			resp.PatientName = patient.FirstName + " " + patient.LastName
+			patientNames[p.PatientID] = resp.PatientName
+		}
+		if name, ok := cashierNames[p.CashierID]; ok {
+			resp.CashierName = name
+		} else if cashier, err := s.userRepo.GetByIDWithClinicCheck(ctx, p.CashierID, clinicID); err == nil {
+			resp.CashierName = cashier.FirstName + " " + cashier.LastName
+			cashierNames[p.CashierID] = resp.CashierName
+		}
+
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// PatientBalance sums billed and paid amounts over the patient's completed
+// visits and lists the visits still owing
+func (s *PaymentService) PatientBalance(ctx context.Context, patientID, clinicID primitive.ObjectID) (*models.PatientBalanceResponse, error) {
+	if _, err := s.patientRepo.GetByID(ctx, patientID, clinicID); err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+
+	visits, err := s.visitRepo.ListByPatient(ctx, clinicID, patientID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to get patient visits", err)
+	}
+
+	balance := &models.PatientBalanceResponse{
+		PatientID: patientID.Hex(),
+		Visits:    []models.VisitBalance{},
+	}
+	for _, v := range visits {
+		if v.Status != models.VisitStatusCompleted {
+			continue
+		}
+		balance.Billed += v.PatientShare()
+		balance.Paid += v.PaidAmount
+
+		if outstanding := v.Outstanding(); outstanding > 0 {
+			balance.Outstanding += outstanding
+			balance.Visits = append(balance.Visits, models.VisitBalance{
+				VisitID:     v.ID.Hex(),
+				Date:        v.Date,
+				Total:       v.Total,
+				Paid:        v.PaidAmount,
+				Outstanding: outstanding,
+				Status:      v.PaymentStatus(),
+			})
+		}
+	}
+
+	sort.Slice(balance.Visits, func(i, j int) bool { return balance.Visits[i].Date < balance.Visits[j].Date })
+	return balance, nil
+}
+
+// paymentSplits validates the methods of a payment against the clinic's and
+// returns the stored method, the splits (only when several methods are used)
+// and the total amount
+func paymentSplits(clinic *models.Clinic, dto models.CreatePaymentDTO) (string, []models.PaymentSplit, float64, error) {
+	if len(dto.Splits) == 0 {
+		if dto.Method == "" || dto.Amount <= 0 {
+			return "", nil, 0, apperrors.BadRequest("Provide method and amount, or splits")
+		}
+		dto.Splits = []models.PaymentSplitDTO{{Method: dto.Method, Amount: dto.Amount}}
+	} else if dto.Method != "" {
+		return "", nil, 0, apperrors.BadRequest("Use either method or splits, not both")
+	}
+
+	// Merge repeated methods, keeping the order they were given in
+	var splits []models.PaymentSplit
+	index := make(map[string]int)
+	total := 0.0
+	for _, sp := range dto.Splits {
+		if !clinic.AcceptsPaymentMethod(sp.Method) {
+			return "", nil, 0, apperrors.BadRequest("Payment method not accepted by the clinic: " + sp.Method)
+		}
+		amount := roundMoney(sp.Amount)
+		if amount <= 0 {
+			return "", nil, 0, apperrors.BadRequest("Amount must be positive")
+		}
+		if i, ok := index[sp.Method]; ok {
+			splits[i].Amount = roundMoney(splits[i].Amount + amount)
+		} else {
+			index[sp.Method] = len(splits)
+			splits = append(splits, models.PaymentSplit{Method: sp.Method, Amount: amount})
+		}
+		total += amount
+	}
+	total = roundMoney(total)
+
+	if dto.Amount > 0 && math.Abs(roundMoney(dto.Amount)-total) > models.MoneyEpsilon {
+		return "", nil, 0, apperrors.BadRequest(fmt.Sprintf("Amount %.2f does not match the sum of splits %.2f", dto.Amount, total))
+	}
+	if len(splits) == 1 {
+		return splits[0].Method, nil, total, nil
+	}
+	return models.PaymentMethodSplit, splits, total, nil
+}
+
+// roundMoney rounds to cents
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
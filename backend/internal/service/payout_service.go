@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PayoutService closes doctors' earning periods into payout statements and
+// records what the clinic pays them. Once a period is closed, visits dated in
+// it and reversals posted in it are locked for that doctor.
+type PayoutService struct {
+	payoutRepo     *repository.PayoutStatementRepository
+	visitRepo      *repository.VisitRepository
+	adjustmentRepo *repository.VisitAdjustmentRepository
+	userRepo       *repository.UserRepository
+	clinicRepo     *repository.ClinicRepository
+	clock          *ClinicClock
+	locker         *Locker
+}
+
+func NewPayoutService(
+	payoutRepo *repository.PayoutStatementRepository,
+	visitRepo *repository.VisitRepository,
+	adjustmentRepo *repository.VisitAdjustmentRepository,
+	userRepo *repository.UserRepository,
+	clinicRepo *repository.ClinicRepository,
+	clock *ClinicClock,
+	locker *Locker,
+) *PayoutService {
+	return &PayoutService{
+		payoutRepo:     payoutRepo,
+		visitRepo:      visitRepo,
+		adjustmentRepo: adjustmentRepo,
+		userRepo:       userRepo,
+		clinicRepo:     clinicRepo,
+		clock:          clock,
+		locker:         locker,
+	}
+}
+
+// Close freezes a doctor's earnings for a period into a statement. The
+// period must not overlap a closed one, must not end in the future and must
+// have no visits still in progress.
+func (s *PayoutService) Close(ctx context.Context, dto models.ClosePayoutPeriodDTO, clinicID, bossID primitive.ObjectID) (*models.PayoutStatement, error) {
+	doctorID, err := primitive.ObjectIDFromHex(dto.DoctorID)
+	if err != nil {
+		return nil, apperrors.BadRequest("Invalid doctor ID")
+	}
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID)
+	if err != nil || doctor.Role != models.RoleDoctor {
+		return nil, apperrors.NotFound("Doctor")
+	}
+
+	for _, d := range []string{dto.From, dto.To} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, apperrors.BadRequest("Invalid date, expected YYYY-MM-DD")
+		}
+	}
+	if dto.To < dto.From {
+		return nil, apperrors.BadRequest("to must not be before from")
+	}
+	// Today is still open: visits can be started and completed until it ends
+	if dto.To >= s.clock.Today(ctx, clinicID) {
+		return nil, apperrors.BadRequest("A period can only be closed after it ends")
+	}
+
+	// Closes for one doctor are serialized; the unique index only covers
+	// period_from, so two overlapping periods could otherwise both pass the
+	// overlap check
+	var statement *models.PayoutStatement
+	err = s.locker.Do(ctx, "payouts:"+doctorID.Hex(), func() error {
+		var closeErr error
+		statement, closeErr = s.close(ctx, dto, clinicID, doctorID, bossID)
+		return closeErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// close checks the period against closed ones and open visits, then stores
+// its statement. The caller holds the doctor's payout lock.
+func (s *PayoutService) close(ctx context.Context, dto models.ClosePayoutPeriodDTO, clinicID, doctorID, bossID primitive.ObjectID) (*models.PayoutStatement, error) {
+	overlaps, err := s.payoutRepo.Overlaps(ctx, clinicID, doctorID, dto.From, dto.To)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to check payout periods", err)
+	}
+	if overlaps {
+		return nil, apperrors.Conflict("Part of this period is already closed for the doctor")
+	}
+
+	unfinished, err := s.visitRepo.CountUnfinishedByDoctorBetween(ctx, clinicID, doctorID, dto.From, dto.To)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to check visits", err)
+	}
+	if unfinished > 0 {
+		return nil, apperrors.BadRequest(fmt.Sprintf("%d visits in the period are not completed or approved yet", unfinished))
+	}
+
+	visits, err := s.visitRepo.ListCompletedByDoctorBetween(ctx, clinicID, doctorID, dto.From, dto.To)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to get visits", err)
+	}
+	adjustments, err := s.adjustmentRepo.ListByDoctorAndDateRange(ctx, clinicID, doctorID, dto.From, dto.To)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to get visit adjustments", err)
+	}
+
+	statement := &models.PayoutStatement{
+		ClinicID:   clinicID,
+		DoctorID:   doctorID,
+		PeriodFrom: dto.From,
+		PeriodTo:   dto.To,
+		Visits:     make([]models.PayoutVisit, 0, len(visits)),
+		Reversals:  make([]models.PayoutReversal, 0, len(adjustments)),
+		Notes:      strings.TrimSpace(dto.Notes),
+		ClosedBy:   bossID,
+	}
+	for _, v := range visits {
+		statement.Visits = append(statement.Visits, models.PayoutVisit{
+			VisitID:       v.ID,
+			PatientID:     v.PatientID,
+			Date:          v.Date,
+			InvoiceNumber: v.InvoiceNumber,
+			Total:         v.Total,
+			DoctorShare:   v.DoctorShare,
+			Earning:       roundMoney(v.DoctorEarning),
+		})
+		statement.Revenue += v.Total
+		statement.GrossEarning += v.DoctorEarning
+	}
+	for _, a := range adjustments {
+		statement.Reversals = append(statement.Reversals, models.PayoutReversal{
+			AdjustmentID:    a.ID,
+			VisitID:         a.VisitID,
+			VisitDate:       a.VisitDate,
+			Date:            a.Date,
+			Amount:          a.Amount,
+			EarningReversal: a.EarningReversal,
+		})
+		statement.ClawedBack += a.EarningReversal
+	}
+	statement.Revenue = roundMoney(statement.Revenue)
+	statement.GrossEarning = roundMoney(statement.GrossEarning)
+	statement.ClawedBack = roundMoney(statement.ClawedBack)
+	statement.Earning = roundMoney(statement.GrossEarning - statement.ClawedBack)
+
+	if err := s.payoutRepo.Create(ctx, statement); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to close payout period", err)
+	}
+	return statement, nil
+}
+
+// Get returns a statement with its visits; doctors only see their own
+func (s *PayoutService) Get(ctx context.Context, id, clinicID, userID primitive.ObjectID, role string) (*models.PayoutStatementResponse, error) {
+	statement, err := s.payoutRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Payout statement")
+	}
+	if role == models.RoleDoctor && statement.DoctorID != userID {
+		return nil, apperrors.NotFound("Payout statement")
+	}
+
+	resp := statement.ToResponse()
+	resp.Visits = statement.Visits
+	resp.Reversals = statement.Reversals
+	resp.DoctorName = s.doctorName(ctx, statement.DoctorID, clinicID)
+	return &resp, nil
+}
+
+// List returns statements, newest period first, optionally of one doctor
+func (s *PayoutService) List(ctx context.Context, clinicID primitive.ObjectID, doctorID *primitive.ObjectID) ([]models.PayoutStatementResponse, error) {
+	statements, err := s.payoutRepo.List(ctx, clinicID, doctorID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list payout statements", err)
+	}
+
+	names := make(map[primitive.ObjectID]string)
+	responses := make([]models.PayoutStatementResponse, 0, len(statements))
+	for _, st := range statements {
+		resp := st.ToResponse()
+		if _, ok := names[st.DoctorID]; !ok {
+			names[st.DoctorID] = s.doctorName(ctx, st.DoctorID, clinicID)
+		}
+		resp.DoctorName = names[st.DoctorID]
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// RecordPayment records money paid to the doctor against a statement
+func (s *PayoutService) RecordPayment(ctx context.Context, id, clinicID, actorID primitive.ObjectID, dto models.RecordPayoutPaymentDTO) (*models.PayoutStatement, error) {
+	statement, err := s.payoutRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Payout statement")
+	}
+
+	amount := roundMoney(dto.Amount)
+	if amount <= 0 {
+		return nil, apperrors.BadRequest("Amount must be positive")
+	}
+	if amount > statement.Owed()+models.MoneyEpsilon {
+		return nil, apperrors.BadRequest(fmt.Sprintf("Payment exceeds the amount owed (%.2f)", statement.Owed()))
+	}
+
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Clinic")
+	}
+	method := strings.ToLower(strings.TrimSpace(dto.Method))
+	if method == "" {
+		method = models.PaymentTypeCash
+	}
+	if !clinic.AcceptsPaymentMethod(method) {
+		return nil, apperrors.BadRequest("Payment method is not enabled for this clinic: " + method)
+	}
+
+	now := time.Now().UTC()
+	payment := models.PayoutPayment{
+		ID:        primitive.NewObjectID(),
+		Amount:    amount,
+		Method:    method,
+		Date:      s.clock.DateOf(ctx, clinicID, now),
+		Notes:     strings.TrimSpace(dto.Notes),
+		CreatedBy: actorID,
+		CreatedAt: now,
+	}
+	ok, err := s.payoutRepo.AddPayment(ctx, statement.ID, clinicID, payment)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to record payout payment", err)
+	}
+	if !ok {
+		return nil, apperrors.Conflict("Statement was paid concurrently, please reload")
+	}
+
+	statement.PaidAmount = roundMoney(statement.PaidAmount + amount)
+	statement.Payments = append(statement.Payments, payment)
+	return statement, nil
+}
+
+// Reopen deletes a statement nothing was paid against, unlocking its period
+func (s *PayoutService) Reopen(ctx context.Context, id, clinicID primitive.ObjectID) (*models.PayoutStatement, error) {
+	statement, err := s.payoutRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Payout statement")
+	}
+
+	deleted, err := s.payoutRepo.DeleteUnpaid(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to reopen payout period", err)
+	}
+	if !deleted {
+		return nil, apperrors.BadRequest("Statements with payments cannot be reopened")
+	}
+	return statement, nil
+}
+
+func (s *PayoutService) doctorName(ctx context.Context, doctorID, clinicID primitive.ObjectID) string {
+	if doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID); err == nil {
+		return doctor.FirstName + " " + doctor.LastName
+	}
+	return ""
+}
+
+// ensurePeriodOpen refuses changes to a doctor's earnings dated in one of
+// the doctor's closed payout periods
+func ensurePeriodOpen(ctx context.Context, payoutRepo *repository.PayoutStatementRepository, clinicID, doctorID primitive.ObjectID, date string) error {
+	closed, err := payoutRepo.Overlaps(ctx, clinicID, doctorID, date, date)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to check payout periods", err)
+	}
+	if closed {
+		return apperrors.BadRequest("The doctor's payout period including " + date + " is closed")
+	}
+	return nil
+}
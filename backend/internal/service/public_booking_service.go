@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+	"medical-crm/pkg/logger"
+	"medical-crm/pkg/notify"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PublicBookingService serves the unauthenticated booking pages of a clinic.
+// Requests are verified by a one-time code sent by SMS and land as pending
+// appointments for reception to approve.
+type PublicBookingService struct {
+	clinicRepo         *repository.ClinicRepository
+	serviceRepo        *repository.ServiceRepository
+	priceRepo          *repository.ServicePriceRepository
+	userRepo           *repository.UserRepository
+	patientRepo        *repository.PatientRepository
+	verificationRepo   *repository.PhoneVerificationRepository
+	appointmentService *AppointmentService
+	sms                notify.Notifier
+	log                *logger.Logger
+}
+
+func NewPublicBookingService(
+	clinicRepo *repository.ClinicRepository,
+	serviceRepo *repository.ServiceRepository,
+	priceRepo *repository.ServicePriceRepository,
+	userRepo *repository.UserRepository,
+	patientRepo *repository.PatientRepository,
+	verificationRepo *repository.PhoneVerificationRepository,
+	appointmentService *AppointmentService,
+	sms notify.Notifier,
+	log *logger.Logger,
+) *PublicBookingService {
+	return &PublicBookingService{
+		clinicRepo:         clinicRepo,
+		serviceRepo:        serviceRepo,
+		priceRepo:          priceRepo,
+		userRepo:           userRepo,
+		patientRepo:        patientRepo,
+		verificationRepo:   verificationRepo,
+		appointmentService: appointmentService,
+		sms:                sms,
+		log:                log,
+	}
+}
+
+// GetClinic resolves an active clinic by its public slug
+func (s *PublicBookingService) GetClinic(ctx context.Context, slug string) (*models.Clinic, error) {
+	clinic, err := s.clinicRepo.GetBySlug(ctx, slug)
+	if err != nil || !clinic.IsActive {
+		return nil, apperrors.NotFound("Clinic")
+	}
+	return clinic, nil
+}
+
+// ListServices returns the clinic's active services at today's prices
+func (s *PublicBookingService) ListServices(ctx context.Context, slug string) ([]models.PublicServiceResponse, error) {
+	clinic, err := s.GetClinic(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := s.serviceRepo.List(ctx, clinic.ID, true)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list services", err)
+	}
+	if err := applyPricesAt(ctx, s.priceRepo, clinic.ID, models.LocalDate(time.Now(), clinic.Location()), services); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to resolve service prices", err)
+	}
+
+	responses := make([]models.PublicServiceResponse, 0, len(services))
+	for _, svc := range services {
+		responses = append(responses, models.PublicServiceResponse{
+			ID:          svc.ID.Hex(),
+			Name:        svc.Name,
+			Description: svc.Description,
+			Price:       svc.Price,
+			Duration:    svc.Duration,
+		})
+	}
+	return responses, nil
+}
+
+// ListDoctors returns doctors accepting online bookings, i.e. active doctors
+// with a working schedule
+func (s *PublicBookingService) ListDoctors(ctx context.Context, slug string) ([]models.PublicDoctorResponse, error) {
+	clinic, err := s.GetClinic(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	doctors, err := s.userRepo.ListByClinicAndRole(ctx, clinic.ID, models.RoleDoctor)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list doctors", err)
+	}
+
+	responses := make([]models.PublicDoctorResponse, 0, len(doctors))
+	for _, d := range doctors {
+		if d.WorkSchedule == nil {
+			continue
+		}
+		responses = append(responses, models.PublicDoctorResponse{
+			ID:       d.ID.Hex(),
+			FullName: d.FirstName + " " + d.LastName,
+		})
+	}
+	return responses, nil
+}
+
+// GetSlots returns a doctor's free slots between two clinic-local dates
+func (s *PublicBookingService) GetSlots(ctx context.Context, slug string, doctorID primitive.ObjectID, from, to string, serviceIDs []string) (*models.DoctorAvailabilityResponse, error) {
+	clinic, err := s.GetClinic(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.appointmentService.GetAvailability(ctx, clinic.ID, doctorID, from, to, serviceIDs, 0)
+}
+
+// RequestCode sends a one-time verification code to the phone. Codes can be
+// resent after VerificationResendAfter, at most VerificationMaxPerPeriod times
+// per VerificationCodeTTL.
+func (s *PublicBookingService) RequestCode(ctx context.Context, slug string, dto models.RequestVerificationCodeDTO) error {
+	clinic, err := s.GetClinic(ctx, slug)
+	if err != nil {
+		return err
+	}
+	phone := normalizePhone(dto.Phone)
+	now := time.Now().UTC()
+
+	if latest, err := s.verificationRepo.GetLatest(ctx, clinic.ID, phone); err == nil {
+		if now.Sub(latest.CreatedAt) < models.VerificationResendAfter {
+			return apperrors.RateLimited("A code was sent recently, please wait before requesting another")
+		}
+	}
+	sent, err := s.verificationRepo.CountSince(ctx, clinic.ID, phone, now.Add(-models.VerificationCodeTTL))
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to check verification codes", err)
+	}
+	if sent >= models.VerificationMaxPerPeriod {
+		return apperrors.RateLimited("Too many codes requested for this phone, please try again later")
+	}
+
+	code, err := generateNumericCode(models.VerificationCodeLength)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to generate verification code", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to generate verification code", err)
+	}
+
+	verification := &models.PhoneVerification{
+		ClinicID:  clinic.ID,
+		Phone:     phone,
+		CodeHash:  string(hash),
+		ExpiresAt: now.Add(models.VerificationCodeTTL),
+	}
+	if err := s.verificationRepo.Create(ctx, verification); err != nil {
+		return apperrors.InternalWithErr("Failed to store verification code", err)
+	}
+
+	msg := notify.Message{
+		To:   phone,
+		Body: fmt.Sprintf("%s: your booking code is %s. It expires in %d minutes.", clinic.Name, code, int(models.VerificationCodeTTL/time.Minute)),
+	}
+	if err := s.sms.Send(ctx, msg); err != nil {
+		return apperrors.InternalWithErr("Failed to send verification code", err)
+	}
+	return nil
+}
+
+// Book verifies the code, matches or registers the patient by phone and
+// creates a pending appointment. The slot is checked before the code is used
+// up, and if the booking still fails the code is released and a patient
+// registered for it removed, so the caller can retry with the same code.
+func (s *PublicBookingService) Book(ctx context.Context, slug string, dto models.PublicBookingDTO) (*models.PublicBookingResponse, error) {
+	clinic, err := s.GetClinic(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	phone := normalizePhone(dto.Phone)
+
+	verification, err := s.checkCode(ctx, clinic.ID, phone, dto.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	appointmentDTO := models.CreateAppointmentDTO{
+		DoctorID:   dto.DoctorID,
+		StartTime:  dto.StartTime,
+		ServiceIDs: dto.ServiceIDs,
+		Notes:      dto.Notes,
+	}
+	if err := s.appointmentService.CheckPending(ctx, appointmentDTO, clinic.ID); err != nil {
+		return nil, err
+	}
+
+	// Using the code up first keeps it single-use under concurrent requests
+	consumed, err := s.verificationRepo.Consume(ctx, verification.ID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to check verification code", err)
+	}
+	if !consumed {
+		return nil, apperrors.InvalidCode("Verification code was already used")
+	}
+
+	patient, registered, err := s.matchPatient(ctx, clinic.ID, phone, dto)
+	if err != nil {
+		s.releaseCode(ctx, verification.ID)
+		return nil, err
+	}
+
+	// The slot may have been taken since it was checked
+	appointmentDTO.PatientID = patient.ID.Hex()
+	appointment, err := s.appointmentService.CreatePending(ctx, appointmentDTO, clinic.ID)
+	if err != nil {
+		if registered {
+			if perr := s.patientRepo.Purge(ctx, patient.ID, clinic.ID); perr != nil {
+				s.log.Error("Failed to remove patient registered for a failed booking", perr)
+			}
+		}
+		s.releaseCode(ctx, verification.ID)
+		return nil, err
+	}
+
+	resp := &models.PublicBookingResponse{
+		ID:        appointment.ID.Hex(),
+		Status:    appointment.Status,
+		StartTime: appointment.StartTime,
+		EndTime:   appointment.EndTime,
+		Duration:  int(appointment.Duration() / time.Minute),
+	}
+	if doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, appointment.DoctorID, clinic.ID); err == nil {
+		resp.DoctorName = doctor.FirstName + " " + doctor.LastName
+	}
+	return resp, nil
+}
+
+// checkCode checks the latest code for the phone, counting the attempt. The
+// code is not used up; Book consumes it once the slot is known to be free.
+func (s *PublicBookingService) checkCode(ctx context.Context, clinicID primitive.ObjectID, phone, code string) (*models.PhoneVerification, error) {
+	verification, err := s.verificationRepo.GetLatest(ctx, clinicID, phone)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperrors.InvalidCode("No valid verification code for this phone, please request a new one")
+		}
+		return nil, apperrors.InternalWithErr("Failed to check verification code", err)
+	}
+
+	ok, err := s.verificationRepo.RecordAttempt(ctx, verification.ID, models.VerificationMaxAttempts)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to check verification code", err)
+	}
+	if !ok {
+		return nil, apperrors.InvalidCode("Too many wrong attempts, please request a new code")
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(verification.CodeHash), []byte(strings.TrimSpace(code))) != nil {
+		return nil, apperrors.InvalidCode("Invalid verification code")
+	}
+	return verification, nil
+}
+
+// releaseCode makes a consumed code usable again after a failed booking
+func (s *PublicBookingService) releaseCode(ctx context.Context, id primitive.ObjectID) {
+	if err := s.verificationRepo.Release(ctx, id); err != nil {
+		s.log.Error("Failed to release verification code", err)
+	}
+}
+
+// matchPatient finds the clinic's patient with this phone or registers a new
+// one, reporting whether it registered one. Existing patient records are not
+// changed by online requests.
+func (s *PublicBookingService) matchPatient(ctx context.Context, clinicID primitive.ObjectID, phone string, dto models.PublicBookingDTO) (*models.Patient, bool, error) {
+	if patient, err := s.patientRepo.GetByPhone(ctx, phone, clinicID); err == nil {
+		return patient, false, nil
+	}
+
+	patient := &models.Patient{
+		ClinicID:  clinicID,
+		FirstName: strings.TrimSpace(dto.FirstName),
+		LastName:  strings.TrimSpace(dto.LastName),
+		Phone:     phone,
+		Notes:     "Registered via online booking",
+		CreatedBy: models.SystemActorID,
+	}
+	if err := s.patientRepo.Create(ctx, patient); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// Registered concurrently by another request
+			if existing, err := s.patientRepo.GetByPhone(ctx, phone, clinicID); err == nil {
+				return existing, false, nil
+			}
+		}
+		return nil, false, apperrors.InternalWithErr("Failed to register patient", err)
+	}
+	return patient, true, nil
+}
+
+// normalizePhone strips formatting so the same number always matches
+func normalizePhone(phone string) string {
+	return strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+}
+
+// generateNumericCode returns a random decimal code of the given length
+func generateNumericCode(length int) (string, error) {
+	var b strings.Builder
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(byte('0' + n.Int64()))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+	"medical-crm/pkg/logger"
+	"medical-crm/pkg/notify"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reminderRetryDelay is the wait before the first retry of a failed reminder;
+// later retries back off linearly
+const reminderRetryDelay = 15 * time.Minute
+
+// ReminderService sends appointment reminders through the configured
+// channels and records every delivery attempt
+type ReminderService struct {
+	clinicRepo      *repository.ClinicRepository
+	appointmentRepo *repository.AppointmentRepository
+	patientRepo     *repository.PatientRepository
+	userRepo        *repository.UserRepository
+	deliveryRepo    *repository.ReminderDeliveryRepository
+	clock           *ClinicClock
+	notifiers       map[string]notify.Notifier // By channel; unconfigured channels are absent
+	maxAttempts     int
+	log             *logger.Logger
+}
+
+func NewReminderService(
+	clinicRepo *repository.ClinicRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	deliveryRepo *repository.ReminderDeliveryRepository,
+	clock *ClinicClock,
+	notifiers map[string]notify.Notifier,
+	maxAttempts int,
+	log *logger.Logger,
+) *ReminderService {
+	return &ReminderService{
+		clinicRepo:      clinicRepo,
+		appointmentRepo: appointmentRepo,
+		patientRepo:     patientRepo,
+		userRepo:        userRepo,
+		deliveryRepo:    deliveryRepo,
+		clock:           clock,
+		notifiers:       notifiers,
+		maxAttempts:     maxAttempts,
+		log:             log,
+	}
+}
+
+// GetSettings returns the clinic's reminder settings, or the defaults
+func (s *ReminderService) GetSettings(ctx context.Context, clinicID primitive.ObjectID) (*models.ReminderSettings, error) {
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Clinic")
+	}
+	if clinic.Reminders == nil {
+		settings := models.DefaultReminderSettings()
+		return &settings, nil
+	}
+	return clinic.Reminders, nil
+}
+
+// UpdateSettings validates and stores the clinic's reminder settings
+func (s *ReminderService) UpdateSettings(ctx context.Context, clinicID primitive.ObjectID, dto models.UpdateReminderSettingsDTO) (*models.ReminderSettings, error) {
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Clinic")
+	}
+
+	settings := &models.ReminderSettings{
+		Enabled:  dto.Enabled,
+		Template: strings.TrimSpace(dto.Template),
+	}
+
+	seenHours := make(map[int]bool)
+	for _, h := range dto.HoursBefore {
+		if h < 1 || h > models.MaxReminderHoursBefore {
+			return nil, apperrors.BadRequest(fmt.Sprintf("hours_before must be between 1 and %d", models.MaxReminderHoursBefore))
+		}
+		if !seenHours[h] {
+			seenHours[h] = true
+			settings.HoursBefore = append(settings.HoursBefore, h)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(settings.HoursBefore)))
+
+	seenChannels := make(map[string]bool)
+	for _, ch := range dto.Channels {
+		switch ch {
+		case models.ReminderChannelSMS, models.ReminderChannelTelegram, models.ReminderChannelEmail:
+		default:
+			return nil, apperrors.BadRequest("Unknown reminder channel: " + ch)
+		}
+		if !seenChannels[ch] {
+			seenChannels[ch] = true
+			settings.Channels = append(settings.Channels, ch)
+		}
+	}
+
+	// Reject templates that fail to render before they reach the job
+	sample := models.ReminderTemplateData{
+		PatientName: "John Smith",
+		DoctorName:  "Jane Doe",
+		ClinicName:  clinic.Name,
+		ClinicPhone: clinic.Phone,
+		Date:        "2025-01-31",
+		Time:        "09:30",
+	}
+	if _, err := models.RenderReminder(settings.Template, sample); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	clinic.Reminders = settings
+	if err := s.clinicRepo.Update(ctx, clinic); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to update reminder settings", err)
+	}
+	return settings, nil
+}
+
+// ListDeliveries returns reminders for appointments on a clinic-local date
+// (today if empty), optionally only those with the given status
+func (s *ReminderService) ListDeliveries(ctx context.Context, clinicID primitive.ObjectID, date, status string) ([]models.ReminderDeliveryResponse, error) {
+	if date == "" {
+		date = s.clock.Today(ctx, clinicID)
+	} else if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, apperrors.BadRequest("Invalid date format, expected YYYY-MM-DD")
+	}
+
+	deliveries, err := s.deliveryRepo.List(ctx, clinicID, date, status)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list reminders", err)
+	}
+
+	responses := make([]models.ReminderDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		resp := d.ToResponse()
+		if patient, err := s.patientRepo.GetByID(ctx, d.PatientID, clinicID); err == nil {
+			resp.PatientName = patient.FirstName + " " + patient.LastName
+			resp.PatientPhone = patient.Phone
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// Run sends due reminders and retries failed ones for every active clinic
+// with reminders enabled. A failing clinic is logged and does not stop the others.
+func (s *ReminderService) Run(ctx context.Context) error {
+	clinics, err := s.clinicRepo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for i := range clinics {
+		clinic := &clinics[i]
+		if clinic.Reminders == nil || !clinic.Reminders.Enabled || len(clinic.Reminders.HoursBefore) == 0 {
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		sent, err := s.remindClinic(ctx, clinic, now)
+		if err != nil {
+			s.log.ErrorWithFields("Reminder run failed", err, map[string]interface{}{"clinic_id": clinic.ID.Hex()})
+			continue
+		}
+		retried, err := s.retryClinic(ctx, clinic, now)
+		if err != nil {
+			s.log.ErrorWithFields("Reminder retries failed", err, map[string]interface{}{"clinic_id": clinic.ID.Hex()})
+		}
+		if sent+retried > 0 {
+			s.log.InfoWithFields("Sent appointment reminders", map[string]interface{}{
+				"clinic_id": clinic.ID.Hex(),
+				"sent":      sent,
+				"retried":   retried,
+			})
+		}
+	}
+	return nil
+}
+
+// remindClinic claims and sends the reminders that became due
+func (s *ReminderService) remindClinic(ctx context.Context, clinic *models.Clinic, now time.Time) (int, error) {
+	offsets := append([]int(nil), clinic.Reminders.HoursBefore...)
+	sort.Ints(offsets)
+	horizon := now.Add(time.Duration(offsets[len(offsets)-1]) * time.Hour)
+
+	appointments, err := s.appointmentRepo.ListStartingBetween(ctx, clinic.ID, now, horizon)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range appointments {
+		appointment := &appointments[i]
+		hours, ok := dueReminder(offsets, appointment, now)
+		if !ok {
+			continue
+		}
+
+		delivery := &models.ReminderDelivery{
+			ClinicID:         clinic.ID,
+			AppointmentID:    appointment.ID,
+			PatientID:        appointment.PatientID,
+			AppointmentStart: appointment.StartTime,
+			AppointmentDate:  appointment.Date,
+			HoursBefore:      hours,
+		}
+		claimed, err := s.deliveryRepo.Claim(ctx, delivery)
+		if err != nil {
+			s.log.Error("Failed to claim reminder", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if s.deliver(ctx, clinic, appointment, delivery) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// dueReminder picks the reminder to send now: the closest offset whose time
+// has come. Offsets that were already due when the appointment was booked are
+// skipped, so a booking made this morning gets no "tomorrow" reminder.
+func dueReminder(ascOffsets []int, appointment *models.Appointment, now time.Time) (int, bool) {
+	for _, h := range ascOffsets {
+		dueAt := appointment.StartTime.Add(-time.Duration(h) * time.Hour)
+		if dueAt.After(now) {
+			continue
+		}
+		if appointment.CreatedAt.After(dueAt) {
+			return 0, false
+		}
+		return h, true
+	}
+	return 0, false
+}
+
+// retryClinic resends failed reminders whose retry is due, as long as the
+// appointment is still upcoming at the same time
+func (s *ReminderService) retryClinic(ctx context.Context, clinic *models.Clinic, now time.Time) (int, error) {
+	deliveries, err := s.deliveryRepo.ListDueRetries(ctx, clinic.ID, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		claimed, err := s.deliveryRepo.ClaimRetry(ctx, delivery.ID)
+		if err != nil {
+			s.log.Error("Failed to claim reminder retry", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		appointment, err := s.appointmentRepo.GetByID(ctx, delivery.AppointmentID, clinic.ID)
+		if err != nil || !appointment.StartTime.Equal(delivery.AppointmentStart) || !appointment.StartTime.After(now) ||
+			(appointment.Status != models.AppointmentStatusScheduled && appointment.Status != models.AppointmentStatusConfirmed) {
+			if err := s.deliveryRepo.Close(ctx, delivery.ID, "Appointment is no longer upcoming"); err != nil {
+				s.log.Error("Failed to close reminder", err)
+			}
+			continue
+		}
+
+		if s.deliver(ctx, clinic, appointment, delivery) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// deliver renders and sends one reminder and records the attempt. Failures
+// caused by the data (no contact, broken template) are final; delivery errors
+// are retried until maxAttempts or the appointment starts.
+func (s *ReminderService) deliver(ctx context.Context, clinic *models.Clinic, appointment *models.Appointment, delivery *models.ReminderDelivery) bool {
+	record := func(status, lastError string, next *time.Time) {
+		if err := s.deliveryRepo.RecordAttempt(ctx, delivery, status, lastError, next); err != nil {
+			s.log.Error("Failed to record reminder delivery", err)
+		}
+	}
+
+	patient, err := s.patientRepo.GetByID(ctx, appointment.PatientID, clinic.ID)
+	if err != nil {
+		record(models.ReminderStatusFailed, "Patient not found", nil)
+		return false
+	}
+
+	loc := clinic.Location()
+	start := appointment.StartTime.In(loc)
+	data := models.ReminderTemplateData{
+		PatientName: patient.FirstName + " " + patient.LastName,
+		ClinicName:  clinic.Name,
+		ClinicPhone: clinic.Phone,
+		Date:        start.Format("2006-01-02"),
+		Time:        start.Format("15:04"),
+	}
+	if doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, appointment.DoctorID, clinic.ID); err == nil {
+		data.DoctorName = doctor.FirstName + " " + doctor.LastName
+	}
+
+	body, err := models.RenderReminder(clinic.Reminders.Template, data)
+	if err != nil {
+		record(models.ReminderStatusFailed, err.Error(), nil)
+		return false
+	}
+	delivery.Body = body
+
+	channel, to := s.pickChannel(clinic.Reminders.Channels, patient)
+	if channel == "" {
+		delivery.Channel, delivery.To = "", ""
+		record(models.ReminderStatusFailed, "Patient has no contact for channels: "+strings.Join(clinic.Reminders.Channels, ", "), nil)
+		return false
+	}
+	delivery.Channel, delivery.To = channel, to
+
+	msg := notify.Message{
+		To:      to,
+		Subject: "Appointment reminder: " + clinic.Name,
+		Body:    body,
+	}
+	if err := s.notifiers[channel].Send(ctx, msg); err != nil {
+		var next *time.Time
+		if attempt := delivery.Attempts + 1; attempt < s.maxAttempts {
+			at := time.Now().UTC().Add(reminderRetryDelay * time.Duration(attempt))
+			if at.Before(appointment.StartTime) {
+				next = &at
+			}
+		}
+		record(models.ReminderStatusFailed, err.Error(), next)
+		return false
+	}
+
+	record(models.ReminderStatusSent, "", nil)
+	return true
+}
+
+// pickChannel returns the first configured channel the patient can be reached on
+func (s *ReminderService) pickChannel(channels []string, patient *models.Patient) (string, string) {
+	for _, ch := range channels {
+		if _, ok := s.notifiers[ch]; !ok {
+			continue
+		}
+		var to string
+		switch ch {
+		case models.ReminderChannelSMS:
+			to = patient.Phone
+		case models.ReminderChannelTelegram:
+			to = patient.Telegram
+		case models.ReminderChannelEmail:
+			to = patient.Email
+		}
+		if to != "" {
+			return ch, to
+		}
+	}
+	return "", ""
+}
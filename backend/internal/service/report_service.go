@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"sort"
 	"time"
 
+	"medical-crm/internal/models"
 	"medical-crm/internal/repository"
 	apperrors "medical-crm/pkg/errors"
 
@@ -15,9 +17,21 @@ type DailyReport struct {
 	Date           string          `json:"date"`
 	PatientsCount  int64           `json:"patients_count"`
 	VisitsCount    int             `json:"visits_count"`
-	TotalRevenue   float64         `json:"total_revenue"`
+	TotalRevenue   float64         `json:"total_revenue"` // Billed for the day's visits
 	TotalDiscount  float64         `json:"total_discount"`
 	DoctorEarnings []DoctorEarning `json:"doctor_earnings"`
+	// Billed vs collected
+	TotalBilled      float64            `json:"total_billed"`
+	TotalCollected   float64            `json:"total_collected"`   // Payments received that day, for any visit
+	TotalOutstanding float64            `json:"total_outstanding"` // Still owed for the day's visits
+	PaymentBreakdown map[string]float64 `json:"payment_breakdown"` // Collected per method, net of refunds
+	// Voids and refunds posted that day, for visits of any date
+	TotalReversed float64 `json:"total_reversed"`
+	TotalRefunded float64 `json:"total_refunded"`
+	NetRevenue    float64 `json:"net_revenue"` // TotalRevenue - TotalReversed
+	// Cash register shifts opened that day; open shifts show live expected cash
+	CashShifts      []models.CashShiftSummary `json:"cash_shifts"`
+	CashDiscrepancy float64                   `json:"cash_discrepancy"` // Sum over closed shifts; negative is a shortage
 }
 
 // MonthlyReport represents monthly statistics with financial summary
@@ -26,10 +40,22 @@ type MonthlyReport struct {
 	Month          int             `json:"month"`
 	PatientsCount  int64           `json:"patients_count"`
 	VisitsCount    int             `json:"visits_count"`
-	TotalRevenue   float64         `json:"total_revenue"`
+	TotalRevenue   float64         `json:"total_revenue"` // Billed for the month's visits
 	TotalDiscount  float64         `json:"total_discount"`
 	DoctorEarnings []DoctorEarning `json:"doctor_earnings"`
+	// Billed vs collected
+	TotalBilled      float64 `json:"total_billed"`
+	TotalCollected   float64 `json:"total_collected"`   // Payments received in the month, for any visit
+	TotalOutstanding float64 `json:"total_outstanding"` // Still owed by patients for the month's visits
+	// Insurance
+	TotalPayerShare      float64           `json:"total_payer_share"`     // Payers' part of the month's visits
+	InsuranceReceivables []PayerReceivable `json:"insurance_receivables"` // Per payer, including claims of earlier months still open
+	// Voids and refunds posted in the month, for visits of any date
+	TotalReversed float64 `json:"total_reversed"`
+	TotalRefunded float64 `json:"total_refunded"`
+	NetRevenue    float64 `json:"net_revenue"` // TotalRevenue - TotalReversed
 	// Financial summary
+	MaterialCosts       float64            `json:"material_costs"` // Materials and lab invoices of the month's visits
 	TotalExpenses       float64            `json:"total_expenses"`
 	ExpensesByCategory  map[string]float64 `json:"expenses_by_category"`
 	TotalSalaries       float64            `json:"total_salaries"`
@@ -37,45 +63,137 @@ type MonthlyReport struct {
 	GrossProfit         float64            `json:"gross_profit"`
 	NetProfit           float64            `json:"net_profit"`
 	// Payment breakdown
-	PaymentBreakdown map[string]float64 `json:"payment_breakdown"` // "cash" and "card" totals
+	PaymentBreakdown map[string]float64 `json:"payment_breakdown"` // Collected per method, net of refunds
+}
+
+// PayerReceivable is what an insurance payer was billed in the period and
+// owes overall
+type PayerReceivable struct {
+	PayerID     string  `json:"payer_id"`
+	PayerName   string  `json:"payer_name"`
+	Billed      float64 `json:"billed"`      // Payer share of the period's visits, net of reversals and rejections
+	Pending     float64 `json:"pending"`     // Open claims not yet submitted
+	Submitted   float64 `json:"submitted"`   // Submitted and awaiting payment
+	Outstanding float64 `json:"outstanding"` // Pending + Submitted
 }
 
 type DoctorEarning struct {
 	DoctorID   string  `json:"doctor_id"`
 	DoctorName string  `json:"doctor_name"`
 	Revenue    float64 `json:"revenue"`
-	Earning    float64 `json:"earning"`
+	Reversed   float64 `json:"reversed"` // Billing reversed in the period
+	Earning    float64 `json:"earning"`  // Net of claw-backs posted in the period
 	VisitCount int     `json:"visit_count"`
 }
 
+// PriceChangesReport lists the service price changes that took effect in a
+// period and what they did to revenue
+type PriceChangesReport struct {
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Changes     []PriceChange `json:"changes"`
+	TotalImpact float64       `json:"total_impact"`
+}
+
+// PriceChange is one price change with the sales of the service while the
+// new price was in effect (up to the next change or the end of the period).
+// Revenue is at list price, before visit discounts; lines snapshotted at the
+// old price in a draft count as sold at it.
+type PriceChange struct {
+	ServiceID     string  `json:"service_id"`
+	ServiceName   string  `json:"service_name"`
+	EffectiveFrom string  `json:"effective_from"`
+	EffectiveTo   string  `json:"effective_to"`
+	OldPrice      float64 `json:"old_price"`
+	NewPrice      float64 `json:"new_price"`
+	ChangePercent float64 `json:"change_percent"`
+	Reason        string  `json:"reason,omitempty"`
+	UnitsSold     int     `json:"units_sold"`
+	Revenue       float64 `json:"revenue"`
+	RevenueImpact float64 `json:"revenue_impact"` // Revenue minus the same units at the old price
+}
+
+// DoctorPayoutsReport shows, per doctor, earnings paid vs owed on closed
+// periods and earnings not yet in any payout statement
+type DoctorPayoutsReport struct {
+	Date          string         `json:"date"`
+	Doctors       []DoctorPayout `json:"doctors"`
+	TotalClosed   float64        `json:"total_closed"`
+	TotalPaid     float64        `json:"total_paid"`
+	TotalOwed     float64        `json:"total_owed"`
+	TotalUnclosed float64        `json:"total_unclosed"`
+}
+
+type DoctorPayout struct {
+	DoctorID   string  `json:"doctor_id"`
+	DoctorName string  `json:"doctor_name"`
+	ClosedTo   string  `json:"closed_to,omitempty"` // End of the latest closed period
+	Closed     float64 `json:"closed"`              // Earnings on closed statements
+	Paid       float64 `json:"paid"`
+	Owed       float64 `json:"owed"`     // Closed but not paid yet
+	Unclosed   float64 `json:"unclosed"` // Earned up to today outside any closed period
+}
+
 type ReportService struct {
-	visitRepo   *repository.VisitRepository
-	patientRepo *repository.PatientRepository
-	userRepo    *repository.UserRepository
-	expenseRepo *repository.ExpenseRepository
-	salaryRepo  *repository.StaffSalaryRepository
+	visitRepo      *repository.VisitRepository
+	patientRepo    *repository.PatientRepository
+	userRepo       *repository.UserRepository
+	clinicRepo     *repository.ClinicRepository
+	expenseRepo    *repository.ExpenseRepository
+	salaryRepo     *repository.StaffSalaryRepository
+	paymentRepo    *repository.PaymentRepository
+	adjustmentRepo *repository.VisitAdjustmentRepository
+	serviceRepo    *repository.ServiceRepository
+	priceRepo      *repository.ServicePriceRepository
+	payoutRepo     *repository.PayoutStatementRepository
+	payerRepo      *repository.InsurancePayerRepository
+	shiftService   *CashShiftService
+	clock          *ClinicClock
 }
 
 func NewReportService(
 	visitRepo *repository.VisitRepository,
 	patientRepo *repository.PatientRepository,
 	userRepo *repository.UserRepository,
+	clinicRepo *repository.ClinicRepository,
 	expenseRepo *repository.ExpenseRepository,
 	salaryRepo *repository.StaffSalaryRepository,
+	paymentRepo *repository.PaymentRepository,
+	adjustmentRepo *repository.VisitAdjustmentRepository,
+	serviceRepo *repository.ServiceRepository,
+	priceRepo *repository.ServicePriceRepository,
+	payoutRepo *repository.PayoutStatementRepository,
+	payerRepo *repository.InsurancePayerRepository,
+	shiftService *CashShiftService,
+	clock *ClinicClock,
 ) *ReportService {
 	return &ReportService{
-		visitRepo:   visitRepo,
-		patientRepo: patientRepo,
-		userRepo:    userRepo,
-		expenseRepo: expenseRepo,
-		salaryRepo:  salaryRepo,
+		visitRepo:      visitRepo,
+		patientRepo:    patientRepo,
+		userRepo:       userRepo,
+		clinicRepo:     clinicRepo,
+		expenseRepo:    expenseRepo,
+		salaryRepo:     salaryRepo,
+		paymentRepo:    paymentRepo,
+		adjustmentRepo: adjustmentRepo,
+		serviceRepo:    serviceRepo,
+		priceRepo:      priceRepo,
+		payoutRepo:     payoutRepo,
+		payerRepo:      payerRepo,
+		shiftService:   shiftService,
+		clock:          clock,
 	}
 }
 
-// GetDailyReport generates a daily report for a clinic
+// GetDailyReport generates a daily report for a clinic-local date (today if empty)
 func (s *ReportService) GetDailyReport(ctx context.Context, clinicID primitive.ObjectID, date string) (*DailyReport, error) {
-	// Get patients created today
-	patientsCount, err := s.patientRepo.CountByClinicAndDate(ctx, clinicID, date)
+	loc := s.clock.Location(ctx, clinicID)
+	if date == "" {
+		date = models.LocalDate(time.Now(), loc)
+	}
+
+	// Get patients created that day
+	patientsCount, err := s.patientRepo.CountByClinicAndDate(ctx, clinicID, date, loc)
 	if err != nil {
 		return nil, apperrors.InternalWithErr("Failed to count patients", err)
 	}
@@ -90,10 +208,12 @@ func (s *ReportService) GetDailyReport(ctx context.Context, clinicID primitive.O
 	doctorStats := make(map[string]*DoctorEarning)
 	totalRevenue := 0.0
 	totalDiscount := 0.0
+	totalOutstanding := 0.0
 
 	for _, v := range visits {
 		totalRevenue += v.Total
 		totalDiscount += v.DiscountAmount
+		totalOutstanding += v.Outstanding()
 
 		doctorID := v.DoctorID.Hex()
 		if _, exists := doctorStats[doctorID]; !exists {
@@ -106,6 +226,11 @@ func (s *ReportService) GetDailyReport(ctx context.Context, clinicID primitive.O
 		doctorStats[doctorID].VisitCount++
 	}
 
+	totalReversed, totalRefunded, err := s.reversals(ctx, clinicID, date, date, doctorStats)
+	if err != nil {
+		return nil, err
+	}
+
 	// Fetch doctor names
 	doctorEarnings := make([]DoctorEarning, 0, len(doctorStats))
 	for _, de := range doctorStats {
@@ -117,28 +242,54 @@ func (s *ReportService) GetDailyReport(ctx context.Context, clinicID primitive.O
 		doctorEarnings = append(doctorEarnings, *de)
 	}
 
+	paymentBreakdown, totalCollected, err := s.collected(ctx, clinicID, date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	shifts, cashDiscrepancy, err := s.shiftService.Summaries(ctx, clinicID, date)
+	if err != nil {
+		return nil, err
+	}
+
 	return &DailyReport{
-		Date:           date,
-		PatientsCount:  patientsCount,
-		VisitsCount:    len(visits),
-		TotalRevenue:   totalRevenue,
-		TotalDiscount:  totalDiscount,
-		DoctorEarnings: doctorEarnings,
+		Date:             date,
+		PatientsCount:    patientsCount,
+		VisitsCount:      len(visits),
+		TotalRevenue:     totalRevenue,
+		TotalDiscount:    totalDiscount,
+		DoctorEarnings:   doctorEarnings,
+		TotalBilled:      totalRevenue,
+		TotalCollected:   totalCollected,
+		TotalOutstanding: totalOutstanding,
+		PaymentBreakdown: paymentBreakdown,
+		TotalReversed:    totalReversed,
+		TotalRefunded:    totalRefunded,
+		NetRevenue:       totalRevenue - totalReversed,
+		CashShifts:       shifts,
+		CashDiscrepancy:  cashDiscrepancy,
 	}, nil
 }
 
-// GetMonthlyReport generates a monthly report for a clinic
+// GetMonthlyReport generates a monthly report for a clinic.
+// A zero year or month defaults to the clinic's current month.
 func (s *ReportService) GetMonthlyReport(ctx context.Context, clinicID primitive.ObjectID, year, month int) (*MonthlyReport, error) {
-	// Get visits for the month
+	if year == 0 || month == 0 {
+		now := time.Now().In(s.clock.Location(ctx, clinicID))
+		if year == 0 {
+			year = now.Year()
+		}
+		if month == 0 {
+			month = int(now.Month())
+		}
+	}
+
+	// Get visits for the month (bucketed by clinic-local visit date)
 	visits, err := s.visitRepo.ListByClinicAndMonth(ctx, clinicID, year, month)
 	if err != nil {
 		return nil, apperrors.InternalWithErr("Failed to get visits", err)
 	}
 
-	// Count patients created in the month
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0)
-
 	// Count patients (simplified - just use total for now)
 	patientsCount, err := s.patientRepo.CountByClinic(ctx, clinicID)
 	if err != nil {
@@ -147,23 +298,16 @@ func (s *ReportService) GetMonthlyReport(ctx context.Context, clinicID primitive
 
 	// Calculate totals and group by doctor
 	doctorStats := make(map[string]*DoctorEarning)
-	paymentBreakdown := map[string]float64{"cash": 0, "card": 0}
 	totalRevenue := 0.0
 	totalDiscount := 0.0
+	totalOutstanding := 0.0
+	materialCosts := 0.0
 
 	for _, v := range visits {
-		// Only count visits in the date range
-		if v.CreatedAt.Before(startDate) || v.CreatedAt.After(endDate) {
-			continue
-		}
-
 		totalRevenue += v.Total
 		totalDiscount += v.DiscountAmount
-
-		// Track payment types
-		if v.PaymentType != "" {
-			paymentBreakdown[v.PaymentType] += v.Total
-		}
+		totalOutstanding += v.Outstanding()
+		materialCosts += v.Costs
 
 		doctorID := v.DoctorID.Hex()
 		if _, exists := doctorStats[doctorID]; !exists {
@@ -176,6 +320,20 @@ func (s *ReportService) GetMonthlyReport(ctx context.Context, clinicID primitive
 		doctorStats[doctorID].VisitCount++
 	}
 
+	monthStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, -1)
+	fromDate, toDate := monthStart.Format("2006-01-02"), monthEnd.Format("2006-01-02")
+
+	totalReversed, totalRefunded, err := s.reversals(ctx, clinicID, fromDate, toDate, doctorStats)
+	if err != nil {
+		return nil, err
+	}
+
+	receivables, totalPayerShare, err := s.receivables(ctx, clinicID, visits)
+	if err != nil {
+		return nil, err
+	}
+
 	// Fetch doctor names and calculate total doctor earnings
 	doctorEarnings := make([]DoctorEarning, 0, len(doctorStats))
 	totalDoctorEarnings := 0.0
@@ -189,6 +347,12 @@ func (s *ReportService) GetMonthlyReport(ctx context.Context, clinicID primitive
 		doctorEarnings = append(doctorEarnings, *de)
 	}
 
+	// Money actually received in the month, from the payments ledger
+	paymentBreakdown, totalCollected, err := s.collected(ctx, clinicID, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+
 	// Fetch expenses for the month
 	expenses, err := s.expenseRepo.FindByClinicAndMonth(ctx, clinicID, year, month)
 	if err != nil {
@@ -217,24 +381,317 @@ func (s *ReportService) GetMonthlyReport(ctx context.Context, clinicID primitive
 		}
 	}
 
-	// Calculate profit
-	grossProfit := totalRevenue - totalDoctorEarnings
+	// Calculate profit on revenue net of reversals. Material costs stay
+	// incurred when a visit is voided.
+	netRevenue := totalRevenue - totalReversed
+	grossProfit := netRevenue - totalDoctorEarnings - materialCosts
 	netProfit := grossProfit - totalExpenses - totalSalaries
 
 	return &MonthlyReport{
-		Year:                year,
-		Month:               month,
-		PatientsCount:       patientsCount,
-		VisitsCount:         len(visits),
-		TotalRevenue:        totalRevenue,
-		TotalDiscount:       totalDiscount,
-		DoctorEarnings:      doctorEarnings,
-		TotalExpenses:       totalExpenses,
-		ExpensesByCategory:  expensesByCategory,
-		TotalSalaries:       totalSalaries,
-		TotalDoctorEarnings: totalDoctorEarnings,
-		GrossProfit:         grossProfit,
-		NetProfit:           netProfit,
-		PaymentBreakdown:    paymentBreakdown,
+		Year:                 year,
+		Month:                month,
+		PatientsCount:        patientsCount,
+		VisitsCount:          len(visits),
+		TotalRevenue:         totalRevenue,
+		TotalDiscount:        totalDiscount,
+		DoctorEarnings:       doctorEarnings,
+		TotalBilled:          totalRevenue,
+		TotalCollected:       totalCollected,
+		TotalOutstanding:     totalOutstanding,
+		TotalPayerShare:      totalPayerShare,
+		InsuranceReceivables: receivables,
+		TotalReversed:        totalReversed,
+		TotalRefunded:        totalRefunded,
+		NetRevenue:           netRevenue,
+		MaterialCosts:        materialCosts,
+		TotalExpenses:        totalExpenses,
+		ExpensesByCategory:   expensesByCategory,
+		TotalSalaries:        totalSalaries,
+		TotalDoctorEarnings:  totalDoctorEarnings,
+		GrossProfit:          grossProfit,
+		NetProfit:            netProfit,
+		PaymentBreakdown:     paymentBreakdown,
 	}, nil
 }
+
+// receivables totals the payers' share of the period's visits and every
+// claim still open, per payer
+func (s *ReportService) receivables(ctx context.Context, clinicID primitive.ObjectID, visits []models.Visit) ([]PayerReceivable, float64, error) {
+	byPayer := make(map[primitive.ObjectID]*PayerReceivable)
+	payer := func(id primitive.ObjectID) *PayerReceivable {
+		if r, ok := byPayer[id]; ok {
+			return r
+		}
+		r := &PayerReceivable{PayerID: id.Hex()}
+		byPayer[id] = r
+		return r
+	}
+
+	total := 0.0
+	for i := range visits {
+		v := &visits[i]
+		if v.Claim == nil {
+			continue
+		}
+		share := v.PayerShare()
+		payer(v.Claim.PayerID).Billed += share
+		total += share
+	}
+
+	open, err := s.visitRepo.ListClaims(ctx, clinicID, nil, models.ClaimStatusPending, models.ClaimStatusSubmitted)
+	if err != nil {
+		return nil, 0, apperrors.InternalWithErr("Failed to get insurance claims", err)
+	}
+	for i := range open {
+		v := &open[i]
+		switch v.Claim.Status {
+		case models.ClaimStatusPending:
+			payer(v.Claim.PayerID).Pending += v.PayerShare()
+		case models.ClaimStatusSubmitted:
+			payer(v.Claim.PayerID).Submitted += v.PayerShare()
+		}
+	}
+
+	names := make(map[primitive.ObjectID]string)
+	if payers, err := s.payerRepo.List(ctx, clinicID, false); err == nil {
+		for _, p := range payers {
+			names[p.ID] = p.Name
+		}
+	}
+
+	result := make([]PayerReceivable, 0, len(byPayer))
+	for id, r := range byPayer {
+		r.PayerName = names[id]
+		r.Billed = roundMoney(r.Billed)
+		r.Pending = roundMoney(r.Pending)
+		r.Submitted = roundMoney(r.Submitted)
+		r.Outstanding = roundMoney(r.Pending + r.Submitted)
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PayerName < result[j].PayerName })
+	return result, roundMoney(total), nil
+}
+
+// collected sums ledger payments per method over a clinic-local date range.
+// Every configured method is present, so clients can rely on the keys;
+// methods no longer configured still appear if they took money.
+func (s *ReportService) collected(ctx context.Context, clinicID primitive.ObjectID, fromDate, toDate string) (map[string]float64, float64, error) {
+	byMethod, err := s.paymentRepo.SumByMethod(ctx, clinicID, fromDate, toDate)
+	if err != nil {
+		return nil, 0, apperrors.InternalWithErr("Failed to sum payments", err)
+	}
+
+	methods := models.DefaultPaymentMethods()
+	if clinic, err := s.clinicRepo.GetByID(ctx, clinicID); err == nil {
+		methods = clinic.PaymentMethods()
+	}
+	breakdown := make(map[string]float64, len(methods))
+	for _, m := range methods {
+		breakdown[m.Code] = 0
+	}
+	total := 0.0
+	for method, amount := range byMethod {
+		breakdown[method] += amount
+		total += amount
+	}
+	return breakdown, total, nil
+}
+
+// reversals sums voids and refunds posted in a clinic-local date range and
+// takes the reversed billing and earning claw-back off each doctor's stats.
+// Doctors with reversals but no visits in the range are added.
+func (s *ReportService) reversals(ctx context.Context, clinicID primitive.ObjectID, fromDate, toDate string, doctorStats map[string]*DoctorEarning) (float64, float64, error) {
+	adjustments, err := s.adjustmentRepo.ListByDateRange(ctx, clinicID, fromDate, toDate)
+	if err != nil {
+		return 0, 0, apperrors.InternalWithErr("Failed to get visit adjustments", err)
+	}
+
+	reversed, refunded := 0.0, 0.0
+	for _, a := range adjustments {
+		reversed += a.Amount
+		refunded += a.RefundAmount
+
+		doctorID := a.DoctorID.Hex()
+		if _, exists := doctorStats[doctorID]; !exists {
+			doctorStats[doctorID] = &DoctorEarning{
+				DoctorID: doctorID,
+			}
+		}
+		doctorStats[doctorID].Reversed += a.Amount
+		doctorStats[doctorID].Earning -= a.EarningReversal
+	}
+	return reversed, refunded, nil
+}
+
+// GetPriceChangesReport reports price changes effective in a clinic-local
+// date range, by default the current month up to today
+func (s *ReportService) GetPriceChangesReport(ctx context.Context, clinicID primitive.ObjectID, fromDate, toDate string) (*PriceChangesReport, error) {
+	today := s.clock.Today(ctx, clinicID)
+	if toDate == "" {
+		toDate = today
+	}
+	if fromDate == "" {
+		fromDate = toDate[:8] + "01"
+	}
+	for _, d := range []string{fromDate, toDate} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, apperrors.BadRequest("Invalid date, expected YYYY-MM-DD")
+		}
+	}
+	if toDate < fromDate {
+		return nil, apperrors.BadRequest("to must not be before from")
+	}
+
+	prices, err := s.priceRepo.ListByClinic(ctx, clinicID, toDate)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to get service prices", err)
+	}
+	history := make(map[primitive.ObjectID][]models.ServicePrice)
+	for _, p := range prices {
+		history[p.ServiceID] = append(history[p.ServiceID], p)
+	}
+
+	report := &PriceChangesReport{From: fromDate, To: toDate, Changes: []PriceChange{}}
+	serviceIDs := []primitive.ObjectID{}
+	for serviceID, entries := range history {
+		for i := 1; i < len(entries); i++ {
+			entry := entries[i]
+			if entry.EffectiveFrom < fromDate {
+				continue
+			}
+			change := PriceChange{
+				ServiceID:     serviceID.Hex(),
+				EffectiveFrom: entry.EffectiveFrom,
+				EffectiveTo:   toDate,
+				OldPrice:      entries[i-1].Price,
+				NewPrice:      entry.Price,
+				Reason:        entry.Reason,
+			}
+			if i+1 < len(entries) {
+				next, _ := time.Parse("2006-01-02", entries[i+1].EffectiveFrom)
+				change.EffectiveTo = next.AddDate(0, 0, -1).Format("2006-01-02")
+			}
+			if change.OldPrice > 0 {
+				change.ChangePercent = roundMoney((change.NewPrice - change.OldPrice) / change.OldPrice * 100)
+			}
+			report.Changes = append(report.Changes, change)
+		}
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	if len(report.Changes) == 0 {
+		return report, nil
+	}
+
+	names := make(map[string]string)
+	if services, err := s.serviceRepo.GetMultipleByIDs(ctx, serviceIDs, clinicID); err == nil {
+		for _, svc := range services {
+			names[svc.ID.Hex()] = svc.Name
+		}
+	}
+
+	visits, err := s.visitRepo.ListCompletedBetween(ctx, clinicID, fromDate, toDate)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to get visits", err)
+	}
+
+	for i := range report.Changes {
+		change := &report.Changes[i]
+		change.ServiceName = names[change.ServiceID]
+		for _, v := range visits {
+			if v.Date < change.EffectiveFrom || v.Date > change.EffectiveTo {
+				continue
+			}
+			for _, line := range v.Services {
+				if line.ServiceID.Hex() != change.ServiceID {
+					continue
+				}
+				change.UnitsSold += line.Quantity
+				change.Revenue += line.Price * float64(line.Quantity)
+				change.RevenueImpact += (line.Price - change.OldPrice) * float64(line.Quantity)
+			}
+		}
+		change.Revenue = roundMoney(change.Revenue)
+		change.RevenueImpact = roundMoney(change.RevenueImpact)
+		report.TotalImpact += change.RevenueImpact
+	}
+	report.TotalImpact = roundMoney(report.TotalImpact)
+
+	sort.Slice(report.Changes, func(i, j int) bool {
+		if report.Changes[i].EffectiveFrom != report.Changes[j].EffectiveFrom {
+			return report.Changes[i].EffectiveFrom < report.Changes[j].EffectiveFrom
+		}
+		return report.Changes[i].ServiceName < report.Changes[j].ServiceName
+	})
+	return report, nil
+}
+
+// GetDoctorPayoutsReport reports doctor earnings paid vs owed as of today.
+// Unclosed earnings are all earnings up to today less those on statements,
+// so gaps between closed periods are included.
+func (s *ReportService) GetDoctorPayoutsReport(ctx context.Context, clinicID primitive.ObjectID) (*DoctorPayoutsReport, error) {
+	today := s.clock.Today(ctx, clinicID)
+
+	statements, err := s.payoutRepo.List(ctx, clinicID, nil)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to get payout statements", err)
+	}
+	earnings, err := s.visitRepo.SumEarningsByDoctor(ctx, clinicID, "", today)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to sum doctor earnings", err)
+	}
+	reversals, err := s.adjustmentRepo.SumEarningReversalsByDoctor(ctx, clinicID, "", today)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to sum earning reversals", err)
+	}
+
+	stats := make(map[primitive.ObjectID]*DoctorPayout)
+	stat := func(doctorID primitive.ObjectID) *DoctorPayout {
+		if _, ok := stats[doctorID]; !ok {
+			stats[doctorID] = &DoctorPayout{DoctorID: doctorID.Hex()}
+		}
+		return stats[doctorID]
+	}
+
+	for doctorID, earning := range earnings {
+		stat(doctorID).Unclosed += earning
+	}
+	for doctorID, reversal := range reversals {
+		stat(doctorID).Unclosed -= reversal
+	}
+	for _, st := range statements {
+		d := stat(st.DoctorID)
+		d.Closed += st.Earning
+		d.Paid += st.PaidAmount
+		d.Owed += st.Owed()
+		d.Unclosed -= st.Earning
+		if st.PeriodTo > d.ClosedTo {
+			d.ClosedTo = st.PeriodTo
+		}
+	}
+
+	report := &DoctorPayoutsReport{Date: today, Doctors: make([]DoctorPayout, 0, len(stats))}
+	for doctorID, d := range stats {
+		if doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID); err == nil {
+			d.DoctorName = doctor.FirstName + " " + doctor.LastName
+		}
+		d.Closed = roundMoney(d.Closed)
+		d.Paid = roundMoney(d.Paid)
+		d.Owed = roundMoney(d.Owed)
+		d.Unclosed = roundMoney(d.Unclosed)
+
+		report.TotalClosed += d.Closed
+		report.TotalPaid += d.Paid
+		report.TotalOwed += d.Owed
+		report.TotalUnclosed += d.Unclosed
+		report.Doctors = append(report.Doctors, *d)
+	}
+	report.TotalClosed = roundMoney(report.TotalClosed)
+	report.TotalPaid = roundMoney(report.TotalPaid)
+	report.TotalOwed = roundMoney(report.TotalOwed)
+	report.TotalUnclosed = roundMoney(report.TotalUnclosed)
+
+	sort.Slice(report.Doctors, func(i, j int) bool {
+		return report.Doctors[i].DoctorName < report.Doctors[j].DoctorName
+	})
+	return report, nil
+}
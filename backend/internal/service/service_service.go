@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -10,13 +12,24 @@ import (
 	apperrors "medical-crm/pkg/errors"
 )
 
+// ServiceService manages the clinic's service catalogue. Prices are versioned:
+// every change is kept in the price history with the date it takes effect,
+// and Service.Price mirrors the latest change already in effect.
 type ServiceService struct {
 	serviceRepo *repository.ServiceRepository
+	priceRepo   *repository.ServicePriceRepository
+	clock       *ClinicClock
 }
 
-func NewServiceService(serviceRepo *repository.ServiceRepository) *ServiceService {
+func NewServiceService(
+	serviceRepo *repository.ServiceRepository,
+	priceRepo *repository.ServicePriceRepository,
+	clock *ClinicClock,
+) *ServiceService {
 	return &ServiceService{
 		serviceRepo: serviceRepo,
+		priceRepo:   priceRepo,
+		clock:       clock,
 	}
 }
 
@@ -26,7 +39,9 @@ func (s *ServiceService) Create(ctx context.Context, dto models.CreateServiceDTO
 		ClinicID:    clinicID,
 		Name:        dto.Name,
 		Description: dto.Description,
+		Category:    strings.TrimSpace(dto.Category),
 		Price:       dto.Price,
+		Cost:        dto.Cost,
 		Duration:    dto.Duration,
 		CreatedBy:   creatorID,
 	}
@@ -42,24 +57,42 @@ func (s *ServiceService) Create(ctx context.Context, dto models.CreateServiceDTO
 		return nil, apperrors.InternalWithErr("Failed to create service", err)
 	}
 
+	initial := &models.ServicePrice{
+		ClinicID:      clinicID,
+		ServiceID:     service.ID,
+		Price:         service.Price,
+		EffectiveFrom: s.clock.Today(ctx, clinicID),
+		CreatedBy:     creatorID,
+	}
+	if err := s.priceRepo.Set(ctx, initial); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to record service price", err)
+	}
+
 	return service, nil
 }
 
-// GetByID retrieves a service by ID
+// GetByID retrieves a service by ID with today's price
 func (s *ServiceService) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.Service, error) {
 	service, err := s.serviceRepo.GetByID(ctx, id, clinicID)
 	if err != nil {
 		return nil, apperrors.NotFound("Service")
 	}
-	return service, nil
+	services := []models.Service{*service}
+	if err := applyPricesAt(ctx, s.priceRepo, clinicID, s.clock.Today(ctx, clinicID), services); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to resolve service price", err)
+	}
+	return &services[0], nil
 }
 
-// List returns all services for a clinic
+// List returns all services for a clinic with today's prices
 func (s *ServiceService) List(ctx context.Context, clinicID primitive.ObjectID, activeOnly bool) ([]models.ServiceResponse, error) {
 	services, err := s.serviceRepo.List(ctx, clinicID, activeOnly)
 	if err != nil {
 		return nil, apperrors.InternalWithErr("Failed to list services", err)
 	}
+	if err := applyPricesAt(ctx, s.priceRepo, clinicID, s.clock.Today(ctx, clinicID), services); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to resolve service prices", err)
+	}
 
 	responses := make([]models.ServiceResponse, len(services))
 	for i, svc := range services {
@@ -69,11 +102,12 @@ func (s *ServiceService) List(ctx context.Context, clinicID primitive.ObjectID,
 	return responses, nil
 }
 
-// Update updates a service
-func (s *ServiceService) Update(ctx context.Context, id, clinicID primitive.ObjectID, dto models.UpdateServiceDTO) (*models.Service, error) {
-	service, err := s.serviceRepo.GetByID(ctx, id, clinicID)
+// Update updates a service. A new price takes effect today; use SchedulePrice
+// for a later date.
+func (s *ServiceService) Update(ctx context.Context, id, clinicID, updaterID primitive.ObjectID, dto models.UpdateServiceDTO) (*models.Service, error) {
+	service, err := s.GetByID(ctx, id, clinicID)
 	if err != nil {
-		return nil, apperrors.NotFound("Service")
+		return nil, err
 	}
 
 	if dto.Name != "" {
@@ -82,8 +116,11 @@ func (s *ServiceService) Update(ctx context.Context, id, clinicID primitive.Obje
 	if dto.Description != "" {
 		service.Description = dto.Description
 	}
-	if dto.Price != nil {
-		service.Price = *dto.Price
+	if dto.Category != nil {
+		service.Category = strings.TrimSpace(*dto.Category)
+	}
+	if dto.Cost != nil {
+		service.Cost = *dto.Cost
 	}
 	if dto.Duration != nil {
 		service.Duration = *dto.Duration
@@ -92,6 +129,14 @@ func (s *ServiceService) Update(ctx context.Context, id, clinicID primitive.Obje
 		service.IsActive = *dto.IsActive
 	}
 
+	if dto.Price != nil && *dto.Price != service.Price {
+		change := models.ScheduleServicePriceDTO{Price: *dto.Price}
+		if _, err := s.setPrice(ctx, service, updaterID, change, s.clock.Today(ctx, clinicID)); err != nil {
+			return nil, err
+		}
+		service.Price = *dto.Price
+	}
+
 	if err := s.serviceRepo.Update(ctx, service); err != nil {
 		return nil, apperrors.InternalWithErr("Failed to update service", err)
 	}
@@ -108,3 +153,142 @@ func (s *ServiceService) Delete(ctx context.Context, id, clinicID primitive.Obje
 
 	return s.serviceRepo.Delete(ctx, id, clinicID)
 }
+
+// SchedulePrice records a price change effective today or on a later date.
+// A change already scheduled for the same date is replaced.
+func (s *ServiceService) SchedulePrice(ctx context.Context, id, clinicID, actorID primitive.ObjectID, dto models.ScheduleServicePriceDTO) (*models.ServicePrice, error) {
+	service, err := s.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := s.clock.Today(ctx, clinicID)
+	if dto.EffectiveFrom == "" {
+		dto.EffectiveFrom = today
+	}
+	if _, err := time.Parse("2006-01-02", dto.EffectiveFrom); err != nil {
+		return nil, apperrors.BadRequest("Invalid effective_from, expected YYYY-MM-DD")
+	}
+	if dto.EffectiveFrom < today {
+		return nil, apperrors.BadRequest("Price changes cannot be backdated")
+	}
+
+	price, err := s.setPrice(ctx, service, actorID, dto, today)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.EffectiveFrom == today && service.Price != dto.Price {
+		service.Price = dto.Price
+		if err := s.serviceRepo.Update(ctx, service); err != nil {
+			return nil, apperrors.InternalWithErr("Failed to update service", err)
+		}
+	}
+	return price, nil
+}
+
+// ListPrices returns a service's price history, newest first, marking the
+// price in effect today
+func (s *ServiceService) ListPrices(ctx context.Context, id, clinicID primitive.ObjectID) ([]models.ServicePriceResponse, error) {
+	if _, err := s.serviceRepo.GetByID(ctx, id, clinicID); err != nil {
+		return nil, apperrors.NotFound("Service")
+	}
+
+	prices, err := s.priceRepo.ListByService(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list service prices", err)
+	}
+
+	today := s.clock.Today(ctx, clinicID)
+	current := false
+	responses := make([]models.ServicePriceResponse, len(prices))
+	for i, p := range prices {
+		responses[i] = p.ToResponse()
+		if !current && p.EffectiveFrom <= today {
+			responses[i].Current = true
+			current = true
+		}
+	}
+	return responses, nil
+}
+
+// DeleteScheduledPrice cancels a price change that has not taken effect yet
+func (s *ServiceService) DeleteScheduledPrice(ctx context.Context, id, priceID, clinicID primitive.ObjectID) error {
+	price, err := s.priceRepo.GetByID(ctx, priceID, id, clinicID)
+	if err != nil {
+		return apperrors.NotFound("Service price")
+	}
+	if price.EffectiveFrom <= s.clock.Today(ctx, clinicID) {
+		return apperrors.BadRequest("Only price changes that have not taken effect can be deleted")
+	}
+
+	if err := s.priceRepo.Delete(ctx, price.ID, clinicID); err != nil {
+		return apperrors.InternalWithErr("Failed to delete service price", err)
+	}
+	return nil
+}
+
+// setPrice stores a price change. Services created before prices were
+// versioned have no history yet, so their current price is recorded first,
+// effective from when the service was created.
+func (s *ServiceService) setPrice(ctx context.Context, service *models.Service, actorID primitive.ObjectID, dto models.ScheduleServicePriceDTO, today string) (*models.ServicePrice, error) {
+	if dto.EffectiveFrom == "" {
+		dto.EffectiveFrom = today
+	}
+
+	history, err := s.priceRepo.ListByService(ctx, service.ID, service.ClinicID)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to load service prices", err)
+	}
+	if len(history) == 0 {
+		since := s.clock.DateOf(ctx, service.ClinicID, service.CreatedAt)
+		if since < dto.EffectiveFrom {
+			baseline := &models.ServicePrice{
+				ClinicID:      service.ClinicID,
+				ServiceID:     service.ID,
+				Price:         service.Price,
+				EffectiveFrom: since,
+				CreatedBy:     service.CreatedBy,
+			}
+			if err := s.priceRepo.Set(ctx, baseline); err != nil {
+				return nil, apperrors.InternalWithErr("Failed to record service price", err)
+			}
+		}
+	}
+
+	price := &models.ServicePrice{
+		ClinicID:      service.ClinicID,
+		ServiceID:     service.ID,
+		Price:         dto.Price,
+		EffectiveFrom: dto.EffectiveFrom,
+		Reason:        dto.Reason,
+		CreatedBy:     actorID,
+	}
+	if err := s.priceRepo.Set(ctx, price); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to record service price", err)
+	}
+	return price, nil
+}
+
+// applyPricesAt sets each service's Price to the price in effect on date.
+// Services without a price history keep their stored price.
+func applyPricesAt(ctx context.Context, priceRepo *repository.ServicePriceRepository, clinicID primitive.ObjectID, date string, services []models.Service) error {
+	if len(services) == 0 {
+		return nil
+	}
+	ids := make([]primitive.ObjectID, len(services))
+	for i := range services {
+		ids[i] = services[i].ID
+	}
+
+	prices, err := priceRepo.PricesAt(ctx, clinicID, ids, date)
+	if err != nil {
+		return err
+	}
+	for i := range services {
+		if price, ok := prices[services[i].ID]; ok {
+			services[i].Price = price
+		}
+	}
+	return nil
+}
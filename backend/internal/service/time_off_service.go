@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TimeOffService manages clinic closures and doctor leaves
+type TimeOffService struct {
+	timeOffRepo     *repository.TimeOffRepository
+	appointmentRepo *repository.AppointmentRepository
+	userRepo        *repository.UserRepository
+	clock           *ClinicClock
+}
+
+func NewTimeOffService(
+	timeOffRepo *repository.TimeOffRepository,
+	appointmentRepo *repository.AppointmentRepository,
+	userRepo *repository.UserRepository,
+	clock *ClinicClock,
+) *TimeOffService {
+	return &TimeOffService{
+		timeOffRepo:     timeOffRepo,
+		appointmentRepo: appointmentRepo,
+		userRepo:        userRepo,
+		clock:           clock,
+	}
+}
+
+// CreateClosure closes the clinic on whole clinic-local days and returns
+// the appointments that fall into the closure
+func (s *TimeOffService) CreateClosure(ctx context.Context, clinicID, createdBy primitive.ObjectID, dto models.CreateClosureDTO) (*models.TimeOff, []models.Appointment, error) {
+	if dto.EndDate == "" {
+		dto.EndDate = dto.StartDate
+	}
+
+	loc := s.clock.Location(ctx, clinicID)
+	start, err := time.ParseInLocation("2006-01-02", dto.StartDate, loc)
+	if err != nil {
+		return nil, nil, apperrors.BadRequest("Invalid start_date, expected YYYY-MM-DD")
+	}
+	end, err := time.ParseInLocation("2006-01-02", dto.EndDate, loc)
+	if err != nil {
+		return nil, nil, apperrors.BadRequest("Invalid end_date, expected YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return nil, nil, apperrors.BadRequest("end_date must not be before start_date")
+	}
+
+	entry := &models.TimeOff{
+		ClinicID:  clinicID,
+		Type:      models.TimeOffTypeClosure,
+		StartTime: start.UTC(),
+		EndTime:   end.AddDate(0, 0, 1).UTC(),
+		Reason:    dto.Reason,
+		CreatedBy: createdBy,
+	}
+
+	if err := s.timeOffRepo.Create(ctx, entry); err != nil {
+		return nil, nil, apperrors.InternalWithErr("Failed to create closure", err)
+	}
+
+	affected, err := s.appointmentRepo.ListByInterval(ctx, clinicID, nil, entry.StartTime, entry.EndTime)
+	if err != nil {
+		return nil, nil, apperrors.InternalWithErr("Failed to list affected appointments", err)
+	}
+
+	return entry, affected, nil
+}
+
+// CreateLeave records a doctor's leave and returns the doctor's appointments
+// that fall into it so reception can move them
+func (s *TimeOffService) CreateLeave(ctx context.Context, clinicID, createdBy primitive.ObjectID, dto models.CreateLeaveDTO) (*models.TimeOff, []models.Appointment, error) {
+	doctorID, err := primitive.ObjectIDFromHex(dto.DoctorID)
+	if err != nil {
+		return nil, nil, apperrors.BadRequest("Invalid doctor ID")
+	}
+
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID)
+	if err != nil || doctor.Role != models.RoleDoctor {
+		return nil, nil, apperrors.NotFound("Doctor")
+	}
+
+	if !dto.EndTime.After(dto.StartTime) {
+		return nil, nil, apperrors.BadRequest("end_time must be after start_time")
+	}
+
+	entry := &models.TimeOff{
+		ClinicID:  clinicID,
+		Type:      models.TimeOffTypeLeave,
+		DoctorID:  &doctorID,
+		StartTime: dto.StartTime.UTC(),
+		EndTime:   dto.EndTime.UTC(),
+		Reason:    dto.Reason,
+		CreatedBy: createdBy,
+	}
+
+	if err := s.timeOffRepo.Create(ctx, entry); err != nil {
+		return nil, nil, apperrors.InternalWithErr("Failed to create leave", err)
+	}
+
+	affected, err := s.appointmentRepo.ListByInterval(ctx, clinicID, &doctorID, entry.StartTime, entry.EndTime)
+	if err != nil {
+		return nil, nil, apperrors.InternalWithErr("Failed to list affected appointments", err)
+	}
+
+	return entry, affected, nil
+}
+
+// List returns upcoming and current entries of the given type (all if empty)
+func (s *TimeOffService) List(ctx context.Context, clinicID primitive.ObjectID, entryType string) ([]models.TimeOff, error) {
+	entries, err := s.timeOffRepo.ListByClinic(ctx, clinicID, entryType, time.Now().UTC())
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list closures and leaves", err)
+	}
+	return entries, nil
+}
+
+// Delete removes a closure or leave of the given type
+func (s *TimeOffService) Delete(ctx context.Context, id, clinicID primitive.ObjectID, entryType string) error {
+	entry, err := s.timeOffRepo.GetByID(ctx, id, clinicID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return apperrors.InternalWithErr("Failed to get entry", err)
+	}
+	if err == mongo.ErrNoDocuments || entry.Type != entryType {
+		if entryType == models.TimeOffTypeClosure {
+			return apperrors.NotFound("Closure")
+		}
+		return apperrors.NotFound("Leave")
+	}
+
+	if err := s.timeOffRepo.Delete(ctx, id, clinicID); err != nil {
+		return apperrors.InternalWithErr("Failed to delete entry", err)
+	}
+	return nil
+}
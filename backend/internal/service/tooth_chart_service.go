@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ToothChartService serves patients' tooth charts (odontograms) and
+// per-tooth treatment history. The chart is a timeline of changes made
+// during visits (see VisitService), replayed up to the requested date.
+type ToothChartService struct {
+	chartRepo   *repository.ToothChartRepository
+	patientRepo *repository.PatientRepository
+	visitRepo   *repository.VisitRepository
+	userRepo    *repository.UserRepository
+	clock       *ClinicClock
+}
+
+func NewToothChartService(
+	chartRepo *repository.ToothChartRepository,
+	patientRepo *repository.PatientRepository,
+	visitRepo *repository.VisitRepository,
+	userRepo *repository.UserRepository,
+	clock *ClinicClock,
+) *ToothChartService {
+	return &ToothChartService{
+		chartRepo:   chartRepo,
+		patientRepo: patientRepo,
+		visitRepo:   visitRepo,
+		userRepo:    userRepo,
+		clock:       clock,
+	}
+}
+
+// GetChart returns the patient's chart as of a clinic-local date, today by
+// default
+func (s *ToothChartService) GetChart(ctx context.Context, patientID, clinicID primitive.ObjectID, date string) (*models.ToothChartResponse, error) {
+	if _, err := s.patientRepo.GetByID(ctx, patientID, clinicID); err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+	if date == "" {
+		date = s.clock.Today(ctx, clinicID)
+	} else if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, apperrors.BadRequest("Invalid date, expected YYYY-MM-DD")
+	}
+
+	entries, err := s.chartRepo.ListByPatient(ctx, clinicID, patientID, date, "")
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to load tooth chart", err)
+	}
+	return &models.ToothChartResponse{
+		PatientID: patientID.Hex(),
+		Date:      date,
+		Teeth:     models.BuildToothChart(entries),
+	}, nil
+}
+
+// History returns the patient's chart changes, oldest first, optionally of
+// one tooth
+func (s *ToothChartService) History(ctx context.Context, patientID, clinicID primitive.ObjectID, tooth string) ([]models.ToothChartEntry, error) {
+	if tooth != "" && !models.ValidTooth(tooth) {
+		return nil, apperrors.BadRequest("Invalid tooth number: " + tooth)
+	}
+	if _, err := s.patientRepo.GetByID(ctx, patientID, clinicID); err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+
+	entries, err := s.chartRepo.ListByPatient(ctx, clinicID, patientID, "", tooth)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to load tooth chart history", err)
+	}
+	if entries == nil {
+		entries = []models.ToothChartEntry{}
+	}
+	return entries, nil
+}
+
+// ToothHistory returns the service lines done on a tooth and the tooth's
+// chart changes
+func (s *ToothChartService) ToothHistory(ctx context.Context, patientID, clinicID primitive.ObjectID, tooth string) (*models.ToothHistoryResponse, error) {
+	if !models.ValidTooth(tooth) {
+		return nil, apperrors.BadRequest("Invalid tooth number: " + tooth)
+	}
+	chart, err := s.History(ctx, patientID, clinicID, tooth)
+	if err != nil {
+		return nil, err
+	}
+
+	visits, err := s.visitRepo.ListByPatientTooth(ctx, clinicID, patientID, tooth)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to load tooth history", err)
+	}
+
+	doctors := make(map[primitive.ObjectID]string)
+	treatments := []models.ToothTreatment{}
+	for _, v := range visits {
+		name, ok := doctors[v.DoctorID]
+		if !ok {
+			if doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, v.DoctorID, clinicID); err == nil {
+				name = doctor.FirstName + " " + doctor.LastName
+			}
+			doctors[v.DoctorID] = name
+		}
+		for _, line := range v.Services {
+			if !containsTooth(line.Teeth, tooth) {
+				continue
+			}
+			treatments = append(treatments, models.ToothTreatment{
+				VisitID:     v.ID.Hex(),
+				Date:        v.Date,
+				Status:      v.Status,
+				DoctorID:    v.DoctorID.Hex(),
+				DoctorName:  name,
+				ServiceID:   line.ServiceID.Hex(),
+				ServiceName: line.ServiceName,
+				Surfaces:    line.Surfaces,
+				Diagnosis:   v.Diagnosis,
+			})
+		}
+	}
+
+	return &models.ToothHistoryResponse{
+		PatientID:  patientID.Hex(),
+		Tooth:      tooth,
+		Treatments: treatments,
+		Chart:      chart,
+	}, nil
+}
+
+func containsTooth(teeth []string, tooth string) bool {
+	for _, t := range teeth {
+		if t == tooth {
+			return true
+		}
+	}
+	return false
+}
+
+// toothNumbers validates FDI tooth numbers, dropping duplicates
+func toothNumbers(raw []string) ([]string, error) {
+	var teeth []string
+	for _, t := range raw {
+		t = strings.TrimSpace(t)
+		if !models.ValidTooth(t) {
+			return nil, apperrors.BadRequest("Invalid tooth number: " + t)
+		}
+		if !containsTooth(teeth, t) {
+			teeth = append(teeth, t)
+		}
+	}
+	return teeth, nil
+}
+
+// chartChanges validates chart changes submitted with a visit
+func chartChanges(dtos []models.ToothChartChangeDTO) ([]models.ToothChartChange, error) {
+	changes := make([]models.ToothChartChange, 0, len(dtos))
+	for _, dto := range dtos {
+		if !models.ValidTooth(dto.Tooth) {
+			return nil, apperrors.BadRequest("Invalid tooth number: " + dto.Tooth)
+		}
+		surfaces, err := toothSurfaces(dto.Surfaces)
+		if err != nil {
+			return nil, err
+		}
+		if len(surfaces) > 0 && !models.SurfaceCondition(dto.Condition) {
+			return nil, apperrors.BadRequest("Condition " + dto.Condition + " applies to the whole tooth")
+		}
+		changes = append(changes, models.ToothChartChange{
+			Tooth:     dto.Tooth,
+			Surfaces:  surfaces,
+			Condition: dto.Condition,
+			Note:      strings.TrimSpace(dto.Note),
+		})
+	}
+	return changes, nil
+}
+
+// toothSurfaces validates surface codes, normalizing case and dropping
+// duplicates
+func toothSurfaces(raw []string) ([]string, error) {
+	var surfaces []string
+	seen := make(map[string]bool)
+	for _, s := range raw {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if !models.ValidSurface(s) {
+			return nil, apperrors.BadRequest("Invalid tooth surface: " + s)
+		}
+		if !seen[s] {
+			seen[s] = true
+			surfaces = append(surfaces, s)
+		}
+	}
+	return surfaces, nil
+}
+
+// chartEntries turns a visit's chart changes into timeline entries
+func chartEntries(visit *models.Visit) []models.ToothChartEntry {
+	entries := make([]models.ToothChartEntry, 0, len(visit.ChartChanges))
+	for i, c := range visit.ChartChanges {
+		entries = append(entries, models.ToothChartEntry{
+			ClinicID:  visit.ClinicID,
+			PatientID: visit.PatientID,
+			VisitID:   visit.ID,
+			DoctorID:  visit.DoctorID,
+			Date:      visit.Date,
+			Seq:       i,
+			Tooth:     c.Tooth,
+			Surfaces:  c.Surfaces,
+			Condition: c.Condition,
+			Note:      c.Note,
+		})
+	}
+	return entries
+}
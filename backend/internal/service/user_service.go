@@ -183,3 +183,41 @@ func (s *UserService) CreateSuperadmin(ctx context.Context, email, password stri
 
 	return user, nil
 }
+
+// GetDoctorSchedule returns a doctor's weekly working schedule
+func (s *UserService) GetDoctorSchedule(ctx context.Context, doctorID, clinicID primitive.ObjectID) (*models.WorkSchedule, error) {
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID)
+	if err != nil || doctor.Role != models.RoleDoctor {
+		return nil, apperrors.NotFound("Doctor")
+	}
+
+	if doctor.WorkSchedule == nil {
+		return &models.WorkSchedule{Days: []models.WorkDay{}}, nil
+	}
+	return doctor.WorkSchedule, nil
+}
+
+// UpdateDoctorSchedule replaces a doctor's weekly working schedule
+func (s *UserService) UpdateDoctorSchedule(ctx context.Context, doctorID, clinicID primitive.ObjectID, dto models.UpdateWorkScheduleDTO) (*models.WorkSchedule, error) {
+	doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID)
+	if err != nil || doctor.Role != models.RoleDoctor {
+		return nil, apperrors.NotFound("Doctor")
+	}
+
+	schedule := &models.WorkSchedule{
+		Days:      dto.Days,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if schedule.Days == nil {
+		schedule.Days = []models.WorkDay{}
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, apperrors.Validation(err.Error())
+	}
+
+	if err := s.userRepo.UpdateWorkSchedule(ctx, doctorID, clinicID, schedule); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to update doctor schedule", err)
+	}
+
+	return schedule, nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"medical-crm/internal/models"
@@ -17,8 +19,16 @@ type VisitService struct {
 	appointmentRepo *repository.AppointmentRepository
 	patientRepo     *repository.PatientRepository
 	serviceRepo     *repository.ServiceRepository
+	priceRepo       *repository.ServicePriceRepository
 	userRepo        *repository.UserRepository
+	clinicRepo      *repository.ClinicRepository
 	contractRepo    *repository.DoctorContractRepository
+	counterRepo     *repository.CounterRepository
+	payoutRepo      *repository.PayoutStatementRepository
+	payerRepo       *repository.InsurancePayerRepository
+	inventory       *InventoryService
+	chartRepo       *repository.ToothChartRepository
+	clock           *ClinicClock
 }
 
 func NewVisitService(
@@ -26,16 +36,32 @@ func NewVisitService(
 	appointmentRepo *repository.AppointmentRepository,
 	patientRepo *repository.PatientRepository,
 	serviceRepo *repository.ServiceRepository,
+	priceRepo *repository.ServicePriceRepository,
 	userRepo *repository.UserRepository,
+	clinicRepo *repository.ClinicRepository,
 	contractRepo *repository.DoctorContractRepository,
+	counterRepo *repository.CounterRepository,
+	payoutRepo *repository.PayoutStatementRepository,
+	payerRepo *repository.InsurancePayerRepository,
+	inventory *InventoryService,
+	chartRepo *repository.ToothChartRepository,
+	clock *ClinicClock,
 ) *VisitService {
 	return &VisitService{
 		visitRepo:       visitRepo,
 		appointmentRepo: appointmentRepo,
 		patientRepo:     patientRepo,
 		serviceRepo:     serviceRepo,
+		priceRepo:       priceRepo,
 		userRepo:        userRepo,
+		clinicRepo:      clinicRepo,
 		contractRepo:    contractRepo,
+		counterRepo:     counterRepo,
+		payoutRepo:      payoutRepo,
+		payerRepo:       payerRepo,
+		inventory:       inventory,
+		chartRepo:       chartRepo,
+		clock:           clock,
 	}
 }
 
@@ -47,7 +73,7 @@ func (s *VisitService) StartVisit(ctx context.Context, dto models.StartVisitDTO,
 	}
 
 	// Verify patient exists
-	_, err = s.patientRepo.GetByID(ctx, patientID, clinicID)
+	patient, err := s.patientRepo.GetByID(ctx, patientID, clinicID)
 	if err != nil {
 		return nil, apperrors.NotFound("Patient")
 	}
@@ -56,7 +82,13 @@ func (s *VisitService) StartVisit(ctx context.Context, dto models.StartVisitDTO,
 		ClinicID:  clinicID,
 		PatientID: patientID,
 		DoctorID:  doctorID,
+		Date:      s.clock.Today(ctx, clinicID),
 		Services:  []models.VisitService{},
+		// Snapshot so the visit records what the doctor was warned about
+		MedicalAlerts: patient.MedicalHistory.CriticalAlerts(),
+	}
+	if err := ensurePeriodOpen(ctx, s.payoutRepo, clinicID, doctorID, visit.Date); err != nil {
+		return nil, err
 	}
 
 	// Link to appointment if provided
@@ -77,11 +109,15 @@ func (s *VisitService) StartVisit(ctx context.Context, dto models.StartVisitDTO,
 			return nil, apperrors.Forbidden("This appointment is for another doctor")
 		}
 
+		if !models.CanTransitionAppointment(appointment.Status, models.AppointmentStatusInProgress) {
+			return nil, apperrors.InvalidTransition(fmt.Sprintf("Cannot start a visit for a %s appointment", appointment.Status))
+		}
+
 		visit.AppointmentID = &appointmentID
 
 		// Update appointment status to in_progress
-		if err := s.appointmentRepo.UpdateStatus(ctx, appointmentID, clinicID, models.AppointmentStatusInProgress); err != nil {
-			return nil, apperrors.InternalWithErr("Failed to update appointment status", err)
+		if err := s.appointmentRepo.TransitionStatus(ctx, appointmentID, clinicID, appointment.Status, models.AppointmentStatusInProgress, doctorID); err != nil {
+			return nil, transitionError(err, "Failed to update appointment status")
 		}
 	}
 
@@ -92,6 +128,11 @@ func (s *VisitService) StartVisit(ctx context.Context, dto models.StartVisitDTO,
 	return visit, nil
 }
 
+// Today returns the current date in the clinic's time zone
+func (s *VisitService) Today(ctx context.Context, clinicID primitive.ObjectID) string {
+	return s.clock.Today(ctx, clinicID)
+}
+
 // GetByID retrieves a visit by ID
 func (s *VisitService) GetByID(ctx context.Context, id, clinicID primitive.ObjectID) (*models.Visit, error) {
 	visit, err := s.visitRepo.GetByID(ctx, id, clinicID)
@@ -101,7 +142,10 @@ func (s *VisitService) GetByID(ctx context.Context, id, clinicID primitive.Objec
 	return visit, nil
 }
 
-// CompleteVisit completes a visit with diagnosis and services
+// CompleteVisit completes a visit with diagnosis and services. The visit is
+// billed but unpaid; money received is recorded in the payments ledger.
+// Reductions below list price beyond the clinic's discount policy hold the
+// visit for boss approval instead.
 func (s *VisitService) CompleteVisit(ctx context.Context, id, clinicID primitive.ObjectID, dto models.CompleteVisitDTO) (*models.Visit, error) {
 	visit, err := s.visitRepo.GetByID(ctx, id, clinicID)
 	if err != nil {
@@ -111,19 +155,29 @@ func (s *VisitService) CompleteVisit(ctx context.Context, id, clinicID primitive
 	if visit.Status == models.VisitStatusCompleted {
 		return nil, apperrors.BadRequest("Visit is already completed")
 	}
+	if visit.Status == models.VisitStatusPendingApproval {
+		return nil, apperrors.BadRequest("Visit is awaiting boss approval")
+	}
+	if err := ensurePeriodOpen(ctx, s.payoutRepo, clinicID, visit.DoctorID, visit.Date); err != nil {
+		return nil, err
+	}
 
-	// Get services and calculate totals
+	// Get services and calculate totals. Lines saved in the draft keep
+	// the price they were added at.
+	snapshot := visit.Services
 	visit.Services = []models.VisitService{}
 	serviceIDs := make([]primitive.ObjectID, 0, len(dto.Services))
-	serviceQuantities := make(map[string]int)
+	lineIDs := make([]primitive.ObjectID, 0, len(dto.Services))
 
 	for _, svc := range dto.Services {
 		serviceID, err := primitive.ObjectIDFromHex(svc.ServiceID)
 		if err != nil {
 			return nil, apperrors.BadRequest("Invalid service ID: " + svc.ServiceID)
 		}
-		serviceIDs = append(serviceIDs, serviceID)
-		serviceQuantities[svc.ServiceID] = svc.Quantity
+		lineIDs = append(lineIDs, serviceID)
+		if !containsObjectID(serviceIDs, serviceID) {
+			serviceIDs = append(serviceIDs, serviceID)
+		}
 	}
 
 	// Fetch all services at once
@@ -135,15 +189,34 @@ func (s *VisitService) CompleteVisit(ctx context.Context, id, clinicID primitive
 	if len(services) != len(serviceIDs) {
 		return nil, apperrors.BadRequest("One or more services not found")
 	}
+	if err := s.priceServices(ctx, visit, snapshot, services); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to resolve service prices", err)
+	}
 
+	byID := make(map[primitive.ObjectID]models.Service, len(services))
 	for _, svc := range services {
-		quantity := serviceQuantities[svc.ID.Hex()]
-		visit.Services = append(visit.Services, models.VisitService{
-			ServiceID:   svc.ID,
-			ServiceName: svc.Name,
-			Price:       svc.Price,
-			Quantity:    quantity,
-		})
+		byID[svc.ID] = svc
+	}
+	for i, item := range dto.Services {
+		line, err := visitLine(byID[lineIDs[i]], item)
+		if err != nil {
+			return nil, err
+		}
+		visit.Services = append(visit.Services, line)
+	}
+
+	if dto.LabInvoices != nil {
+		if visit.LabInvoices, err = labInvoices(dto.LabInvoices); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkLabInvoices(visit); err != nil {
+		return nil, err
+	}
+	if dto.ChartChanges != nil {
+		if visit.ChartChanges, err = chartChanges(dto.ChartChanges); err != nil {
+			return nil, err
+		}
 	}
 
 	// Set discount
@@ -154,8 +227,9 @@ func (s *VisitService) CompleteVisit(ctx context.Context, id, clinicID primitive
 	if visit.DiscountType == "fixed" {
 		// Calculate subtotal first to validate
 		subtotal := 0.0
-		for _, svc := range visit.Services {
-			subtotal += svc.Price * float64(svc.Quantity)
+		for i := range visit.Services {
+			visit.Services[i].Calculate()
+			subtotal += visit.Services[i].Subtotal
 		}
 		if visit.DiscountValue > subtotal {
 			return nil, apperrors.InvalidDiscount("Discount cannot exceed subtotal")
@@ -166,18 +240,21 @@ func (s *VisitService) CompleteVisit(ctx context.Context, id, clinicID primitive
 		}
 	}
 
-	// Get doctor share from active contract
-	today := time.Now().Format("2006-01-02")
+	// Get doctor share from the contract active on the clinic's current day
+	today := s.clock.Today(ctx, clinicID)
 	contract, err := s.contractRepo.FindActiveByDoctor(ctx, clinicID, visit.DoctorID, today)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			// No active contract found, default to 0% share
+			contract = nil
 			visit.DoctorShare = 0
+			visit.DeductCosts = false
 		} else {
 			return nil, apperrors.InternalWithErr("Failed to fetch doctor contract", err)
 		}
 	} else {
 		visit.DoctorShare = contract.SharePercentage
+		visit.DeductCosts = contract.DeductCosts
 	}
 
 	// Calculate totals
@@ -188,27 +265,264 @@ func (s *VisitService) CompleteVisit(ctx context.Context, id, clinicID primitive
 		return nil, apperrors.InvalidDiscount("Total cannot be negative")
 	}
 
+	if contract != nil {
+		if err := s.applyCommission(ctx, visit, contract); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update visit status
 	visit.Diagnosis = dto.Diagnosis
 	visit.Notes = dto.Notes
 	visit.AffectedTeeth = dto.AffectedTeeth
 	visit.XRayImages = dto.XRayImages
 	visit.PaymentType = dto.PaymentType
+	visit.Approval = nil
+
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Clinic")
+	}
+	if policy := clinic.Discounts; policy != nil {
+		if reduction := roundMoney(visit.MaxReductionPercent()); reduction > policy.MaxDoctorDiscount {
+			visit.Status = models.VisitStatusPendingApproval
+			visit.Approval = &models.VisitApproval{
+				Discount:    reduction,
+				MaxDiscount: policy.MaxDoctorDiscount,
+				RequestedAt: time.Now().UTC(),
+			}
+			ok, err := s.visitRepo.UpdateIfStatus(ctx, visit, models.VisitStatusStarted)
+			if err != nil {
+				return nil, apperrors.InternalWithErr("Failed to submit visit for approval", err)
+			}
+			if !ok {
+				return nil, apperrors.Conflict("Visit was changed concurrently, please reload")
+			}
+			if dto.ChartChanges != nil {
+				if err := s.applyChart(ctx, visit); err != nil {
+					return nil, err
+				}
+			}
+			return visit, nil
+		}
+	}
+
+	if err := s.finalize(ctx, visit, models.VisitStatusStarted); err != nil {
+		return nil, err
+	}
+	if dto.ChartChanges != nil {
+		if err := s.applyChart(ctx, visit); err != nil {
+			return nil, err
+		}
+	}
+	return visit, nil
+}
+
+// applyChart writes the visit's chart changes to the patient's tooth chart,
+// replacing those saved with an earlier draft
+func (s *VisitService) applyChart(ctx context.Context, visit *models.Visit) error {
+	if err := s.chartRepo.ReplaceForVisit(ctx, visit.ClinicID, visit.ID, chartEntries(visit)); err != nil {
+		return apperrors.InternalWithErr("Failed to update tooth chart", err)
+	}
+	return nil
+}
+
+// finalize completes a visit in the given status: splits off the insurer's
+// part, numbers its invoice, consumes its materials from stock and completes
+// the linked appointment
+func (s *VisitService) finalize(ctx context.Context, visit *models.Visit, from string) error {
+	if err := s.splitClaim(ctx, visit); err != nil {
+		return err
+	}
+
 	visit.Status = models.VisitStatusCompleted
 	now := time.Now().UTC()
 	visit.CompletedAt = &now
 
-	if err := s.visitRepo.Update(ctx, visit); err != nil {
-		return nil, apperrors.InternalWithErr("Failed to complete visit", err)
+	invoiceNumber, err := s.counterRepo.Next(ctx, visit.ClinicID, models.CounterInvoice)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to number invoice", err)
 	}
+	visit.InvoiceNumber = invoiceNumber
+
+	ok, err := s.visitRepo.UpdateIfStatus(ctx, visit, from)
+	if err != nil {
+		return apperrors.InternalWithErr("Failed to complete visit", err)
+	}
+	if !ok {
+		return apperrors.Conflict("Visit was changed concurrently, please reload")
+	}
+
+	s.inventory.ConsumeForVisit(ctx, visit)
 
 	// Update appointment status if linked
 	if visit.AppointmentID != nil {
-		if err := s.appointmentRepo.UpdateStatus(ctx, *visit.AppointmentID, clinicID, models.AppointmentStatusCompleted); err != nil {
+		if err := s.appointmentRepo.TransitionStatus(ctx, *visit.AppointmentID, visit.ClinicID, models.AppointmentStatusInProgress, models.AppointmentStatusCompleted, visit.DoctorID); err != nil {
 			// Log error but don't fail the visit
 		}
 	}
+	return nil
+}
+
+// splitClaim bills the payer's part of the visit under the patient's policy
+// in force on the visit date, within the policy's per-visit and annual limits
+func (s *VisitService) splitClaim(ctx context.Context, visit *models.Visit) error {
+	visit.Claim = nil
+	patient, err := s.patientRepo.GetByID(ctx, visit.PatientID, visit.ClinicID)
+	if err != nil {
+		return apperrors.NotFound("Patient")
+	}
+	policy := patient.PolicyOn(visit.Date)
+	if policy == nil || visit.Total <= 0 {
+		return nil
+	}
+	if payer, err := s.payerRepo.GetByID(ctx, policy.PayerID, visit.ClinicID); err != nil || !payer.IsActive {
+		return nil
+	}
+
+	amount := visit.Total * policy.CoveragePercent / 100
+	if policy.MaxPerVisit > 0 && amount > policy.MaxPerVisit {
+		amount = policy.MaxPerVisit
+	}
+	if policy.AnnualLimit > 0 {
+		year := visit.Date[:4]
+		used, err := s.visitRepo.SumClaimsByPolicy(ctx, visit.ClinicID, policy.ID, year+"-01-01", year+"-12-31")
+		if err != nil {
+			return apperrors.InternalWithErr("Failed to check insurance limit", err)
+		}
+		if left := policy.AnnualLimit - used; amount > left {
+			amount = left
+		}
+	}
+	amount = roundMoney(amount)
+	if amount < models.MoneyEpsilon {
+		return nil
+	}
+
+	visit.Claim = &models.VisitClaim{
+		PayerID:         policy.PayerID,
+		PolicyID:        policy.ID,
+		PolicyNumber:    policy.PolicyNumber,
+		CoveragePercent: policy.CoveragePercent,
+		Amount:          amount,
+		Status:          models.ClaimStatusPending,
+	}
+	return nil
+}
+
+// GetDiscountPolicy returns the clinic's limit on doctor discounts
+func (s *VisitService) GetDiscountPolicy(ctx context.Context, clinicID primitive.ObjectID) (*models.DiscountPolicyResponse, error) {
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Clinic")
+	}
+	resp := &models.DiscountPolicyResponse{}
+	if clinic.Discounts != nil {
+		limit := clinic.Discounts.MaxDoctorDiscount
+		resp.MaxDoctorDiscount = &limit
+	}
+	return resp, nil
+}
+
+// UpdateDiscountPolicy sets or removes the clinic's limit on doctor discounts.
+// Visits already waiting for approval are not re-evaluated.
+func (s *VisitService) UpdateDiscountPolicy(ctx context.Context, clinicID primitive.ObjectID, dto models.UpdateDiscountPolicyDTO) (*models.DiscountPolicyResponse, error) {
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Clinic")
+	}
+
+	clinic.Discounts = nil
+	if dto.MaxDoctorDiscount != nil {
+		clinic.Discounts = &models.DiscountPolicy{MaxDoctorDiscount: *dto.MaxDoctorDiscount}
+	}
+	if err := s.clinicRepo.Update(ctx, clinic); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to update discount policy", err)
+	}
+	return &models.DiscountPolicyResponse{MaxDoctorDiscount: dto.MaxDoctorDiscount}, nil
+}
+
+// ListPendingApproval returns visits held for boss approval, oldest first
+func (s *VisitService) ListPendingApproval(ctx context.Context, clinicID primitive.ObjectID) ([]models.VisitResponse, error) {
+	visits, err := s.visitRepo.ListByStatus(ctx, clinicID, models.VisitStatusPendingApproval)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list visits", err)
+	}
 
+	responses := make([]models.VisitResponse, 0, len(visits))
+	for _, v := range visits {
+		resp := v.ToResponse()
+		if patient, err := s.patientRepo.GetByID(ctx, v.PatientID, clinicID); err == nil {
+			resp.PatientName = patient.FirstName + " " + patient.LastName
+		}
+		if doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, v.DoctorID, clinicID); err == nil {
+			resp.DoctorName = doctor.FirstName + " " + doctor.LastName
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// Approve completes a visit held for approval with the lines as submitted
+func (s *VisitService) Approve(ctx context.Context, id, clinicID, bossID primitive.ObjectID, dto models.VisitApprovalDecisionDTO) (*models.Visit, error) {
+	visit, err := s.pendingVisit(ctx, id, clinicID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	visit.Approval.Decision = models.ApprovalDecisionApproved
+	visit.Approval.DecidedBy = &bossID
+	visit.Approval.DecidedAt = &now
+	visit.Approval.Note = strings.TrimSpace(dto.Note)
+
+	if err := s.finalize(ctx, visit, models.VisitStatusPendingApproval); err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+// Reject returns a visit held for approval to the doctor as a draft; the
+// note tells the doctor what to change
+func (s *VisitService) Reject(ctx context.Context, id, clinicID, bossID primitive.ObjectID, dto models.VisitApprovalDecisionDTO) (*models.Visit, error) {
+	note := strings.TrimSpace(dto.Note)
+	if note == "" {
+		return nil, apperrors.BadRequest("A note is required to reject a visit")
+	}
+
+	visit, err := s.pendingVisit(ctx, id, clinicID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	visit.Status = models.VisitStatusStarted
+	visit.Approval.Decision = models.ApprovalDecisionRejected
+	visit.Approval.DecidedBy = &bossID
+	visit.Approval.DecidedAt = &now
+	visit.Approval.Note = note
+
+	ok, err := s.visitRepo.UpdateIfStatus(ctx, visit, models.VisitStatusPendingApproval)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to reject visit", err)
+	}
+	if !ok {
+		return nil, apperrors.Conflict("Visit was changed concurrently, please reload")
+	}
+	return visit, nil
+}
+
+func (s *VisitService) pendingVisit(ctx context.Context, id, clinicID primitive.ObjectID) (*models.Visit, error) {
+	visit, err := s.visitRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Visit")
+	}
+	if visit.Status != models.VisitStatusPendingApproval || visit.Approval == nil {
+		return nil, apperrors.BadRequest("Visit is not awaiting approval")
+	}
+	if err := ensurePeriodOpen(ctx, s.payoutRepo, clinicID, visit.DoctorID, visit.Date); err != nil {
+		return nil, err
+	}
 	return visit, nil
 }
 
@@ -261,6 +575,12 @@ func (s *VisitService) SaveDraft(ctx context.Context, id, clinicID primitive.Obj
 	if visit.Status == models.VisitStatusCompleted {
 		return nil, apperrors.BadRequest("Cannot edit completed visit")
 	}
+	if visit.Status == models.VisitStatusPendingApproval {
+		return nil, apperrors.BadRequest("Visit is awaiting boss approval")
+	}
+	if err := ensurePeriodOpen(ctx, s.payoutRepo, clinicID, visit.DoctorID, visit.Date); err != nil {
+		return nil, err
+	}
 
 	// Update diagnosis
 	if dto.Diagnosis != "" {
@@ -275,6 +595,13 @@ func (s *VisitService) SaveDraft(ctx context.Context, id, clinicID primitive.Obj
 		visit.AffectedTeeth = dto.AffectedTeeth
 	}
 
+	// Update tooth chart changes
+	if dto.ChartChanges != nil {
+		if visit.ChartChanges, err = chartChanges(dto.ChartChanges); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update plan steps
 	if dto.PlanSteps != nil {
 		visit.PlanSteps = dto.PlanSteps
@@ -296,52 +623,247 @@ func (s *VisitService) SaveDraft(ctx context.Context, id, clinicID primitive.Obj
 		visit.PaymentType = dto.PaymentType
 	}
 
-	// Update services if provided
+	// Update services if provided. A service keeps the price it had when it
+	// was first added to the draft.
 	if len(dto.Services) > 0 {
+		snapshot := visit.Services
 		visit.Services = []models.VisitService{}
 		serviceIDs := make([]primitive.ObjectID, 0, len(dto.Services))
-		serviceQuantities := make(map[string]int)
+		lineIDs := make([]primitive.ObjectID, len(dto.Services))
 
-		for _, svc := range dto.Services {
+		for i, svc := range dto.Services {
 			serviceID, err := primitive.ObjectIDFromHex(svc.ServiceID)
 			if err != nil {
 				continue // Skip invalid service IDs
 			}
-			serviceIDs = append(serviceIDs, serviceID)
-			serviceQuantities[svc.ServiceID] = svc.Quantity
+			lineIDs[i] = serviceID
+			if !containsObjectID(serviceIDs, serviceID) {
+				serviceIDs = append(serviceIDs, serviceID)
+			}
 		}
 
 		if len(serviceIDs) > 0 {
 			services, err := s.serviceRepo.GetMultipleByIDs(ctx, serviceIDs, clinicID)
 			if err == nil {
+				if err := s.priceServices(ctx, visit, snapshot, services); err != nil {
+					return nil, apperrors.InternalWithErr("Failed to resolve service prices", err)
+				}
+				byID := make(map[primitive.ObjectID]models.Service, len(services))
 				for _, svc := range services {
-					quantity := serviceQuantities[svc.ID.Hex()]
-					if quantity <= 0 {
-						quantity = 1
+					byID[svc.ID] = svc
+				}
+				for i, item := range dto.Services {
+					svc, ok := byID[lineIDs[i]]
+					if !ok {
+						continue // Skip unknown services
+					}
+					line, err := visitLine(svc, item)
+					if err != nil {
+						return nil, err
 					}
-					visit.Services = append(visit.Services, models.VisitService{
-						ServiceID:   svc.ID,
-						ServiceName: svc.Name,
-						Price:       svc.Price,
-						Quantity:    quantity,
-					})
+					visit.Services = append(visit.Services, line)
 				}
 			}
 		}
+	}
 
-		// Calculate totals
-		visit.CalculateTotal()
+	if dto.LabInvoices != nil {
+		if visit.LabInvoices, err = labInvoices(dto.LabInvoices); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkLabInvoices(visit); err != nil {
+		return nil, err
 	}
 
-	visit.UpdatedAt = time.Now().UTC()
+	// Calculate totals
+	visit.CalculateTotal()
 
-	if err := s.visitRepo.Update(ctx, visit); err != nil {
+	// A completion or approval landing since the visit was read must not be
+	// undone by this stale draft
+	ok, err := s.visitRepo.UpdateIfStatus(ctx, visit, models.VisitStatusStarted)
+	if err != nil {
 		return nil, apperrors.InternalWithErr("Failed to save visit draft", err)
 	}
+	if !ok {
+		return nil, apperrors.Conflict("Visit was changed concurrently, please reload")
+	}
+	if dto.ChartChanges != nil {
+		if err := s.applyChart(ctx, visit); err != nil {
+			return nil, err
+		}
+	}
 
 	return visit, nil
 }
 
+// visitLine builds a visit line at the service's price with the requested
+// quantity, teeth, price override and discount
+func visitLine(svc models.Service, item models.AddVisitServiceDTO) (models.VisitService, error) {
+	line := models.VisitService{
+		ServiceID:     svc.ID,
+		ServiceName:   svc.Name,
+		Category:      svc.Category,
+		Price:         svc.Price,
+		Cost:          svc.Cost,
+		Quantity:      item.Quantity,
+		PriceOverride: item.PriceOverride,
+		DiscountType:  item.DiscountType,
+		Reason:        strings.TrimSpace(item.Reason),
+	}
+	if line.DiscountType != "" {
+		line.DiscountValue = item.DiscountValue
+	}
+
+	var err error
+	if line.Teeth, err = toothNumbers(item.Teeth); err != nil {
+		return line, err
+	}
+	if line.Surfaces, err = toothSurfaces(item.Surfaces); err != nil {
+		return line, err
+	}
+	if len(line.Surfaces) > 0 && len(line.Teeth) == 0 {
+		return line, apperrors.BadRequest("Surfaces need a tooth for " + svc.Name)
+	}
+	// A line naming teeth is billed once per tooth
+	if len(line.Teeth) > 0 {
+		if line.Quantity == 0 {
+			line.Quantity = len(line.Teeth)
+		} else if line.Quantity != len(line.Teeth) {
+			return line, apperrors.BadRequest(fmt.Sprintf("Quantity of %s must match the %d teeth given", svc.Name, len(line.Teeth)))
+		}
+	}
+	if line.Quantity == 0 {
+		line.Quantity = 1
+	}
+
+	if line.Adjusted() && line.Reason == "" {
+		return line, apperrors.BadRequest("A reason is required to change the price of " + svc.Name)
+	}
+	switch line.DiscountType {
+	case "percentage":
+		if line.DiscountValue > 100 {
+			return line, apperrors.InvalidDiscount("Percentage discount cannot exceed 100%")
+		}
+	case "fixed":
+		if line.DiscountValue > line.UnitPrice()*float64(line.Quantity) {
+			return line, apperrors.InvalidDiscount("Line discount cannot exceed the line amount for " + svc.Name)
+		}
+	}
+	line.Calculate()
+	return line, nil
+}
+
+// applyCommission computes the doctor's earning on each line under the
+// contract's rules and sets the visit earning to their sum. Percentage shares
+// are paid on the line's earning base (see Visit.EarningBase); lines no rule
+// matches earn the contract's flat share.
+func (s *VisitService) applyCommission(ctx context.Context, visit *models.Visit, contract *models.DoctorContract) error {
+	monthRevenue := 0.0
+	if contract.HasTiers() {
+		revenue, err := s.visitRepo.AggregateRevenueByDoctor(ctx, visit.ClinicID, visit.Date[:8]+"01", visit.Date)
+		if err != nil {
+			return apperrors.InternalWithErr("Failed to compute monthly revenue", err)
+		}
+		monthRevenue = revenue[visit.DoctorID.Hex()]
+	}
+
+	visit.DoctorEarning = 0
+	for i := range visit.Services {
+		line := &visit.Services[i]
+		rule := contract.RuleFor(line)
+		switch {
+		case rule == nil:
+			line.Earning = roundMoney(visit.EarningBase(i) * contract.SharePercentage / 100)
+			line.EarningRule = fmt.Sprintf("Contract share: %g%%", contract.SharePercentage)
+		case rule.FixedAmount != nil:
+			line.Earning = roundMoney(*rule.FixedAmount * float64(line.Quantity))
+			line.EarningRule = fmt.Sprintf("%s: %.2f per unit", ruleLabel(rule), *rule.FixedAmount)
+		default:
+			share := rule.ShareAt(monthRevenue)
+			line.Earning = roundMoney(visit.EarningBase(i) * share / 100)
+			line.EarningRule = fmt.Sprintf("%s: %g%%", ruleLabel(rule), share)
+		}
+		visit.DoctorEarning += line.Earning
+	}
+	visit.DoctorEarning = roundMoney(visit.DoctorEarning)
+	return nil
+}
+
+// ruleLabel names a commission rule in a line's earning breakdown
+func ruleLabel(rule *models.CommissionRule) string {
+	switch {
+	case rule.Name != "":
+		return rule.Name
+	case rule.Category != "":
+		return rule.Category
+	case len(rule.ServiceIDs) > 0:
+		return "Service rule"
+	default:
+		return "All services"
+	}
+}
+
+// labInvoices builds a visit's lab invoices from the request
+func labInvoices(dtos []models.LabInvoiceDTO) ([]models.LabInvoice, error) {
+	invoices := make([]models.LabInvoice, 0, len(dtos))
+	for _, dto := range dtos {
+		inv := models.LabInvoice{
+			Lab:           strings.TrimSpace(dto.Lab),
+			InvoiceNumber: strings.TrimSpace(dto.InvoiceNumber),
+			Amount:        dto.Amount,
+			Notes:         dto.Notes,
+		}
+		if dto.ServiceID != "" {
+			serviceID, err := primitive.ObjectIDFromHex(dto.ServiceID)
+			if err != nil {
+				return nil, apperrors.BadRequest("Invalid service ID on lab invoice: " + dto.ServiceID)
+			}
+			inv.ServiceID = &serviceID
+		}
+		invoices = append(invoices, inv)
+	}
+	return invoices, nil
+}
+
+// checkLabInvoices makes sure lab invoices tied to a service belong to one of
+// the visit's lines
+func checkLabInvoices(visit *models.Visit) error {
+	for _, inv := range visit.LabInvoices {
+		if inv.ServiceID == nil {
+			continue
+		}
+		found := false
+		for _, line := range visit.Services {
+			if line.ServiceID == *inv.ServiceID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return apperrors.BadRequest("Lab invoice " + inv.Lab + " refers to a service not on the visit")
+		}
+	}
+	return nil
+}
+
+// priceServices sets the price each service is billed at on the visit: the
+// price already on the visit's lines if the service was added before,
+// otherwise the price in effect on the visit date
+func (s *VisitService) priceServices(ctx context.Context, visit *models.Visit, lines []models.VisitService, services []models.Service) error {
+	if err := applyPricesAt(ctx, s.priceRepo, visit.ClinicID, visit.Date, services); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		for i := range services {
+			if services[i].ID == line.ServiceID {
+				services[i].Price = line.Price
+			}
+		}
+	}
+	return nil
+}
+
 // ListByPatient returns all visits for a specific patient
 func (s *VisitService) ListByPatient(ctx context.Context, clinicID, patientID primitive.ObjectID) ([]models.VisitResponse, error) {
 	visits, err := s.visitRepo.ListByPatient(ctx, clinicID, patientID)
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"medical-crm/internal/models"
+	"medical-crm/internal/repository"
+	apperrors "medical-crm/pkg/errors"
+	"medical-crm/pkg/logger"
+	"medical-crm/pkg/notify"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxOffersPerSlot limits how many waiting patients are offered the same freed slot;
+// the first one to accept gets it
+const maxOffersPerSlot = 3
+
+// WaitlistService manages the waitlist and offers freed slots to waiting patients
+type WaitlistService struct {
+	waitlistRepo *repository.WaitlistRepository
+	patientRepo  *repository.PatientRepository
+	userRepo     *repository.UserRepository
+	clock        *ClinicClock
+	notifier     notify.Notifier
+	offerTTL     time.Duration
+	log          *logger.Logger
+}
+
+func NewWaitlistService(
+	waitlistRepo *repository.WaitlistRepository,
+	patientRepo *repository.PatientRepository,
+	userRepo *repository.UserRepository,
+	clock *ClinicClock,
+	notifier notify.Notifier,
+	offerTTL time.Duration,
+	log *logger.Logger,
+) *WaitlistService {
+	return &WaitlistService{
+		waitlistRepo: waitlistRepo,
+		patientRepo:  patientRepo,
+		userRepo:     userRepo,
+		clock:        clock,
+		notifier:     notifier,
+		offerTTL:     offerTTL,
+		log:          log,
+	}
+}
+
+// Create adds a patient to the waitlist
+func (s *WaitlistService) Create(ctx context.Context, dto models.CreateWaitlistEntryDTO, clinicID, creatorID primitive.ObjectID) (*models.WaitlistEntry, error) {
+	patientID, err := primitive.ObjectIDFromHex(dto.PatientID)
+	if err != nil {
+		return nil, apperrors.BadRequest("Invalid patient ID")
+	}
+	if _, err := s.patientRepo.GetByID(ctx, patientID, clinicID); err != nil {
+		return nil, apperrors.NotFound("Patient")
+	}
+
+	entry := &models.WaitlistEntry{
+		ClinicID:  clinicID,
+		PatientID: patientID,
+		FromDate:  dto.FromDate,
+		ToDate:    dto.ToDate,
+		TimeFrom:  dto.TimeFrom,
+		TimeTo:    dto.TimeTo,
+		Notes:     dto.Notes,
+		CreatedBy: creatorID,
+	}
+
+	if dto.DoctorID != "" {
+		doctorID, err := primitive.ObjectIDFromHex(dto.DoctorID)
+		if err != nil {
+			return nil, apperrors.BadRequest("Invalid doctor ID")
+		}
+		doctor, err := s.userRepo.GetByIDWithClinicCheck(ctx, doctorID, clinicID)
+		if err != nil || doctor.Role != models.RoleDoctor {
+			return nil, apperrors.NotFound("Doctor")
+		}
+		entry.DoctorID = &doctorID
+	}
+
+	if _, err := time.Parse("2006-01-02", dto.FromDate); err != nil {
+		return nil, apperrors.BadRequest("Invalid from_date, expected YYYY-MM-DD")
+	}
+	if _, err := time.Parse("2006-01-02", dto.ToDate); err != nil {
+		return nil, apperrors.BadRequest("Invalid to_date, expected YYYY-MM-DD")
+	}
+	if dto.ToDate < dto.FromDate {
+		return nil, apperrors.BadRequest("to_date must not be before from_date")
+	}
+	for _, clock := range []string{dto.TimeFrom, dto.TimeTo} {
+		if clock == "" {
+			continue
+		}
+		if _, err := models.ParseClock(clock); err != nil {
+			return nil, apperrors.BadRequest(err.Error())
+		}
+	}
+
+	if err := s.waitlistRepo.Create(ctx, entry); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to add to waitlist", err)
+	}
+	return entry, nil
+}
+
+// List returns the active queue (waiting and offered), or entries of one status
+func (s *WaitlistService) List(ctx context.Context, clinicID primitive.ObjectID, status string) ([]models.WaitlistEntryResponse, error) {
+	if err := s.waitlistRepo.ExpireOffers(ctx, clinicID, time.Now().UTC()); err != nil {
+		return nil, apperrors.InternalWithErr("Failed to expire waitlist offers", err)
+	}
+
+	entries, err := s.waitlistRepo.List(ctx, clinicID, status)
+	if err != nil {
+		return nil, apperrors.InternalWithErr("Failed to list waitlist", err)
+	}
+
+	responses := make([]models.WaitlistEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		resp := e.ToResponse()
+		if patient, err := s.patientRepo.GetByID(ctx, e.PatientID, clinicID); err == nil {
+			resp.PatientName = patient.FirstName + " " + patient.LastName
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// Remove takes an entry off the waitlist
+func (s *WaitlistService) Remove(ctx context.Context, id, clinicID primitive.ObjectID) error {
+	if err := s.waitlistRepo.UpdateStatus(ctx, id, clinicID, models.WaitlistStatusCancelled); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return apperrors.NotFound("Waitlist entry")
+		}
+		return apperrors.InternalWithErr("Failed to remove waitlist entry", err)
+	}
+	return nil
+}
+
+// GetOffer returns an entry holding a valid, unexpired offer
+func (s *WaitlistService) GetOffer(ctx context.Context, id, clinicID primitive.ObjectID) (*models.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.GetByID(ctx, id, clinicID)
+	if err != nil {
+		return nil, apperrors.NotFound("Waitlist entry")
+	}
+	if entry.Status != models.WaitlistStatusOffered || entry.Offer == nil {
+		return nil, apperrors.BadRequest("Waitlist entry has no open offer")
+	}
+	if !entry.Offer.ExpiresAt.After(time.Now().UTC()) {
+		return nil, apperrors.BadRequest("Waitlist offer has expired")
+	}
+	return entry, nil
+}
+
+// MarkBooked closes an entry after its offer was booked and withdraws the
+// same slot from the other entries it was offered to
+func (s *WaitlistService) MarkBooked(ctx context.Context, entry *models.WaitlistEntry) error {
+	if err := s.waitlistRepo.UpdateStatus(ctx, entry.ID, entry.ClinicID, models.WaitlistStatusBooked); err != nil {
+		return apperrors.InternalWithErr("Failed to update waitlist entry", err)
+	}
+	if entry.Offer != nil {
+		if err := s.waitlistRepo.ReleaseOffers(ctx, entry.ClinicID, entry.Offer.AppointmentID, entry.ID); err != nil {
+			s.log.Error("Failed to release waitlist offers", err)
+		}
+	}
+	return nil
+}
+
+// Release returns an entry whose offered slot is no longer free to the waiting queue
+func (s *WaitlistService) Release(ctx context.Context, entry *models.WaitlistEntry) {
+	if err := s.waitlistRepo.UpdateStatus(ctx, entry.ID, entry.ClinicID, models.WaitlistStatusWaiting); err != nil {
+		s.log.Error("Failed to release waitlist entry", err)
+	}
+}
+
+// WithdrawOffers returns entries offered the slot of a reopened appointment to the waiting queue
+func (s *WaitlistService) WithdrawOffers(ctx context.Context, appointment *models.Appointment) {
+	if err := s.waitlistRepo.ReleaseOffers(ctx, appointment.ClinicID, appointment.ID, primitive.NilObjectID); err != nil {
+		s.log.Error("Failed to withdraw waitlist offers", err)
+	}
+}
+
+// OfferFreedSlot offers the slot of a cancelled or no-show appointment to
+// matching waitlist entries, oldest first, and notifies the patients.
+// Failures are logged and never fail the cancellation itself.
+func (s *WaitlistService) OfferFreedSlot(ctx context.Context, appointment *models.Appointment) int {
+	now := time.Now().UTC()
+	if !appointment.StartTime.After(now) {
+		return 0
+	}
+
+	if err := s.waitlistRepo.ExpireOffers(ctx, appointment.ClinicID, now); err != nil {
+		s.log.Error("Failed to expire waitlist offers", err)
+	}
+
+	loc := s.clock.Location(ctx, appointment.ClinicID)
+	start := appointment.StartTime.In(loc)
+	end := appointment.EndTime.In(loc)
+
+	entries, err := s.waitlistRepo.ListWaitingForDate(ctx, appointment.ClinicID, start.Format("2006-01-02"))
+	if err != nil {
+		s.log.Error("Failed to find waitlist entries", err)
+		return 0
+	}
+
+	expiresAt := now.Add(s.offerTTL)
+	if expiresAt.After(appointment.StartTime) {
+		expiresAt = appointment.StartTime
+	}
+	offer := models.WaitlistOffer{
+		AppointmentID: appointment.ID,
+		DoctorID:      appointment.DoctorID,
+		StartTime:     appointment.StartTime,
+		EndTime:       appointment.EndTime,
+		OfferedAt:     now,
+		ExpiresAt:     expiresAt,
+	}
+
+	offered := 0
+	for _, entry := range entries {
+		if offered >= maxOffersPerSlot {
+			break
+		}
+		if entry.PatientID == appointment.PatientID || !entry.Matches(appointment.DoctorID, start, end) {
+			continue
+		}
+
+		ok, err := s.waitlistRepo.MarkOffered(ctx, entry.ID, entry.ClinicID, offer)
+		if err != nil {
+			s.log.Error("Failed to mark waitlist entry offered", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		offered++
+
+		s.notifyOffer(ctx, &entry, start, expiresAt.In(loc))
+	}
+
+	return offered
+}
+
+func (s *WaitlistService) notifyOffer(ctx context.Context, entry *models.WaitlistEntry, start, expiresAt time.Time) {
+	patient, err := s.patientRepo.GetByID(ctx, entry.PatientID, entry.ClinicID)
+	if err != nil {
+		s.log.Error("Failed to load patient for waitlist offer", err)
+		return
+	}
+
+	msg := notify.Message{
+		To:      patient.Phone,
+		Subject: "Appointment slot available",
+		Body: fmt.Sprintf("A slot is available on %s at %s. Please contact the clinic before %s to book it.",
+			start.Format("2006-01-02"), start.Format("15:04"), expiresAt.Format("2006-01-02 15:04")),
+	}
+	if err := s.notifier.Send(ctx, msg); err != nil {
+		s.log.Error("Failed to send waitlist offer", err)
+	}
+}
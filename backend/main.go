@@ -42,6 +42,18 @@ func main() {
 		return
 	}
 
+	// Recompute stored dates in each clinic's time zone
+	if len(os.Args) > 1 && os.Args[1] == "migrate-dates" {
+		runMigrateDates(cfg, log)
+		return
+	}
+
+	// Record ledger payments for visits completed before the payments ledger
+	if len(os.Args) > 1 && os.Args[1] == "migrate-payments" {
+		runMigratePayments(cfg, log)
+		return
+	}
+
 	// Connect to MongoDB
 	db, mongoClient, err := database.Connect(cfg.MongoURI, cfg.MongoDB, cfg.MongoTimeout, log)
 	if err != nil {
@@ -62,7 +74,7 @@ func main() {
 	}
 
 	// Setup router
-	r := router.Setup(cfg, db, mongoClient, log)
+	r, scheduler := router.Setup(cfg, db, mongoClient, log)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -80,6 +92,9 @@ func main() {
 		}
 	}()
 
+	// Start background jobs
+	scheduler.Start(context.Background())
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -96,6 +111,9 @@ func main() {
 		log.Error("Server forced to shutdown", err)
 	}
 
+	// Stop background jobs before closing the database
+	scheduler.Stop()
+
 	// Disconnect from MongoDB
 	database.Disconnect(mongoClient, log)
 
@@ -229,3 +247,36 @@ func runSeed(cfg *config.Config, log *logger.Logger) {
 	fmt.Printf("  Password: %s\n", cfg.SuperadminPassword)
 	fmt.Println("\nYou can now login and create clinics.")
 }
+
+// runMigrateDates recomputes appointment and visit dates using clinic time zones
+func runMigrateDates(cfg *config.Config, log *logger.Logger) {
+	log.Info("Running migrate-dates command...")
+
+	db, mongoClient, err := database.Connect(cfg.MongoURI, cfg.MongoDB, cfg.MongoTimeout, log)
+	if err != nil {
+		log.Fatal("Failed to connect to MongoDB", err)
+	}
+	defer database.Disconnect(mongoClient, log)
+
+	if err := database.RecomputeLocalDates(context.Background(), db, log); err != nil {
+		log.Fatal("Failed to recompute dates", err)
+	}
+
+	log.Info("Date migration completed")
+}
+
+func runMigratePayments(cfg *config.Config, log *logger.Logger) {
+	log.Info("Running migrate-payments command...")
+
+	db, mongoClient, err := database.Connect(cfg.MongoURI, cfg.MongoDB, cfg.MongoTimeout, log)
+	if err != nil {
+		log.Fatal("Failed to connect to MongoDB", err)
+	}
+	defer database.Disconnect(mongoClient, log)
+
+	if err := database.BackfillVisitPayments(context.Background(), db, log); err != nil {
+		log.Fatal("Failed to backfill payments", err)
+	}
+
+	log.Info("Payment migration completed")
+}
@@ -74,10 +74,19 @@ func (c *ResendClient) SendInviteEmail(toEmail, clinicName, inviteLink string) e
 </html>
 `, clinicName, inviteLink, inviteLink)
 
+	return c.Send(toEmail, fmt.Sprintf("🏥 %s klinikasiga taklif", clinicName), htmlContent)
+}
+
+// Send delivers an HTML e-mail to a single recipient
+func (c *ResendClient) Send(toEmail, subject, htmlContent string) error {
+	if c.apiKey == "" {
+		return fmt.Errorf("RESEND_API_KEY not configured")
+	}
+
 	emailReq := EmailRequest{
 		From:    "CRM Clinic <onboarding@resend.dev>",
 		To:      []string{toEmail},
-		Subject: fmt.Sprintf("🏥 %s klinikasiga taklif", clinicName),
+		Subject: subject,
 		HTML:    htmlContent,
 	}
 
@@ -8,21 +8,26 @@ import (
 
 // Error codes
 const (
-	CodeInternal           = "INTERNAL_ERROR"
-	CodeBadRequest         = "BAD_REQUEST"
-	CodeUnauthorized       = "UNAUTHORIZED"
-	CodeForbidden          = "FORBIDDEN"
-	CodeNotFound           = "NOT_FOUND"
-	CodeConflict           = "CONFLICT"
-	CodeValidation         = "VALIDATION_ERROR"
-	CodeInvalidCredentials = "INVALID_CREDENTIALS"
-	CodeTokenExpired       = "TOKEN_EXPIRED"
-	CodeTokenInvalid       = "TOKEN_INVALID"
-	CodeInviteExpired      = "INVITE_EXPIRED"
-	CodeInviteUsed         = "INVITE_USED"
+	CodeInternal            = "INTERNAL_ERROR"
+	CodeBadRequest          = "BAD_REQUEST"
+	CodeUnauthorized        = "UNAUTHORIZED"
+	CodeForbidden           = "FORBIDDEN"
+	CodeNotFound            = "NOT_FOUND"
+	CodeConflict            = "CONFLICT"
+	CodeValidation          = "VALIDATION_ERROR"
+	CodeInvalidCredentials  = "INVALID_CREDENTIALS"
+	CodeTokenExpired        = "TOKEN_EXPIRED"
+	CodeTokenInvalid        = "TOKEN_INVALID"
+	CodeInviteExpired       = "INVITE_EXPIRED"
+	CodeInviteUsed          = "INVITE_USED"
 	CodeAppointmentConflict = "APPOINTMENT_CONFLICT"
-	CodeDiagnosisRequired  = "DIAGNOSIS_REQUIRED"
-	CodeInvalidDiscount    = "INVALID_DISCOUNT"
+	CodeDiagnosisRequired   = "DIAGNOSIS_REQUIRED"
+	CodeInvalidDiscount     = "INVALID_DISCOUNT"
+	CodeOutsideWorkingHours = "OUTSIDE_WORKING_HOURS"
+	CodeTimeOff             = "TIME_OFF"
+	CodeInvalidTransition   = "INVALID_STATUS_TRANSITION"
+	CodeRateLimited         = "RATE_LIMITED"
+	CodeInvalidCode         = "INVALID_VERIFICATION_CODE"
 )
 
 // AppError is the application error type
@@ -206,3 +211,43 @@ func InvalidDiscount(message string) *AppError {
 		HTTPStatus: http.StatusBadRequest,
 	}
 }
+
+func OutsideWorkingHours() *AppError {
+	return &AppError{
+		Code:       CodeOutsideWorkingHours,
+		Message:    "Appointment is outside the doctor's working hours",
+		HTTPStatus: http.StatusBadRequest,
+	}
+}
+
+func TimeOff(message string) *AppError {
+	return &AppError{
+		Code:       CodeTimeOff,
+		Message:    message,
+		HTTPStatus: http.StatusConflict,
+	}
+}
+
+func InvalidTransition(message string) *AppError {
+	return &AppError{
+		Code:       CodeInvalidTransition,
+		Message:    message,
+		HTTPStatus: http.StatusConflict,
+	}
+}
+
+func RateLimited(message string) *AppError {
+	return &AppError{
+		Code:       CodeRateLimited,
+		Message:    message,
+		HTTPStatus: http.StatusTooManyRequests,
+	}
+}
+
+func InvalidCode(message string) *AppError {
+	return &AppError{
+		Code:       CodeInvalidCode,
+		Message:    message,
+		HTTPStatus: http.StatusBadRequest,
+	}
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"medical-crm/pkg/email"
+)
+
+// EmailNotifier delivers messages by e-mail; Message.To is the address
+type EmailNotifier struct {
+	client *email.ResendClient
+}
+
+func NewEmailNotifier(client *email.ResendClient) *EmailNotifier {
+	return &EmailNotifier{client: client}
+}
+
+func (n *EmailNotifier) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return fmt.Errorf("email: no address")
+	}
+
+	body := strings.ReplaceAll(html.EscapeString(msg.Body), "\n", "<br>")
+	if err := n.client.Send(msg.To, msg.Subject, "<p>"+body+"</p>"); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return nil
+}
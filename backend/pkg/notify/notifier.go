@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"context"
+	"sync"
+
+	"medical-crm/pkg/logger"
+)
+
+// Message is a notification addressed to a patient or staff member
+type Message struct {
+	To      string // Phone number, chat ID or e-mail depending on the channel
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages over some channel (SMS, messenger, e-mail)
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier writes messages to the application log instead of delivering them
+type LogNotifier struct {
+	log *logger.Logger
+}
+
+func NewLogNotifier(log *logger.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	n.log.InfoWithFields("Notification", map[string]interface{}{
+		"to":      msg.To,
+		"subject": msg.Subject,
+		"body":    msg.Body,
+	})
+	return nil
+}
+
+// MemoryNotifier records messages in memory; intended for tests
+type MemoryNotifier struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryNotifier() *MemoryNotifier {
+	return &MemoryNotifier{}
+}
+
+func (n *MemoryNotifier) Send(ctx context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of all messages sent so far
+func (n *MemoryNotifier) Messages() []Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Message(nil), n.messages...)
+}
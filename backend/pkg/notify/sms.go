@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+)
+
+// SMSNotifier delivers messages through an HTTP SMS gateway. The gateway is
+// expected to accept a JSON body {"to", "from", "text"} authorised with a
+// bearer token; Message.To is the phone number.
+type SMSNotifier struct {
+	url    string
+	token  string
+	sender string
+}
+
+func NewSMSNotifier(url, token, sender string) *SMSNotifier {
+	return &SMSNotifier{url: url, token: token, sender: sender}
+}
+
+func (n *SMSNotifier) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return fmt.Errorf("sms: no phone number")
+	}
+
+	headers := map[string]string{}
+	if n.token != "" {
+		headers["Authorization"] = "Bearer " + n.token
+	}
+	payload := map[string]string{
+		"to":   msg.To,
+		"from": n.sender,
+		"text": msg.Body,
+	}
+	if _, err := postJSON(ctx, n.url, headers, payload); err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	return nil
+}
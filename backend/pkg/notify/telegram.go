@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const telegramAPI = "https://api.telegram.org"
+
+// TelegramNotifier delivers messages through a Telegram bot; Message.To is the
+// chat ID the patient shared with the bot
+type TelegramNotifier struct {
+	token string
+}
+
+func NewTelegramNotifier(token string) *TelegramNotifier {
+	return &TelegramNotifier{token: token}
+}
+
+func (n *TelegramNotifier) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return fmt.Errorf("telegram: no chat ID")
+	}
+
+	payload := map[string]string{
+		"chat_id": msg.To,
+		"text":    msg.Body,
+	}
+	body, err := postJSON(ctx, telegramAPI+"/bot"+n.token+"/sendMessage", nil, payload)
+	if err != nil {
+		// Telegram explains rejections (blocked bot, unknown chat) in the body
+		var result struct {
+			Description string `json:"description"`
+		}
+		if json.Unmarshal(body, &result) == nil && result.Description != "" {
+			return fmt.Errorf("telegram: %s", result.Description)
+		}
+		return fmt.Errorf("telegram: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package pdf
+
+import (
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// face is an embedded TrueType font. Glyph lookups are cached; the sfnt
+// buffer is not safe for concurrent use, hence the mutex.
+type face struct {
+	name string // PDF BaseFont
+	data []byte
+	font *sfnt.Font
+
+	mu     sync.Mutex
+	buf    sfnt.Buffer
+	glyphs map[rune]glyph
+
+	zipOnce sync.Once
+	zipped  []byte // Deflated data, see compressed
+}
+
+// glyph is a character's glyph ID and advance width in 1/1000 em
+type glyph struct {
+	id    uint16
+	width int
+}
+
+// The Go fonts cover Latin, Cyrillic and Greek
+var (
+	regularFace = mustFace("GoRegular", goregular.TTF)
+	boldFace    = mustFace("GoBold", gobold.TTF)
+)
+
+func mustFace(name string, data []byte) *face {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		panic("pdf: parse font " + name + ": " + err.Error())
+	}
+	return &face{name: name, data: data, font: f, glyphs: make(map[rune]glyph)}
+}
+
+func faceFor(bold bool) *face {
+	if bold {
+		return boldFace
+	}
+	return regularFace
+}
+
+// em is the scale glyph metrics are requested at, so results are in 1/1000 em
+var em = fixed.I(1000)
+
+// glyph returns the glyph of r; ok is false if the font lacks it
+func (f *face) glyph(r rune) (glyph, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if g, ok := f.glyphs[r]; ok {
+		return g, g.id != 0
+	}
+	var g glyph
+	if id, err := f.font.GlyphIndex(&f.buf, r); err == nil && id != 0 {
+		if adv, err := f.font.GlyphAdvance(&f.buf, id, em, font.HintingNone); err == nil {
+			g = glyph{id: uint16(id), width: adv.Round()}
+		}
+	}
+	f.glyphs[r] = g
+	return g, g.id != 0
+}
+
+// substitutes stand in for characters the fonts lack
+var substitutes = map[rune]rune{
+	'ʻ': '\'', 'ʼ': '\'', '‘': '\'', '’': '\'',
+	'“': '"', '”': '"',
+	'–': '-', '—': '-',
+	'\n': ' ', '\t': ' ',
+}
+
+// shape maps s to glyphs, substituting or replacing with '?' what the font
+// lacks. The runes are returned alongside for the ToUnicode map.
+func (f *face) shape(s string) ([]glyph, []rune) {
+	glyphs := make([]glyph, 0, len(s))
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		g, ok := f.glyph(r)
+		if !ok {
+			if sub, found := substitutes[r]; found {
+				r = sub
+			} else {
+				r = '?'
+			}
+			g, _ = f.glyph(r)
+		}
+		glyphs = append(glyphs, g)
+		runes = append(runes, r)
+	}
+	return glyphs, runes
+}
+
+// descriptor returns the font's bounding box, ascent, descent and cap height
+// in 1/1000 em, with the Y axis pointing up as PDF expects
+func (f *face) descriptor() (bbox [4]int, ascent, descent, capHeight int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if b, err := f.font.Bounds(&f.buf, em, font.HintingNone); err == nil {
+		// sfnt's Y axis points down
+		bbox = [4]int{b.Min.X.Floor(), -b.Max.Y.Ceil(), b.Max.X.Ceil(), -b.Min.Y.Floor()}
+	}
+	if m, err := f.font.Metrics(&f.buf, em, font.HintingNone); err == nil {
+		ascent, descent, capHeight = m.Ascent.Round(), -m.Descent.Round(), m.CapHeight.Round()
+	}
+	return bbox, ascent, descent, capHeight
+}
@@ -0,0 +1,242 @@
+// Package pdf writes simple text-and-line PDF documents. Text is set in the
+// Go fonts, embedded as TrueType with Identity-H encoding so Cyrillic and
+// other non-Latin names print as written; characters the fonts lack are
+// substituted or replaced.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document is a PDF under construction. Coordinates are in points with the
+// origin at the top-left corner of the page.
+type Document struct {
+	pages []*bytes.Buffer
+	title string
+	used  map[*face]map[uint16]rune // Glyphs drawn per font, for widths and ToUnicode
+}
+
+func New(title string) *Document {
+	d := &Document{title: title, used: make(map[*face]map[uint16]rune)}
+	d.AddPage()
+	return d
+}
+
+// AddPage starts a new page; drawing continues on it
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws s with its baseline at (x, y)
+func (d *Document) Text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	f := faceFor(bold)
+	glyphs, runes := f.shape(s)
+
+	used := d.used[f]
+	if used == nil {
+		used = make(map[uint16]rune)
+		d.used[f] = used
+	}
+	var hex strings.Builder
+	for i, g := range glyphs {
+		used[g.id] = runes[i]
+		fmt.Fprintf(&hex, "%04X", g.id)
+	}
+	fmt.Fprintf(d.page(), "BT /%s %.2f Tf %.2f %.2f Td <%s> Tj ET\n", font, size, x, PageHeight-y, hex.String())
+}
+
+// TextRight draws s so that it ends at x
+func (d *Document) TextRight(x, y, size float64, bold bool, s string) {
+	d.Text(x-TextWidth(s, size, bold), y, size, bold, s)
+}
+
+// Line draws a thin line
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// TextWidth returns the width of s in points
+func TextWidth(s string, size float64, bold bool) float64 {
+	glyphs, _ := faceFor(bold).shape(s)
+	units := 0
+	for _, g := range glyphs {
+		units += g.width
+	}
+	return float64(units) * size / 1000
+}
+
+// Truncate shortens s with an ellipsis so it fits in width
+func Truncate(s string, width, size float64, bold bool) string {
+	if TextWidth(s, size, bold) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && TextWidth(string(runes)+"...", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// Bytes renders the document
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo renders the document to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	var offsets []int
+
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	stream := func(dict string, data []byte) {
+		obj(fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data))
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Fixed objects: 1 catalog, 2 page tree, 3 info, then five per font
+	// (Type0 font, CID font, descriptor, font file, ToUnicode map); then a
+	// page and a content stream per page
+	faces := []*face{regularFace, boldFace}
+	firstFont := 4
+	firstPage := firstFont + 5*len(faces)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj(fmt.Sprintf("<< /Title %s /Producer (medical-crm) >>", textString(d.title)))
+
+	for i, f := range faces {
+		n := firstFont + 5*i
+		used := d.used[f]
+		bbox, ascent, descent, capHeight := f.descriptor()
+
+		obj(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			f.name, n+1, n+4))
+		obj(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW 1000 /W [%s] >>",
+			f.name, n+2, widths(f, used)))
+		obj(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			f.name, bbox[0], bbox[1], bbox[2], bbox[3], ascent, descent, capHeight, n+3))
+		stream(fmt.Sprintf("/Filter /FlateDecode /Length1 %d", len(f.data)), f.compressed())
+		stream("", toUnicode(used))
+	}
+
+	for i, content := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, firstFont, firstFont+5, firstPage+2*i+1))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// widths lists the advance widths of the used glyphs for a CID font's /W
+func widths(f *face, used map[uint16]rune) string {
+	ids := sortedGlyphs(used)
+	var sb strings.Builder
+	for _, id := range ids {
+		g, _ := f.glyph(used[id])
+		fmt.Fprintf(&sb, "%d [%d] ", id, g.width)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// toUnicode builds the CMap that maps used glyphs back to text, so the PDF
+// can be searched and copied from
+func toUnicode(used map[uint16]rune) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	ids := sortedGlyphs(used)
+	// A bfchar block holds at most 100 entries
+	for start := 0; start < len(ids); start += 100 {
+		chunk := ids[start:min(start+100, len(ids))]
+		fmt.Fprintf(&b, "%d beginbfchar\n", len(chunk))
+		for _, id := range chunk {
+			fmt.Fprintf(&b, "<%04X> <%s>\n", id, utf16Hex(used[id]))
+		}
+		b.WriteString("endbfchar\n")
+	}
+
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	return b.Bytes()
+}
+
+func sortedGlyphs(used map[uint16]rune) []uint16 {
+	ids := make([]uint16, 0, len(used))
+	for id := range used {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// textString encodes s as a UTF-16 PDF text string (for document metadata)
+func textString(s string) string {
+	var sb strings.Builder
+	sb.WriteString("<FEFF")
+	for _, r := range s {
+		sb.WriteString(utf16Hex(r))
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+func utf16Hex(r rune) string {
+	var sb strings.Builder
+	for _, u := range utf16.Encode([]rune{r}) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	return sb.String()
+}
+
+// compressed returns the font file deflated for embedding, computed once
+func (f *face) compressed() []byte {
+	f.zipOnce.Do(func() {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		_, _ = zw.Write(f.data)
+		_ = zw.Close()
+		f.zipped = buf.Bytes()
+	})
+	return f.zipped
+}